@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"RESPAWN/internal/i18n"
+	"RESPAWN/internal/process"
+	"RESPAWN/internal/system"
+	"RESPAWN/pkg/config"
+)
+
+// Blacklist command group
+var blacklistCmd = &cobra.Command{
+	Use:   "blacklist",
+	Short: "List apps skipped after repeated launch timeouts",
+	Long:  "Lists apps that hung during repeated restores. They are skipped by restores until blacklist_duration passes or the blacklist is cleared",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := handleBlacklist(); err != nil {
+			printFailure("Blacklist", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// Blacklist clear command
+var blacklistClearCmd = &cobra.Command{
+	Use:   "clear [app]",
+	Short: "Let restores launch blacklisted apps again",
+	Long:  "Takes one app, or every app when none is given, off the blacklist",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := ""
+		if len(args) == 1 {
+			appName = args[0]
+		}
+		if err := handleBlacklistClear(appName); err != nil {
+			printFailure("Clear", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// handleBlacklist lists blacklisted apps and when they come off the blacklist
+func handleBlacklist() error {
+	if err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("Config load failed: %w", err)
+	}
+
+	history, err := process.LoadRestoreHistory()
+	if err != nil {
+		return err
+	}
+
+	blacklist := history.Blacklist()
+	if len(blacklist) == 0 {
+		fmt.Fprintln(system.Stdout, i18n.T("blacklist.none"))
+		return nil
+	}
+
+	fmt.Fprintln(system.Stdout, i18n.T("blacklist.header"))
+	for _, appName := range blacklistedApps(blacklist) {
+		fmt.Fprintln(system.Stdout, i18n.T("blacklist.entry", appName, blacklist[appName].Format("2006-01-02 15:04")))
+	}
+	fmt.Fprintln(system.Stdout, "\n" + i18n.T("blacklist.hint"))
+	return nil
+}
+
+// handleBlacklistClear takes appName, or every app when empty, off the blacklist
+func handleBlacklistClear(appName string) error {
+	if err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("Config load failed: %w", err)
+	}
+
+	history, err := process.LoadRestoreHistory()
+	if err != nil {
+		return err
+	}
+
+	cleared := history.ClearBlacklist(appName)
+	if err := history.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintln(system.Stdout, i18n.T("blacklist.cleared", cleared))
+	return nil
+}
+
+// blacklistedApps returns the blacklisted app names sorted
+func blacklistedApps(blacklist map[string]time.Time) []string {
+	apps := make([]string, 0, len(blacklist))
+	for appName := range blacklist {
+		apps = append(apps, appName)
+	}
+	sort.Strings(apps)
+	return apps
+}
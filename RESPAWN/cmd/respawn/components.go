@@ -0,0 +1,42 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"RESPAWN/internal/types"
+	"RESPAWN/internal/ui"
+)
+
+// Notifier is the part of the notification manager the commands and daemon use
+type Notifier interface {
+	ShowError(title, message string) error
+	ShowWarning(title, message string) error
+	ShowStatus(title, message string) error
+	ShowInfo(title, message string) error
+	ShowAppRestored(appName string, timestamp time.Time) error
+	ShowRestoreComplete(summary types.RestoreSummary) error
+	ShowCheckpointFailed(status types.CheckpointStatus) error
+	ShowCheckpointSuccess(status types.CheckpointStatus) error
+	ShowRestorationProgress(current, total int, currentApp string) error
+	ShowPermissionRequest(permissionType, instructions string) (string, error)
+	ShowDiskSpaceAlert(usedPercent float64, critical bool, pruneCount int, pruneSize string, pruneArg string) (ui.PromptChoice, error)
+	ShowRestorePrompt(checkpointName, digest string) ui.PromptChoice
+	FlushDigest() error
+	ApplyConfig()
+}
+
+// lazyComponents builds rarely needed components on first use, so commands and
+// the daemon only pay for what they touch. Safe for the daemon's goroutines.
+type lazyComponents struct {
+	notifierOnce sync.Once
+	notifier     Notifier
+}
+
+// notifications returns the notification manager, creating it on first use
+func (lc *lazyComponents) notifications() Notifier {
+	lc.notifierOnce.Do(func() {
+		lc.notifier = ui.NewNotificationManager()
+	})
+	return lc.notifier
+}
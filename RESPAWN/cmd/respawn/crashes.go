@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"RESPAWN/internal/i18n"
+	"RESPAWN/internal/system"
+	"RESPAWN/pkg/config"
+)
+
+// Crashes command group
+var crashesCmd = &cobra.Command{
+	Use:   "crashes",
+	Short: "List recorded crashes",
+	Long:  "Lists recorded crashes with their reports. RESPAWN disables auto-start after crash_threshold crashes within crash_window",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := handleCrashes(); err != nil {
+			printFailure("Crashes", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// Crashes reset command
+var crashesResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Clear the crash history and re-enable auto-start",
+	Long:  "Forgets recorded crashes and clears the disabled state, so auto-start works again without reinstalling",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := handleCrashesReset(); err != nil {
+			printFailure("Reset", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// handleCrashes lists recorded crashes, newest first
+func handleCrashes() error {
+	if err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("Config load failed: %w", err)
+	}
+
+	startupMgr, err := system.NewStartupManager()
+	if err != nil {
+		return err
+	}
+
+	history := startupMgr.CrashHistory()
+	if len(history) == 0 {
+		fmt.Fprintln(system.Stdout, i18n.T("crashes.none"))
+		return nil
+	}
+
+	recent, window, disabled := startupMgr.CrashStatus()
+	fmt.Fprintln(system.Stdout, i18n.T("crashes.summary", len(history), recent, window))
+	for _, record := range history {
+		reason := record.Reason
+		if reason == "" {
+			reason = i18n.T("crashes.unknown")
+		}
+		fmt.Fprintf(system.Stdout, "   %s  %s\n", record.Time.Format("2006-01-02 15:04:05"), reason)
+		if record.Report != "" {
+			fmt.Fprintf(system.Stdout, "      %s\n", (&url.URL{Scheme: "file", Path: record.Report}).String())
+		}
+	}
+	if disabled {
+		fmt.Fprintln(system.Stdout, "\n" + i18n.T("crashes.disabled"))
+	}
+	return nil
+}
+
+// handleCrashesReset clears the crash tracker
+func handleCrashesReset() error {
+	if err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("Config load failed: %w", err)
+	}
+
+	startupMgr, err := system.NewStartupManager()
+	if err != nil {
+		return err
+	}
+
+	reenabled, err := startupMgr.ResetCrashes()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(system.Stdout, i18n.T("crashes.cleared"))
+	if reenabled {
+		fmt.Fprintln(system.Stdout, i18n.T("crashes.reenabled"))
+	}
+	return nil
+}
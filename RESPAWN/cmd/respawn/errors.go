@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"RESPAWN/internal/checkpoint"
+	"RESPAWN/internal/process"
+	"RESPAWN/internal/system"
+)
+
+// errorHint suggests a next step for the error kinds commands commonly run into
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, checkpoint.ErrCheckpointNotFound):
+		return "Run 'respawn list' to see the available checkpoints"
+	case errors.Is(err, checkpoint.ErrCorruptCheckpoint):
+		return "Run 'respawn verify --quarantine' to set damaged checkpoints aside, then restore another one"
+	case errors.Is(err, checkpoint.ErrUnverifiedCheckpoint):
+		return "Run 'respawn repair' on the machine that made the checkpoint so it gets a checksum, or restore a local one"
+	case errors.Is(err, checkpoint.ErrOtherMachine):
+		return "Pass --all-machines to restore a checkpoint from another Mac"
+	case errors.Is(err, system.ErrPermissionMissing):
+		return "Grant the permission in System Settings → Privacy & Security, then try again"
+	case errors.Is(err, process.ErrAppNotInstalled):
+		return "Reinstall the app or stop tracking it in the config"
+	case errors.Is(err, process.ErrNoRestoreQueue):
+		return "Start a new restore with 'respawn restore'"
+	case errors.Is(err, process.ErrRestoreRunning):
+		return "Wait for it to finish, or check its progress with 'respawn status'"
+	}
+	return ""
+}
+
+// printFailure reports a failed command on stderr, with a hint when the error kind has one
+func printFailure(action string, err error) {
+	fmt.Fprintf(system.Stderr, "❌ %s failed: %v\n", action, err)
+	if hint := errorHint(err); hint != "" {
+		fmt.Fprintf(system.Stderr, "💡 %s\n", hint)
+	}
+}
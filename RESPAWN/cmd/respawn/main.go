@@ -3,6 +3,9 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,20 +13,34 @@ import (
     "syscall"
     "strconv"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 
     "RESPAWN/internal/checkpoint"
-	"RESPAWN/internal/process"
+    "RESPAWN/internal/i18n"
+    "RESPAWN/internal/process"
 	"RESPAWN/internal/system"
     "RESPAWN/internal/types"
-	"RESPAWN/internal/ui"
+    "RESPAWN/internal/ui"
+	"RESPAWN/internal/workspace"
 	"RESPAWN/pkg/config"
 )
 
 
+// eventFlushTimeout bounds how long exiting waits for webhook deliveries
+const eventFlushTimeout = 10 * time.Second
+
+// shutdownTimeout bounds how long exiting waits for an in-flight checkpoint or maintenance run
+const shutdownTimeout = 30 * time.Second
+
+// restorePromptHold caps how long scheduled checkpoints wait on an unanswered or
+// snoozed restore prompt; the snoozed prompt still finds its checkpoint by ID
+const restorePromptHold = 15 * time.Minute
+
 const (
 	Version = "v1.0.0-beta"
 	Copyright = "© 2024 NINSCO GLOBAL RESOURCES LTD. All rights reserved."
@@ -36,13 +53,31 @@ type RESPAWNApp struct {
 	startupManager      *system.StartupManager
     monitor            *system.SystemMonitor
     checkpointManager  *checkpoint.CheckpointManager
-    notificationManager *ui.NotificationManager
-    launcher           *process.ApplicationLauncher
-    detector           *process.ProcessDetector
+    apiServer          *system.APIServer
+    pprofServer        *system.APIServer
+    lazyComponents     // Notifier and launcher, built on first use
     
     startTime          time.Time
-    lastCheckpointTime time.Time
     isRunning          bool
+
+    // lastCheckpointTime is set by the checkpoint handler and the directory watcher
+    // and read on shutdown, each from its own goroutine
+    lastCheckpointMu   sync.Mutex
+    lastCheckpointTime time.Time
+}
+
+// setLastCheckpointTime records when the newest checkpoint was taken
+func (a *RESPAWNApp) setLastCheckpointTime(t time.Time) {
+    a.lastCheckpointMu.Lock()
+    defer a.lastCheckpointMu.Unlock()
+    a.lastCheckpointTime = t
+}
+
+// getLastCheckpointTime returns when the newest checkpoint was taken
+func (a *RESPAWNApp) getLastCheckpointTime() time.Time {
+    a.lastCheckpointMu.Lock()
+    defer a.lastCheckpointMu.Unlock()
+    return a.lastCheckpointTime
 }
 
 var (
@@ -52,6 +87,33 @@ var (
     silentMode   bool
     forceMode    bool
     checkpointID string
+    assumeYes    bool
+    purgeData    bool
+    guiSetup     bool
+    installBinary string // Binary the LaunchAgent runs, instead of this one
+    installDryRun bool
+    installSystem bool // Install or uninstall for every user, for MDM deployments
+    rawOutput    bool
+    checkpointApps string
+    checkpointNote string
+    pauseDuration  time.Duration
+    jsonOutput     bool
+    includeManual  bool
+    continueRestore bool
+    restoreLatest  bool
+    foregroundMode bool
+    fastList       bool
+    allMachines    bool
+    compressionLevel int
+    quarantineFiles  bool
+    rebuildAll       bool
+    pruneFree        string
+    showTimings      bool
+    dryRun           bool
+    profileName      string
+    plainMode        bool
+    quietMode        bool
+    verboseMode      bool
 )
 
 // Root command
@@ -60,16 +122,43 @@ var rootCmd = &cobra.Command{
     Short:   "RESPAWN - Automatic workspace restoration",
     Long:    buildWelcomeMessage(),
     Version: Version,
+    // --profile is passed on through the environment so every config load sees it
+    PersistentPreRun: func(cmd *cobra.Command, args []string) {
+        if profileName != "" {
+            os.Setenv(config.ProfileEnv, profileName)
+        }
+        if plainMode || system.PlainOutputRequested() {
+            system.SetPlainOutput()
+        }
+        if quietMode {
+            system.SetQuietOutput()
+        }
+        if verboseMode {
+            if err := system.SetLogMirror(system.Stderr); err != nil {
+                fmt.Fprintf(system.Stderr, "⚠️  Verbose logging unavailable: %v\n", err)
+            }
+        }
+    },
 }
 
 // Install command
 var installCmd = &cobra.Command{
     Use:   "install",
     Short: "Install RESPAWN auto-start",
-    Long:  "Sets up RESPAWN to start automatically on system login",
+    Long:  `Sets up RESPAWN to start automatically on system login.
+
+Use --binary when respawn lives somewhere else than the binary you run, e.g. after
+moving it; running install again rewrites a LaunchAgent that points at an old path.
+--dry-run prints the plist to stdout without installing anything, for review or
+for deploying through MDM.
+
+--system installs for every user of the Mac: a LaunchAgent in /Library/LaunchAgents
+starts RESPAWN in each user's session, and /Library/Application Support/respawn/config.json
+is the config users start from. Checkpoints and logs stay in each user's home. Don't
+combine it with a per-user install.`,
     Run: func(cmd *cobra.Command, args []string) {
         if err := handleInstall(); err != nil {
-            fmt.Printf("❌ Installation failed: %v\n", err)
+            printFailure("Installation", err)
             os.Exit(1)
         }
     },
@@ -82,7 +171,7 @@ var uninstallCmd = &cobra.Command{
     Long:  "Removes RESPAWN from auto-start",
     Run: func(cmd *cobra.Command, args []string) {
         if err := handleUninstall(); err != nil {
-            fmt.Printf("❌ Uninstall failed: %v\n", err)
+            printFailure("Uninstall", err)
             os.Exit(1)
         }
     },
@@ -95,7 +184,7 @@ var startCmd = &cobra.Command{
     Long:  "Starts RESPAWN in background monitoring mode",
     Run: func(cmd *cobra.Command, args []string) {
         if err := handleStart(); err != nil {
-            fmt.Printf("❌ Start failed: %v\n", err)
+            printFailure("Start", err)
             os.Exit(1)
         }
     },
@@ -105,10 +194,12 @@ var startCmd = &cobra.Command{
 var restoreCmd = &cobra.Command{
     Use:   "restore",
     Short: "Restore workspace from checkpoint",
-    Long:  "Restores applications from the latest or specified checkpoint",
+    Long:  `Restores applications from the latest or specified checkpoint. Run in a terminal
+without --checkpoint, it offers the latest checkpoints to choose from; --latest skips the choice.`,
     Run: func(cmd *cobra.Command, args []string) {
         if err := handleRestore(); err != nil {
-            fmt.Printf("❌ Restore failed: %v\n", err)
+            printFailure("Restore", err)
+            system.FlushEvents(eventFlushTimeout)
             os.Exit(1)
         }
     },
@@ -121,7 +212,8 @@ var checkpointCmd = &cobra.Command{
     Long:  "Forces creation of a checkpoint now",
     Run: func(cmd *cobra.Command, args []string) {
         if err := handleCheckpoint(); err != nil {
-            fmt.Printf("❌ Checkpoint failed: %v\n", err)
+            printFailure("Checkpoint", err)
+            system.FlushEvents(eventFlushTimeout)
             os.Exit(1)
         }
     },
@@ -134,7 +226,7 @@ var statusCmd = &cobra.Command{
     Long:  "Displays current RESPAWN status and statistics",
     Run: func(cmd *cobra.Command, args []string) {
         if err := handleStatus(); err != nil {
-            fmt.Printf("❌ Status check failed: %v\n", err)
+            printFailure("Status check", err)
             os.Exit(1)
         }
     },
@@ -147,7 +239,7 @@ var enableCmd = &cobra.Command{
     Long:  "Re-enables RESPAWN auto-start on system login",
     Run: func(cmd *cobra.Command, args []string) {
         if err := handleEnableAutoStart(); err != nil {
-            fmt.Printf("❌ Enable failed: %v\n", err)
+            printFailure("Enable", err)
             os.Exit(1)
         }
     },
@@ -160,7 +252,7 @@ var disableCmd = &cobra.Command{
     Long:  "Disables RESPAWN auto-start without uninstalling",
     Run: func(cmd *cobra.Command, args []string) {
         if err := handleDisableAutoStart(); err != nil {
-            fmt.Printf("❌ Disable failed: %v\n", err)
+            printFailure("Disable", err)
             os.Exit(1)
         }
     },
@@ -173,7 +265,7 @@ var pauseCmd = &cobra.Command{
     Long:  "Temporarily pauses checkpoint creation",
     Run: func(cmd *cobra.Command, args []string) {
         if err := handlePause(); err != nil {
-            fmt.Printf("❌ Pause failed: %v\n", err)
+            printFailure("Pause", err)
             os.Exit(1)
         }
     },
@@ -186,21 +278,220 @@ var resumeCmd = &cobra.Command{
     Long:  "Resumes checkpoint creation after pause",
     Run: func(cmd *cobra.Command, args []string) {
         if err := handleResume(); err != nil {
-            fmt.Printf("❌ Resume failed: %v\n", err)
+            printFailure("Resume", err)
+            os.Exit(1)
+        }
+    },
+}
+
+// Purge command
+var purgeCmd = &cobra.Command{
+    Use:   "purge",
+    Short: "Delete all RESPAWN data",
+    Long:  "Deletes all checkpoints, metadata, logs, learning data, teams, templates and marker files. Files are overwritten before removal, but on APFS that doesn't erase the old contents, so this isn't secure deletion",
+    Run: func(cmd *cobra.Command, args []string) {
+        if err := handlePurge(); err != nil {
+            printFailure("Purge", err)
+            os.Exit(1)
+        }
+    },
+}
+
+// Prune command
+var pruneCmd = &cobra.Command{
+    Use:   "prune",
+    Short: "Delete old checkpoints to free disk space",
+    Long:  "Deletes the oldest checkpoints until the requested space is freed. The newest and last restored checkpoints are kept.",
+    Run: func(cmd *cobra.Command, args []string) {
+        if err := handlePrune(); err != nil {
+            printFailure("Prune", err)
+            os.Exit(1)
+        }
+    },
+}
+
+// Doctor command
+var doctorCmd = &cobra.Command{
+    Use:   "doctor",
+    Short: "Diagnose permissions and limitations",
+    Long:  "Checks macOS permissions and explains which features are limited without them",
+    Run: func(cmd *cobra.Command, args []string) {
+        if err := handleDoctor(); err != nil {
+            printFailure("Doctor", err)
+            os.Exit(1)
+        }
+    },
+}
+
+// Show command
+var showCmd = &cobra.Command{
+    Use:   "show <checkpoint-id>",
+    Short: "Show a checkpoint",
+    Long:  "Displays the contents of a checkpoint. Use --raw to print it as pretty JSON, e.g. respawn show <id> --raw > cp.json",
+    Args:  cobra.ExactArgs(1),
+    Run: func(cmd *cobra.Command, args []string) {
+        if err := handleShow(args[0]); err != nil {
+            printFailure("Show", err)
+            os.Exit(1)
+        }
+    },
+}
+
+// List command
+var listCmd = &cobra.Command{
+    Use:   "list",
+    Short: "List checkpoints",
+    Long:  "Lists this Mac's checkpoints newest first. --json --fast reads metadata only and is meant for launcher extensions (Raycast, Alfred)",
+    Args:  cobra.NoArgs,
+    Run: func(cmd *cobra.Command, args []string) {
+        if err := handleList(); err != nil {
+            printFailure("List", err)
+            os.Exit(1)
+        }
+    },
+}
+
+// Storage command group
+var storageCmd = &cobra.Command{
+    Use:   "storage",
+    Short: "Manage checkpoint storage",
+    Long:  "Maintenance commands for stored checkpoints",
+}
+
+// Storage recompress command
+var recompressCmd = &cobra.Command{
+    Use:   "recompress",
+    Short: "Recompress checkpoints at a new level",
+    Long:  "Re-encodes existing compressed checkpoints at a new zstd level with low CPU priority and reports the space saved",
+    Run: func(cmd *cobra.Command, args []string) {
+        if err := handleRecompress(); err != nil {
+            printFailure("Recompress", err)
+            os.Exit(1)
+        }
+    },
+}
+
+// Verify command
+var verifyCmd = &cobra.Command{
+    Use:   "verify",
+    Short: "Check checkpoint integrity",
+    Long:  "Validates every checkpoint's checksum and metadata and reports corrupt or orphaned files",
+    Run: func(cmd *cobra.Command, args []string) {
+        if err := handleVerify(); err != nil {
+            printFailure("Verify", err)
+            os.Exit(1)
+        }
+    },
+}
+
+// Repair command
+var repairCmd = &cobra.Command{
+    Use:   "repair",
+    Short: "Rebuild checkpoint metadata",
+    Long:  "Regenerates missing or stale checkpoint metadata by reading each checkpoint file",
+    Run: func(cmd *cobra.Command, args []string) {
+        if err := handleRepair(); err != nil {
+            printFailure("Repair", err)
             os.Exit(1)
         }
     },
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Use this profile's checkpoints (default: profile from config, or $"+config.ProfileEnv+")")
+	rootCmd.PersistentFlags().BoolVar(&plainMode, "plain", false, "Plain output without emoji, box art or ANSI styling (also set by $NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVarP(&quietMode, "quiet", "q", false, "Only print errors")
+	rootCmd.PersistentFlags().BoolVar(&verboseMode, "verbose", false, "Mirror log lines, including DEBUG, to stderr as they are written")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+
 	// Add flags to restore command
 	restoreCmd.Flags().BoolVarP(&silentMode, "silent", "s", false, "Restore silently without progress display")
-	restoreCmd.Flags().StringVarP(&checkpointID, "checkpoint", "c", "", "Restore from specific checkpoint ID")
+	restoreCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the restore summary as JSON")
+	restoreCmd.Flags().BoolVar(&includeManual, "include-manual", false, "Also launch apps marked manual restore only after repeated failures")
+	restoreCmd.Flags().BoolVar(&continueRestore, "continue", false, "Finish a restore interrupted by sleep, a crash or a kill")
+	restoreCmd.Flags().StringVarP(&checkpointID, "checkpoint", "c", "", "Restore from a checkpoint ID, unique ID prefix or note")
+	restoreCmd.Flags().BoolVar(&restoreLatest, "latest", false, "Restore the latest checkpoint without asking")
+	restoreCmd.Flags().BoolVar(&allMachines, "all-machines", false, "Also offer, and allow restoring, checkpoints taken on other Macs")
 
 	// Add flags to checkpoint command 
 	checkpointCmd.Flags().BoolVarP(&forceMode, "force", "f", false, "Force checkpoint even under high CPU/low battery")
+	checkpointCmd.Flags().StringVar(&checkpointApps, "apps", "", "Only snapshot these apps, comma-separated (e.g. \"Chrome,iTerm2\")")
+	checkpointCmd.Flags().StringVarP(&checkpointNote, "message", "m", "", "Attach a note to the checkpoint (e.g. \"state before demo\")")
+
+	// Add flags to install command
+	startCmd.Flags().BoolVar(&foregroundMode, "foreground", false, "Run in the foreground instead of detaching, for service managers such as brew services")
+	installCmd.Flags().BoolVar(&guiSetup, "gui-setup", false, "Use the dialog-based setup instead of the terminal wizard")
+	installCmd.Flags().StringVar(&installBinary, "binary", "", "Point the LaunchAgent at this respawn binary instead of the running one")
+	installCmd.Flags().BoolVar(&installDryRun, "dry-run", false, "Print the LaunchAgent plist that would be written and change nothing")
+	installCmd.Flags().BoolVar(&installSystem, "system", false, "Install for every user in /Library, for MDM deployments (needs sudo)")
+	uninstallCmd.Flags().BoolVar(&installSystem, "system", false, "Remove the system-wide install (needs sudo)")
+
+	// Add flags to uninstall command
+	uninstallCmd.Flags().BoolVar(&purgeData, "purge", false, "Also delete all checkpoints, logs, learning data, teams and templates")
+	uninstallCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Skip the confirmation prompt")
+
+	// Add flags to purge command
+	purgeCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Skip the confirmation prompt")
+
+
+
+	// Add flags to status command
+	statusCmd.Flags().BoolVar(&showTimings, "timings", false, "Show how long recent daemon startups took, per phase")
+	statusCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print status as JSON")
+
+	// Add flags to prune command
+	pruneCmd.Flags().StringVar(&pruneFree, "free", "", "Space to free, e.g. 1GB or 800MB (required)")
+	pruneCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be deleted without deleting")
+	pruneCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Skip the confirmation prompt")
+	pruneCmd.MarkFlagRequired("free")
+
+	// Add flags to show command
+	showCmd.Flags().BoolVar(&rawOutput, "raw", false, "Print the checkpoint as pretty JSON")
+
+	// Add flags to list command
+	listCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print checkpoint summaries as JSON")
+	listCmd.Flags().BoolVar(&fastList, "fast", false, "Read metadata only (checkpoints missing metadata are skipped)")
+	listCmd.Flags().BoolVar(&allMachines, "all-machines", false, "Include checkpoints taken on other Macs syncing the checkpoint directory")
+
+	// Add flags to storage commands
+	recompressCmd.Flags().IntVar(&compressionLevel, "level", 0, "zstd compression level 1-22 (default: compression_level from config)")
+	recompressCmd.Flags().BoolVarP(&forceMode, "force", "f", false, "Recompress even on low battery")
+	storageCmd.AddCommand(recompressCmd)
+
+	// Add flags to verify command
+	verifyCmd.Flags().BoolVar(&quarantineFiles, "quarantine", false, "Move corrupt and orphaned files to the quarantine directory")
 
+	// Add flags to repair command
+	repairCmd.Flags().BoolVar(&rebuildAll, "all", false, "Rebuild metadata for every checkpoint, not just missing or stale ones")
 
+	// Add flags to pause command
+	pauseCmd.Flags().DurationVar(&pauseDuration, "for", 0, "Resume automatically after this long (e.g. 2h)")
+
+	// Template subcommands
+	templateExportCmd.Flags().StringVarP(&templateOutput, "output", "o", "", "Write the template to this path instead of the templates folder")
+	templateSuggestCmd.Flags().StringVarP(&templateOutput, "output", "o", "", "Write the template to this path instead of the templates folder")
+	templateSuggestCmd.Flags().IntVar(&suggestAppCount, "apps", 5, "Maximum number of apps to include")
+	templateCmd.AddCommand(templateExportCmd, templateSuggestCmd, templateListCmd)
+
+	// Crash tracking subcommands
+	crashesCmd.AddCommand(crashesResetCmd)
+
+	// Blacklist subcommands
+	blacklistCmd.AddCommand(blacklistClearCmd)
+
+	// Service manager subcommands
+	servicePlistCmd.Flags().StringVar(&serviceBinary, "binary", "", "Binary path to run, instead of the detected Homebrew path")
+	servicePlistCmd.Flags().StringVar(&serviceLabel, "label", system.HomebrewServiceLabel, "launchd label")
+	servicePlistCmd.Flags().StringVarP(&serviceOutput, "output", "o", "", "Write the plist to this path instead of stdout")
+	serviceCmd.AddCommand(servicePlistCmd)
+
+	// URL scheme subcommands
+	urlSchemeCmd.AddCommand(urlSchemeInstallCmd, urlSchemeUninstallCmd)
+
+	// Team subcommands
+	teamJoinCmd.Flags().StringVar(&teamOwnerKey, "owner-key", "", "The team owner's public key, as they gave it to you")
+	teamJoinCmd.MarkFlagRequired("owner-key")
+	teamCmd.AddCommand(teamCreateCmd, teamInviteCmd, teamJoinCmd, teamAcceptCmd, teamActivateCmd, teamLeaveCmd, teamListCmd)
 
 	// Add all commands to root
 	rootCmd.AddCommand(installCmd)
@@ -213,12 +504,33 @@ func init() {
 	rootCmd.AddCommand(disableCmd)
 	rootCmd.AddCommand(pauseCmd)
 	rootCmd.AddCommand(resumeCmd)
+	rootCmd.AddCommand(purgeCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(storageCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(repairCmd)
+	rootCmd.AddCommand(teamCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(templateCmd)
+	rootCmd.AddCommand(urlCmd)
+	rootCmd.AddCommand(urlSchemeCmd)
+	rootCmd.AddCommand(crashesCmd)
+	rootCmd.AddCommand(blacklistCmd)
+	rootCmd.AddCommand(serviceCmd)
 }
 
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	err := rootCmd.Execute()
+
+	// Let webhooks for what this command did go out before exiting
+	system.FlushEvents(eventFlushTimeout)
+
+	if err != nil {
+		fmt.Fprintln(system.Stdout, err)
 		os.Exit(1)
 	}
 }
@@ -248,7 +560,7 @@ and restores it after system restarts or crashes.
 // initializeComponents starts all RESPAWN components in correct order
 func initializeComponents() error {
     system.Info("Initializing RESPAWN components...")
-    initStart := time.Now()
+    timer := system.NewPhaseTimer()
 
     // Phase 1: Logger (already initialized by system.Info call above)
     system.Debug("Logger initialized ✓")
@@ -264,11 +576,10 @@ func initializeComponents() error {
         system.Info("Config auto-fixed successfully ✓")
 
         // Show notification about auto-fix 
-        if app.notificationManager != nil {
-            app.notificationManager.ShowError("Configuration Reset", "Config was reset to defaults")
-        }
+        app.notifications().ShowWarning("Configuration Reset", "Config was reset to defaults")
     }
     system.Debug("Configuration loaded ✓")
+    timer.Mark("config")
 
     // Phase 3: Startup Manager and permissions
     startupMgr, err := system.NewStartupManager()
@@ -276,8 +587,20 @@ func initializeComponents() error {
         return fmt.Errorf("Startup manager initialization failed: %w", err)
     }
     app.startupManager = startupMgr
+    system.SetPanicHandler(startupMgr.RecordPanic)
     system.Debug("Startup manager initialized ✓")
 
+    // Refuses to start after repeated crashes, then takes the single-instance lock
+    // (the daemon writes its own lock and PID file, so the PID is the one to signal)
+    // and checks permissions; missing ones put us in degraded mode
+    if err := app.startupManager.StartWithPolicy(); err != nil {
+        return err
+    }
+    if system.IsDegradedMode() {
+        system.Warn("Running in degraded mode - checkpoints will be basic (no window state)")
+    }
+    timer.Mark("permissions")
+
     // Phase 4: Storage and Checkpoint Manager
     checkpointMgr, err := checkpoint.NewCheckpointManager()
     if err != nil {
@@ -285,33 +608,27 @@ func initializeComponents() error {
     }
     app.checkpointManager = checkpointMgr
     system.Debug("Checkpoint manager initialized ✓")
+    go applyBackupExclusions() // tmutil is slow, keep it out of the startup budget
+    timer.Mark("storage")
 
-    // Phase 5: Process Detection
-    app.detector = process.NewProcessDetector()
-    system.Debug("Process detector initialized ✓")
-
-    // Phase 6: Application Launcher
-    app.launcher = process.NewApplicationLauncher()
-    system.Debug("Application launcher initialized ✓")
-
-    // Phase 7: System Monitor
+    // Phase 5: System Monitor
     monitor, err := system.NewSystemMonitor()
     if err != nil {
         return fmt.Errorf("System monitor initialization failed: %w", err)
     }
     app.monitor = monitor
     system.Debug("System monitor initialized ✓")
+    timer.Mark("monitor")
 
-    // Phase 8: Notification Manager
-    app.notificationManager = ui.NewNotificationManager()
-    system.Debug("Notification manager initialized ✓")
+    // The notifier and launcher are built on first use
 
-    duration := time.Since(initStart)
-    system.Info("All components initialized in", duration)
+    timing := timer.Finish()
+    app.monitor.RecordStartup(timing)
+    system.Info("All components initialized in", timing.Total)
 
     // Log warning if initialization took too long, but continue
-    if duration.Seconds() > 8 {
-        system.Warn("Initialization exceeded 8-seconds target:", duration)
+    if timing.OverBudget() {
+        system.Warn("Initialization exceeded", system.StartupBudget, "target:", timing.Total)
     }
     return nil
 }
@@ -358,6 +675,35 @@ func autoFixConfig(origErr error) error {
 func handleInstall() error {
     system.Info("Starting RESPAWN installation")
 
+    binary, err := installBinaryPath()
+    if err != nil {
+        return err
+    }
+
+    // Show what would be installed without touching the system
+    if installDryRun && installSystem {
+        fmt.Fprintln(system.Stderr, "Would write", system.SystemLaunchAgentPath)
+        return system.WriteSystemLaunchAgentPlist(system.Stdout, binary)
+    }
+    if installDryRun {
+        fmt.Fprintln(system.Stderr, "Would write", system.LaunchAgentPath())
+        return system.WriteLaunchAgentPlist(system.Stdout, system.LaunchAgentLabel(), binary, "start")
+    }
+
+    // Fleet installs skip the per-user setup, each user gets it on first start
+    if installSystem {
+        if err := system.InstallSystemWide(binary); err != nil {
+            return err
+        }
+        fmt.Fprintln(system.Stdout, "✅ RESPAWN installed for all users")
+        fmt.Fprintln(system.Stdout, "✅ Starts for each user at their next login")
+        fmt.Fprintln(system.Stdout, "✅ Default settings:", config.SystemConfigTemplate)
+        return nil
+    }
+    if system.SystemWideInstalled() {
+        return fmt.Errorf("RESPAWN is installed for all users of this Mac already (%s)", system.SystemLaunchAgentPath)
+    }
+
     // Check if first run
     if isFirstRun() {
         if err := showFirstTimeExperience(); err != nil {
@@ -373,24 +719,87 @@ func handleInstall() error {
         return fmt.Errorf("Startup manager creation failed: %w", err)
     }
     app.startupManager = startupMgr
+    app.startupManager.SetExecutablePath(binary)
+
+    // Walk through missing permissions instead of failing at first start
+    if isTerminal() {
+        if err := app.startupManager.GuidePermissionSetup(5 * time.Minute); err != nil {
+            return fmt.Errorf("Permission setup incomplete: %w", err)
+        }
+    }
+
+    // Exercise the Apple Events we need once, so prompts appear now and not mid-checkpoint
+    fmt.Fprintln(system.Stdout, "Checking permissions (macOS may ask you to allow access)...")
+    if cache, err := app.startupManager.RunPermissionPreflight(); err != nil {
+        system.Warn("Permission pre-flight failed:", err)
+    } else if !cache.States[system.PermissionAutomation] {
+        fmt.Fprintln(system.Stdout, "⚠️  Automation access to System Events was denied - window state will not be captured")
+    }
 
     // Install auto-start
     if err := app.startupManager.Install(); err != nil {
         return fmt.Errorf("Installation failed: %w", err)
     }
 
-    fmt.Println("✅ RESPAWN installed successfully!")
-    fmt.Println("✅ Auto-start configured")
-    fmt.Println("✅ Will start on next login")
-    fmt.Println("\nRun 'respawn start' to start now, or restart your system.")
+    // Keep churning checkpoint blobs out of backups and the Spotlight index
+    if err := config.LoadConfig(); err != nil {
+        system.Warn("Config load failed:", err)
+    }
+    applyBackupExclusions()
+
+    // Let Shortcuts and Focus automations drive RESPAWN through respawn:// links
+    if err := installURLScheme(); err != nil {
+        system.Warn("URL scheme registration failed:", err)
+        fmt.Fprintln(system.Stdout, "⚠️  respawn:// links are unavailable - retry with 'respawn url-scheme install'")
+    }
+
+    fmt.Fprintln(system.Stdout, "✅ RESPAWN installed successfully!")
+    fmt.Fprintln(system.Stdout, "✅ Auto-start configured")
+    fmt.Fprintln(system.Stdout, "✅ Will start on next login")
+    fmt.Fprintln(system.Stdout, "\nRun 'respawn start' to start now, or restart your system.")
     
     return nil
 }
 
+// installBinaryPath returns the absolute path of the binary the LaunchAgent should
+// run: --binary, checked to be an executable file, or the running one
+func installBinaryPath() (string, error) {
+    if installBinary == "" {
+        executablePath, err := os.Executable()
+        if err != nil {
+            return "", fmt.Errorf("failed to locate respawn binary: %w", err)
+        }
+        return executablePath, nil
+    }
+
+    binary, err := filepath.Abs(installBinary)
+    if err != nil {
+        return "", err
+    }
+    info, err := os.Stat(binary)
+    if err != nil {
+        return "", fmt.Errorf("--binary: %w", err)
+    }
+    if info.IsDir() || info.Mode().Perm()&0111 == 0 {
+        return "", fmt.Errorf("--binary: %s is not an executable file", binary)
+    }
+    return binary, nil
+}
+
 //handleUninstall processes the uninstall command
 func handleUninstall() error {
     system.Info("Starting RESPAWN uninstall....")
 
+    // Users' data is theirs to purge, only the shared agent goes
+    if installSystem {
+        if err := system.UninstallSystemWide(); err != nil {
+            return err
+        }
+        fmt.Fprintln(system.Stdout, "✅ RESPAWN uninstalled for all users")
+        fmt.Fprintln(system.Stdout, "Note: Settings template kept in", config.SystemConfigDir)
+        return nil
+    }
+
     app = &RESPAWNApp{}
 
     startupMgr, err := system.NewStartupManager()
@@ -400,13 +809,44 @@ func handleUninstall() error {
 
     app.startupManager = startupMgr
 
+    // Data and log dirs may be configured elsewhere
+    if err := config.LoadConfig(); err != nil {
+        system.Debug("Config load failed, using the default locations:", err)
+    }
+
+    if purgeData && !assumeYes {
+        fmt.Fprintln(system.Stdout, "--purge will permanently delete all checkpoints, logs, learning data, teams and templates")
+        if !confirmTyped("Type 'yes' to continue: ") {
+            fmt.Fprintln(system.Stdout, "Uninstall cancelled")
+            return nil
+        }
+    }
+
     if err := app.startupManager.Uninstall(); err != nil {
         return fmt.Errorf("uninstall failed: %w", err)
     }
+    if err := system.UninstallURLHandler(); err != nil {
+        system.Warn("Failed to remove URL handler:", err)
+    }
+
+    fmt.Fprintln(system.Stdout, "✅ RESPAWN uninstalled successfully")
+
+    if !purgeData {
+        fmt.Fprintf(system.Stdout, "Note: RESPAWN data preserved in %s\n", strings.Join(purgeDirs(), ", "))
+        return nil
+    }
+
+    deleted, err := system.PurgeData(purgeDirs()...)
+    if err != nil {
+        return fmt.Errorf("purge failed: %w", err)
+    }
+    deleted = append(deleted, app.startupManager.PurgeStateFiles()...)
+
+    for _, path := range deleted {
+        fmt.Fprintf(system.Stdout, "  deleted %s\n", path)
+    }
+    fmt.Fprintf(system.Stdout, "✅ Purged %d files\n", len(deleted))
 
-    fmt.Println("✅ RESPAWN uninstalled successfully")
-    fmt.Println("Note: Checkpoint data preserved in ~/.respawn/")
-    
     return nil
 }
 
@@ -414,10 +854,21 @@ func handleUninstall() error {
 func handleStart() error {
     system.Info("Starting RESPAWN")
 
-    // Always  daemonize on start
-    if err := daemonize(); err != nil {
-        return fmt.Errorf("Failed to daemonize: %w", err)
+    // The running check and the daemon's output logs use the configured data and log
+    // dirs; a broken config is left for initializeComponents to repair
+    if err := config.LoadConfig(); err != nil {
+        system.Debug("Config load failed before daemonizing:", err)
+    }
+
+    // Daemonize on start, unless a service manager or the user keeps it in the foreground
+    if !foregroundMode {
+        if err := daemonize(); err != nil {
+            return fmt.Errorf("Failed to daemonize: %w", err)
+        }
+    } else if pid, running := runningDaemonPID(); running {
+        return fmt.Errorf("RESPAWN is already running (PID: %d)", pid)
     }
+    defer system.RecordPanics()
     app = &RESPAWNApp{
         startTime: time.Now(),
         isRunning: true,
@@ -434,64 +885,282 @@ func handleStart() error {
 
     // Show RESPAWN ACTIVE notification (regardless of init time)
     system.Info("System stabilized, showing active notification")
-    if err := app.notificationManager.ShowError("RESPAWN Active", "Monitoring workspace"); err != nil {
+    if err := app.notifications().ShowStatus("RESPAWN Active", "Monitoring workspace"); err != nil {
         system.Warn("Failed to show active notification:", err)
     }
 
+    // Let the monitor drive checkpoints and maintenance
+    app.monitor.SetCheckpointHandler(func(trigger string) error {
+        cp, err := app.checkpointManager.CreateCheckpoint(trigger)
+        if err != nil {
+            app.notifications().ShowCheckpointFailed(types.CheckpointStatus{
+                Success:      false,
+                Timestamp:    time.Now(),
+                ErrorMessage: err.Error(),
+            })
+            return err
+        }
+        app.setLastCheckpointTime(cp.Timestamp)
+        app.monitor.RecordAppUsage(cp.AppNames)
+        return nil
+    })
+    app.monitor.SetMaintenanceHandler(app.checkpointManager.PerformMaintenanceTasks)
+    app.checkpointManager.SetMetricsRecorder(app.monitor)
+    app.monitor.SetCompressionEstimator(app.checkpointManager.EstimateCompressionSavings)
+    app.monitor.SetConfigChangeHandler(applyConfigReload)
+    app.checkpointManager.SetDiskAlertHandler(func(status checkpoint.DiskStatus, plan *checkpoint.PrunePlan) {
+        if system.Snoozed(system.SnoozeDiskAlert) {
+            return
+        }
+        // The critical dialog waits for an answer, so it must not hold up maintenance
+        go func() {
+            defer system.RecordPanics()
+            pruneArg := fmt.Sprintf("%dMB", plan.Bytes>>20+1)
+            choice, _ := app.notifications().ShowDiskSpaceAlert(status.UsedPercent, status.Critical,
+                len(plan.Checkpoints), checkpoint.FormatBytes(plan.Bytes), pruneArg)
+            switch choice {
+            case ui.ChoiceAccept:
+                // Prune exactly the plan the dialog showed
+                if _, err := app.checkpointManager.Prune(plan); err != nil {
+                    system.Warn("Failed to prune from the disk alert:", err)
+                }
+            case ui.ChoiceSnooze:
+                if err := system.Snooze(system.SnoozeDiskAlert, ""); err != nil {
+                    system.Warn("Failed to snooze disk alert:", err)
+                }
+            }
+        }()
+    })
+
+    // Restore after a restart, asking first unless auto_restore is on
+    app.monitor.SetRestartHandler(func() {
+        if config.Current().AutoRestore {
+            restoreAfterRestart("")
+            return
+        }
+        promptRestore("")
+    })
+
+    // Notifications held back by quiet hours go out as one digest when they end
+    app.monitor.SetQuietHoursEndHandler(func() {
+        if err := app.notifications().FlushDigest(); err != nil {
+            system.Warn("Failed to show notification digest:", err)
+        }
+    })
+
+    // "Remind Me Later" comes back here once the snooze runs out
+    app.monitor.SetSnoozeHandler(func(due system.DueSnooze) {
+        switch due.Kind {
+        case system.SnoozeRestorePrompt:
+            promptRestore(due.Subject)
+        case system.SnoozeDiskAlert:
+            if err := app.checkpointManager.AlertDiskSpace(); err != nil {
+                system.Warn("Disk space check failed:", err)
+            }
+        default:
+            system.Warn("Dropping unknown snoozed notification:", due.Kind)
+        }
+    })
+
+    // Scheduled launches restore a checkpoint as a workspace bootstrap
+    app.monitor.SetScheduledLaunchHandler(func(launch config.ScheduledLaunch) error {
+        var results []types.LaunchResult
+        var err error
+        start := time.Now()
+        if launch.Template != "" {
+            var t *workspace.Template
+            if t, err = workspace.Load(launch.Template); err == nil {
+                results, err = t.Apply(nil)
+            }
+        } else if launch.Checkpoint == "latest" {
+            results, err = app.checkpointManager.RestoreLatestCheckpoint()
+        } else {
+            results, err = app.checkpointManager.RestoreFromCheckpoint(launch.Checkpoint)
+        }
+        if err != nil {
+            message := fmt.Sprintf("%s: %v", launch.Name, err)
+            if hint := errorHint(err); hint != "" {
+                message += "\n" + hint
+            }
+            app.notifications().ShowError("Scheduled Launch Failed", message)
+            return err
+        }
+
+        summary := process.SummarizeRestore(results, start, time.Now())
+        if len(summary.NewlyManualOnlyApps) > 0 {
+            app.notifications().ShowWarning("Manual Restore Only",
+                i18n.T("notify.manual_only_marked", strings.Join(summary.NewlyManualOnlyApps, ", ")))
+        }
+        if len(summary.NewlyBlacklistedApps) > 0 {
+            app.notifications().ShowWarning("Apps Blacklisted",
+                i18n.T("notify.blacklisted", strings.Join(summary.NewlyBlacklistedApps, ", ")))
+        }
+        system.Info("Scheduled launch", launch.Name, "opened", summary.SuccessfulApps, "apps")
+        return nil
+    })
+
+    // Say how long the Mac slept, so a wake isn't mistaken for a crash or restart
+    app.monitor.SetWakeHandler(func(period system.SleepPeriod) {
+        if period.End.IsZero() {
+            return
+        }
+        app.notifications().ShowInfo("Welcome Back",
+            i18n.T("notify.woke", period.Start.Format("15:04"), period.End.Format("15:04"), i18n.Duration(period.Duration())))
+    })
+
+    // Show pause and resume as soon as the daemon sees them
+    app.monitor.SetPauseHandler(func(paused bool, until time.Time) {
+        switch {
+        case !paused:
+            app.notifications().ShowStatus(i18n.T("notify.resumed_title"), i18n.T("notify.resumed"))
+        case until.IsZero():
+            app.notifications().ShowStatus(i18n.T("notify.paused_title"), i18n.T("notify.paused"))
+        default:
+            app.notifications().ShowStatus(i18n.T("notify.paused_title"), i18n.T("notify.paused_until", until.Format("15:04")))
+        }
+    })
+
+    // Exit non-zero so launchd's KeepAlive brings up a fresh process
+    app.monitor.SetResourceLimitHandler(func(reason string) {
+        system.Error("Restarting due to resource limit:", reason)
+        system.Emit(system.EventDaemonCrashed, map[string]interface{}{"reason": reason})
+        system.FlushEvents(eventFlushTimeout)
+        cleanup()
+        os.Exit(1)
+    })
+
     // Start monitoring 
     if err := app.monitor.Start(); err != nil {
         return fmt.Errorf("monitor start failed: %w", err)
     }
 
+    // Local API lets GUIs follow events without polling
+    if addr := config.Current().APIAddress; addr != "" {
+        apiServer, err := system.StartAPIServer(addr)
+        if err != nil {
+            system.Warn("Local API disabled:", err)
+        } else {
+            app.apiServer = apiServer
+        }
+    }
+
+    // Profiling for "RESPAWN is using CPU" reports, opt-in through debug.pprof
+    if config.Current().Debug.PProf {
+        pprofServer, err := system.StartPprofServer(config.Current().Debug.PProfAddress)
+        if err != nil {
+            system.Warn("pprof disabled:", err)
+        } else {
+            app.pprofServer = pprofServer
+        }
+    }
+
+    // Pick up checkpoints added or removed by sync tools and other machines
+    if _, err := app.checkpointManager.WatchCheckpoints(func(list *checkpoint.CheckpointList) {
+        if len(list.Checkpoints) > 0 {
+            app.setLastCheckpointTime(list.Checkpoints[0].Timestamp)
+        } else {
+            app.setLastCheckpointTime(time.Time{})
+        }
+    }); err != nil {
+        system.Warn("Not watching checkpoint directory:", err)
+    }
+
     // Setup graceful shutdown
     setupGracefulShutdown()
+    setupControlSignals()
 
     system.Info("RESPAWN is now running...")
-    system.Info("Next checkpoint in:", config.GlobalConfig.CheckpointInterval)
+    system.Info("Next checkpoint in:", config.Current().CheckpointInterval)
 
     // Keep running until interrupted
     select{}
 }
 
-// daemonize forks the process and exits the parent
-func daemonize() error {
-    // Check if already a daemon
-    if os.Getppid() == 1 {
-        return nil // Already daemonized
+// promptRestore asks whether to restore a checkpoint, the latest one when checkpointID
+// is empty, snoozing the question when the user picks Remind Me Later. The checkpoint
+// is pinned by ID through the snooze, and scheduled checkpoints are held until the
+// question is answered, so the one restored is the one that was offered.
+func promptRestore(checkpointID string) {
+    list, err := app.checkpointManager.GetAvailableCheckpoints()
+    if err != nil || len(list.Checkpoints) == 0 {
+        system.Info("No checkpoint to offer for restore")
+        app.monitor.ReleaseCheckpoints()
+        return
     }
-    // Fork the process
-    cmd := exec.Command(os.Args[0], os.Args[1:]...)
-    cmd.Stdout = nil
-    cmd.Stderr = nil
-    cmd.Stdin = nil
 
-    if err := cmd.Start(); err != nil {
-        return err
+    offered := &list.Checkpoints[0]
+    if checkpointID != "" {
+        offered = nil
+        for i := range list.Checkpoints {
+            if list.Checkpoints[i].ID == checkpointID {
+                offered = &list.Checkpoints[i]
+                break
+            }
+        }
+        if offered == nil {
+            system.Info("Snoozed restore checkpoint is gone:", checkpointID)
+            app.monitor.ReleaseCheckpoints()
+            return
+        }
     }
-    // Parent exits, child continues
-    fmt.Printf("RESPAWN started in background (PID: %d)\n", cmd.Process.Pid)
-    os.Exit(0)
 
-    return nil
+    app.monitor.HoldCheckpoints("waiting for an answer to the restore prompt", restorePromptHold)
+    switch app.notifications().ShowRestorePrompt(offered.Timestamp.Format("Mon Jan 02 15:04"), checkpoint.Digest(offered)) {
+    case ui.ChoiceAccept:
+        restoreAfterRestart(offered.ID)
+    case ui.ChoiceSnooze:
+        if err := system.Snooze(system.SnoozeRestorePrompt, offered.ID); err != nil {
+            // Nothing will ask again, so nothing would release the hold
+            system.Warn("Failed to snooze restore prompt:", err)
+            break
+        }
+        return // Still unanswered, until the hold lapses
+    default:
+        system.Info("Restore declined")
+    }
+    app.monitor.ReleaseCheckpoints()
 }
 
-// Helper to check if running in background
-func isBackgroundMode() bool {
-    // Checks if parent process is launchd (PID 1)
-    return os.Getppid() == 1
+// restoreAfterRestart restores a checkpoint from the daemon, the latest one when
+// checkpointID is empty, and shows the summary
+func restoreAfterRestart(checkpointID string) {
+    start := time.Now()
+    var results []types.LaunchResult
+    var err error
+    if checkpointID == "" {
+        results, err = app.checkpointManager.RestoreLatestCheckpoint()
+    } else {
+        results, err = app.checkpointManager.RestoreFromCheckpoint(checkpointID)
+    }
+    if err != nil {
+        message := err.Error()
+        if hint := errorHint(err); hint != "" {
+            message += "\n" + hint
+        }
+        app.notifications().ShowError("Restore Failed", message)
+        return
+    }
+    app.notifications().ShowRestoreComplete(process.SummarizeRestore(results, start, time.Now()))
 }
 
-// Start process in background
-func startInBackground() error {
-    cmd := exec.Command(os.Args[0], "start", "--background")
-    cmd.Stdout = nil
-    cmd.Stderr = nil
+// daemonize starts a detached `respawn start` and exits the parent. The child, and
+// a daemon started by launchd, carry on as the daemon.
+func daemonize() error {
+    if system.IsDaemon() {
+        return nil // Already daemonized
+    }
+
+    if pid, running := runningDaemonPID(); running {
+        return fmt.Errorf("RESPAWN is already running (PID: %d)", pid)
+    }
 
-    if err := cmd.Start(); err != nil {
-        return fmt.Errorf("Failed to start in background: %w", err)
+    // Only the command is passed on - --profile travels in the environment
+    pid, err := system.Daemonize("start")
+    if err != nil {
+        return err
     }
 
-    fmt.Printf("✅ RESPAWN started in background (PID: %d)\n", cmd.Process.Pid)
+    fmt.Fprintf(system.Stdout, "✅ RESPAWN started in background (PID: %d)\n", pid)
     os.Exit(0)
     return nil
 }
@@ -516,14 +1185,78 @@ func handleRestore() error {
         return fmt.Errorf("Checkpoint manager creation failed: %w", err)
     }
     app.checkpointManager = checkpointMgr
-
-    app.launcher = process.NewApplicationLauncher()
-    app.notificationManager = ui.NewNotificationManager()
+    app.checkpointManager.SetAllMachines(allMachines)
 
     var results []types.LaunchResult
 
-    // Restore from specific checkpoint or latest
-    if checkpointID != "" {
+    // Progress is printed as each app launches, and mirrored to notifications unless
+    // silent. With --json, stdout is kept for the summary.
+    var notifier Notifier
+    if !silentMode {
+        notifier = app.notifications()
+    }
+    progressOut := system.Stdout
+    if jsonOutput {
+        progressOut = system.Stderr
+    }
+    if restoreLatest && checkpointID != "" {
+        return fmt.Errorf("--latest and --checkpoint can't be combined")
+    }
+
+    // Let people at a terminal choose instead of silently taking the latest
+    pickedNewest := false
+    if checkpointID == "" && !restoreLatest && !continueRestore && !jsonOutput && !silentMode && isTerminal() {
+        picked, newest, err := pickCheckpoint(app.checkpointManager)
+        if errors.Is(err, errRestoreCancelled) {
+            fmt.Fprintln(system.Stdout, "Restore cancelled")
+            return nil
+        }
+        if err != nil {
+            return err
+        }
+        checkpointID = picked
+        pickedNewest = newest
+    }
+
+    app.checkpointManager.SetRestoreProgressHandler(restoreProgress(notifier, progressOut))
+    app.checkpointManager.SetIncludeManualOnly(includeManual)
+    // Restoring the newest checkpoint loses nothing worth a checkpoint first, picked or not
+    app.checkpointManager.SetCheckpointBeforeRestore(checkpointID != "" && !continueRestore && !pickedNewest)
+
+    // Two restores at once would launch every app twice
+    if queue, err := process.LoadRestoreQueue(); err == nil && queue.Running() {
+        return fmt.Errorf("%w: process %d is restoring %s", process.ErrRestoreRunning, queue.PID, queue.Source)
+    }
+
+    // Starting over replaces the queue of an interrupted restore, so mention it first
+    if !continueRestore {
+        if queue, err := process.LoadRestoreQueue(); err == nil {
+            fmt.Fprintln(progressOut, i18n.T("restore.interrupted", queue.Source, len(queue.Remaining)))
+        }
+
+        // Apps updated since the checkpoint may not take their old session back
+        if changes, err := app.checkpointManager.PreviewVersionChanges(checkpointID); err == nil {
+            for _, change := range changes {
+                key := "restore.upgraded"
+                if change.Major {
+                    key = "restore.upgraded_major"
+                }
+                fmt.Fprintln(progressOut, i18n.T(key, change.App, change.From, change.To))
+            }
+        }
+    }
+
+    // Continue an interrupted restore, or restore from specific checkpoint or latest
+    start := time.Now()
+    if continueRestore {
+        if checkpointID != "" {
+            return fmt.Errorf("--continue resumes the interrupted restore and can't be combined with --checkpoint")
+        }
+        if queue, err := process.LoadRestoreQueue(); err == nil {
+            fmt.Fprintln(progressOut, i18n.T("restore.continuing", queue.Source, len(queue.Remaining)))
+        }
+        results, err = app.checkpointManager.ContinueRestore()
+    } else if checkpointID != "" {
         system.Info("Restoring from checkpoint:", checkpointID)
         results, err = app.checkpointManager.RestoreFromCheckpoint(checkpointID)
     } else {
@@ -535,31 +1268,64 @@ func handleRestore() error {
         return fmt.Errorf("Restoration failed: %w", err)
     }
 
-    // Show progress (unless silent mode)
+    summary := process.SummarizeRestore(results, start, time.Now())
     if !silentMode {
-        for _, result := range results {
-            if result.Success {
-                app.notificationManager.ShowAppRestored(result.AppName, result.LaunchTime)
-            }
+        app.notifications().ShowRestoreComplete(summary)
+    }
+
+    if jsonOutput {
+        data, err := json.MarshalIndent(summary, "", "  ")
+        if err != nil {
+            return err
         }
+        fmt.Fprintln(system.Stdout, string(data))
+        return nil
     }
 
-    // Show summary
-    successful, failed, failedApps := app.launcher.GetLaunchSummary()
+    fmt.Fprintln(system.Stdout, i18n.T("restore.done", summary.SuccessfulApps, i18n.Duration(summary.TotalDuration)))
+    if summary.FailedApps > 0 {
+        fmt.Fprintln(system.Stdout, i18n.T("restore.failed", summary.FailedApps))
+    }
+    if len(summary.AlreadyRunningApps) > 0 {
+        fmt.Fprintln(system.Stdout, i18n.T("restore.running", strings.Join(summary.AlreadyRunningApps, ", ")))
+    }
+    if len(summary.ReopenedByMacOSApps) > 0 {
+        fmt.Fprintln(system.Stdout, i18n.T("restore.reopened", strings.Join(summary.ReopenedByMacOSApps, ", ")))
+    }
+    if len(summary.ManualOnlyApps) > 0 {
+        fmt.Fprintln(system.Stdout, i18n.T("restore.manual_only", strings.Join(summary.ManualOnlyApps, ", ")))
+    }
+    if len(summary.NewlyManualOnlyApps) > 0 {
+        fmt.Fprintln(system.Stdout, i18n.T("restore.manual_only_marked", config.Current().ManualRestoreAfter, strings.Join(summary.NewlyManualOnlyApps, ", ")))
+    }
+    if len(summary.BlacklistedApps) > 0 {
+        fmt.Fprintln(system.Stdout, i18n.T("restore.blacklisted", strings.Join(summary.BlacklistedApps, ", ")))
+    }
+    if len(summary.NewlyBlacklistedApps) > 0 {
+        fmt.Fprintln(system.Stdout, i18n.T("restore.blacklisted_now", i18n.Duration(config.Current().BlacklistDuration), strings.Join(summary.NewlyBlacklistedApps, ", ")))
+    }
 
-    if !silentMode {
-        summary := types.RestoreSummary{
-            TotalApps:      successful + failed,
-            SuccessfulApps: successful,
-            FailedApps:     failed,
-            FailedAppNames: failedApps,
+    if len(summary.NotInstalledApps) > 0 {
+        fmt.Fprintln(system.Stdout, "\n" + i18n.T("restore.skipped"))
+        for _, appName := range summary.NotInstalledApps {
+            if hint, ok := summary.InstallHints[appName]; ok {
+                fmt.Fprintf(system.Stdout, "   - %s (%s)\n", appName, hint)
+            } else {
+                fmt.Fprintf(system.Stdout, "   - %s\n", appName)
+            }
         }
-        app.notificationManager.ShowRestoreComplete(summary)
     }
 
-    fmt.Printf("✅ Restored %d applications\n", successful)
-    if failed > 0 {
-        fmt.Printf("⚠️  %d applications failed to restore\n", failed)
+    if len(summary.ListeningPorts) > 0 {
+        fmt.Fprintln(system.Stdout, "\n" + i18n.T("restore.dev_servers"))
+        apps := make([]string, 0, len(summary.ListeningPorts))
+        for appName := range summary.ListeningPorts {
+            apps = append(apps, appName)
+        }
+        sort.Strings(apps)
+        for _, appName := range apps {
+            fmt.Fprintf(system.Stdout, "   - %s: %s\n", appName, formatPorts(summary.ListeningPorts[appName]))
+        }
     }
 
     return nil
@@ -582,16 +1348,43 @@ func handleCheckpoint() error {
     }
     app.checkpointManager = checkpointMgr
 
-    // Create checkpoint
-    cp, err := app.checkpointManager.CreateCheckpoint()
+    // Create checkpoint, scoped to the given apps when --apps is set
+    var cp *types.Checkpoint
+    if checkpointApps != "" {
+        cp, err = app.checkpointManager.CreatePartialCheckpoint(strings.Split(checkpointApps, ","))
+    } else {
+        cp, err = app.checkpointManager.CreateCheckpoint(config.TriggerManual)
+    }
     if err != nil {
         return fmt.Errorf("Checkpoint creation failed: %w", err)
     }
 
-    fmt.Printf("✅ Checkpoint created: %s\n", cp.ID)
-    fmt.Printf("   Applications saved: %d\n", len(cp.Processes))
-    fmt.Printf("   Size: %d bytes\n", cp.FileSize)
-    
+    if checkpointNote != "" {
+        if err := app.checkpointManager.AnnotateCheckpoint(cp.ID, checkpointNote); err != nil {
+            system.Warn("Failed to attach note:", err)
+        } else {
+            cp.Note = checkpointNote
+        }
+    }
+
+    if cp.Partial {
+        fmt.Fprintln(system.Stdout, i18n.T("checkpoint.created_partial", cp.ID))
+    } else {
+        fmt.Fprintln(system.Stdout, i18n.T("checkpoint.created", cp.ID))
+    }
+    digest := checkpoint.Digest(cp)
+    fmt.Fprintf(system.Stdout, "   %s\n", digest)
+    if cp.Note != "" {
+        fmt.Fprintln(system.Stdout, i18n.T("checkpoint.note", cp.Note))
+    }
+
+    app.notifications().ShowCheckpointSuccess(types.CheckpointStatus{
+        Success:      true,
+        CheckpointID: cp.ID,
+        Timestamp:    cp.Timestamp,
+        AppsCount:    len(cp.AppNames),
+        Digest:       digest,
+    })
     return nil
 }
 
@@ -619,17 +1412,7 @@ func handleStatus() error {
     }
 
     // Check if RESPAWN is running
-    isRunning := false
-    pidFile := filepath.Join(os.Getenv("HOME"), ".respawn", "respawn.pid")
-    if pidData, err := os.ReadFile(pidFile); err == nil {
-        if pid, err := strconv.Atoi(strings.TrimSpace(string(pidData))); err == nil {
-            if process, err := os.FindProcess(pid); err == nil {
-                if err := process.Signal(syscall.Signal(0)); err == nil {
-                    isRunning = true
-                }
-            }
-        }
-    }
+    _, isRunning := runningDaemonPID()
 
     // Get checkpoint list
     checkpointList, err := checkpointMgr.GetAvailableCheckpoints()
@@ -637,62 +1420,172 @@ func handleStatus() error {
         return fmt.Errorf("Failed to get checkpoints: %w", err)
     }
 
+    if jsonOutput {
+        return printStatusJSON(buildStatusSummary(checkpointMgr, startupMgr, checkpointList))
+    }
+
     //Display Status
-    fmt.Println("\n=== RESPAWN STATUS ===")
-    fmt.Printf("Version: %s\n", Version)
-    fmt.Printf("Running: %s\n", boolToStatus(isRunning))
-    fmt.Printf("Auto-start: %s\n", boolToStatus(startupMgr.IsEnabled()))
+    fmt.Fprintln(system.Stdout, "\n" + i18n.T("status.header"))
+    fmt.Fprintln(system.Stdout, i18n.T("status.version", Version))
+    fmt.Fprintln(system.Stdout, i18n.T("status.running", boolToStatus(isRunning)))
+    fmt.Fprintln(system.Stdout, i18n.T("status.autostart", boolToStatus(startupMgr.IsEnabled())))
+    fmt.Fprintln(system.Stdout, i18n.T("status.profile", checkpointMgr.Profile()))
+
+    // Crash history, so a disabled auto-start isn't a mystery
+    recentCrashes, crashWindow, crashDisabled := startupMgr.CrashStatus()
+    fmt.Fprintln(system.Stdout, i18n.T("status.crashes", recentCrashes, crashWindow))
+    if crashDisabled {
+        fmt.Fprintln(system.Stdout, i18n.T("status.crash_disabled"))
+    }
     
     // Show pause state
-    pauseFile := filepath.Join(os.Getenv("HOME"), ".respawn", "paused")
-    if _, err := os.Stat(pauseFile); err == nil {
-        fmt.Printf("Status: ⏸️  PAUSED\n")
+    if until, paused := system.PausedUntil(); paused {
+        if until.IsZero() {
+            fmt.Fprintln(system.Stdout, i18n.T("status.paused"))
+        } else {
+            fmt.Fprintln(system.Stdout, i18n.T("status.paused_until", until.Format("15:04")))
+        }
     } else if isRunning {
-        fmt.Printf("Status: ✅ ACTIVE - Monitoring\n")
+        fmt.Fprintln(system.Stdout, i18n.T("status.active"))
     } else {
-        fmt.Printf("Status: ❌ STOPPED\n")
+        fmt.Fprintln(system.Stdout, i18n.T("status.stopped"))
     }
     
-    fmt.Printf("\nCheckpoints:\n")
-    fmt.Printf("  Total: %d\n", checkpointList.TotalCount)    
+    // Daemon details come from the state snapshot it writes every heartbeat
+    if isRunning {
+        if state, err := system.LoadDaemonState(); err == nil {
+            fmt.Fprintln(system.Stdout, "\n" + i18n.T("status.daemon"))
+            fmt.Fprintln(system.Stdout, i18n.T("status.pid", state.PID))
+            fmt.Fprintln(system.Stdout, i18n.T("status.uptime", time.Since(state.StartedAt).Round(time.Second)))
+            fmt.Fprintln(system.Stdout, i18n.T("status.heartbeat", time.Since(state.LastHeartbeat).Round(time.Second)))
+            if state.LastCheckpointDuration > 0 {
+                fmt.Fprintln(system.Stdout, i18n.T("status.last_checkpoint", state.LastCheckpointDuration.Round(time.Millisecond)))
+            }
+            if !state.SelfUsage.SampledAt.IsZero() {
+                fmt.Fprintln(system.Stdout, i18n.T("status.resources", state.SelfUsage.RSSMB, state.SelfUsage.CPUPercent))
+            }
+            if state.CheckpointGate != "" {
+                fmt.Fprintln(system.Stdout, i18n.T("status.blocked_by", state.CheckpointGate))
+            } else {
+                fmt.Fprintln(system.Stdout, i18n.T("status.blocked_nothing"))
+            }
+            if sleep := state.LastSleep; sleep != nil && !sleep.End.IsZero() {
+                fmt.Fprintln(system.Stdout, i18n.T("status.last_sleep", sleep.Start.Format("15:04"), sleep.End.Format("15:04"), i18n.Duration(sleep.Duration())))
+            }
+        }
+    }
+
+    fmt.Fprintln(system.Stdout, "\n" + i18n.T("status.checkpoints"))
+    fmt.Fprintln(system.Stdout, i18n.T("status.total", checkpointList.TotalCount))
 
     if len(checkpointList.Checkpoints) > 0 {
         latest := checkpointList.Checkpoints[0]
-        fmt.Printf("  Latest: %s\n", latest.ID)
-        fmt.Printf("  Created: %s\n", latest.Timestamp.Format("2006-01-02 15:04:05"))
-        fmt.Printf("  Apps in latest: %d\n", len(latest.AppNames))
+        fmt.Fprintln(system.Stdout, i18n.T("status.latest", latest.ID))
+        if latest.CaptureMode == "basic" {
+            fmt.Fprintln(system.Stdout, i18n.T("status.basic_capture"))
+        }
+        fmt.Fprintln(system.Stdout, i18n.T("status.created", latest.Timestamp.Format("2006-01-02 15:04:05")))
+        if latest.Note != "" {
+            fmt.Fprintln(system.Stdout, i18n.T("status.note", latest.Note))
+        }
+        fmt.Fprintln(system.Stdout, i18n.T("status.latest_apps", len(latest.AppNames)))
         
         if len(latest.AppNames) > 0 {
-            fmt.Printf("  Applications:\n")
+            fmt.Fprintln(system.Stdout, i18n.T("status.applications"))
             for i, app := range latest.AppNames {
                 if i >= 10 {
-                    fmt.Printf("    ... and %d more\n", len(latest.AppNames)-10)
+                    fmt.Fprintln(system.Stdout, i18n.T("status.more_apps", len(latest.AppNames)-10))
                     break
                 }
-                fmt.Printf("    - %s\n", app)
+                fmt.Fprintf(system.Stdout, "    - %s\n", app)
             }
         }
         
         // Show next checkpoint time
         if isRunning {
-            nextCheckpoint := latest.Timestamp.Add(config.GlobalConfig.CheckpointInterval)
+            nextCheckpoint := latest.Timestamp.Add(config.Current().CheckpointInterval)
             timeUntil := time.Until(nextCheckpoint)
             if timeUntil > 0 {
-                fmt.Printf("\n  Next checkpoint in: %s\n", timeUntil.Round(time.Minute))
+                fmt.Fprintln(system.Stdout, "\n" + i18n.T("status.next_checkpoint", timeUntil.Round(time.Minute)))
             } else {
-                fmt.Printf("\n  Next checkpoint: Overdue (should create soon)\n")
+                fmt.Fprintln(system.Stdout, "\n" + i18n.T("status.overdue"))
             }
         }
     } else {
-        fmt.Printf("  No checkpoints yet\n")
+        fmt.Fprintln(system.Stdout, i18n.T("status.no_checkpoints"))
     }
     
-    fmt.Printf("\nConfiguration:\n")
-    fmt.Printf("  Checkpoint interval: %v\n", config.GlobalConfig.CheckpointInterval)
-    fmt.Printf("  Data retention: %d days\n", config.GlobalConfig.DataRetentionDays)
-    
+    // Restore track record, so apps RESPAWN stopped launching aren't a mystery
+    queue, queueErr := process.LoadRestoreQueue()
+    if history, err := process.LoadRestoreHistory(); err == nil && (len(history.Apps) > 0 || queueErr == nil) {
+        fmt.Fprintln(system.Stdout, "\n" + i18n.T("status.restores"))
+        if queueErr == nil && queue.Running() {
+            fmt.Fprintln(system.Stdout, i18n.T("status.restoring", queue.Source, len(queue.Remaining), queue.PID))
+        } else if queueErr == nil {
+            fmt.Fprintln(system.Stdout, i18n.T("status.interrupted", queue.Source, len(queue.Remaining)))
+        }
+        fmt.Fprintln(system.Stdout, i18n.T("status.success_rate", history.SuccessRate()*100))
+        if manual := history.ManualOnlyApps(); len(manual) > 0 {
+            fmt.Fprintln(system.Stdout, i18n.T("status.manual_only", strings.Join(manual, ", ")))
+        }
+        if blacklist := history.Blacklist(); len(blacklist) > 0 {
+            fmt.Fprintln(system.Stdout, i18n.T("status.blacklisted", strings.Join(blacklistedApps(blacklist), ", ")))
+        }
+    }
+
+    // Use cached pre-flight results so status never triggers a TCC prompt
+    fmt.Fprintln(system.Stdout, "\n" + i18n.T("status.permissions"))
+    if cache, err := system.LoadPermissionCache(); err != nil {
+        fmt.Fprintln(system.Stdout, i18n.T("status.not_checked"))
+    } else {
+        fmt.Fprintln(system.Stdout, i18n.T("status.automation", boolToGranted(cache.States[system.PermissionAutomation])))
+        fmt.Fprintln(system.Stdout, i18n.T("status.accessibility", boolToGranted(cache.States[system.PermissionAccessibility])))
+        if cache.States[system.PermissionScreenRecording] {
+            fmt.Fprintln(system.Stdout, i18n.T("status.screen", boolToGranted(true)))
+        } else {
+            fmt.Fprintln(system.Stdout, i18n.T("status.screen_missing", boolToGranted(false)))
+        }
+        fmt.Fprintln(system.Stdout, i18n.T("status.checked_at", cache.CheckedAt.Format("2006-01-02 15:04:05")))
+    }
+
+    fmt.Fprintln(system.Stdout, "\n" + i18n.T("status.configuration"))
+    fmt.Fprintln(system.Stdout, i18n.T("status.interval", config.Current().CheckpointInterval))
+    fmt.Fprintln(system.Stdout, i18n.T("status.retention", config.Current().DataRetentionDays))
+    fmt.Fprintln(system.Stdout, i18n.T("status.loops",
+        config.Current().MonitorInterval, config.Current().HeartbeatInterval, config.Current().LearningInterval))
+
+    if showTimings {
+        printStartupTimings()
+    }
     return nil
 }
+
+// printStartupTimings shows the latest startup per phase and the recent history against the budget
+func printStartupTimings() {
+    fmt.Fprintf(system.Stdout, "\nStartup timings (budget %v):\n", system.StartupBudget)
+
+    metrics, err := system.LoadMetrics()
+    if err != nil || len(metrics.StartupTimings) == 0 {
+        fmt.Fprintln(system.Stdout, "  No startups recorded yet")
+        return
+    }
+
+    latest := metrics.StartupTimings[len(metrics.StartupTimings)-1]
+    fmt.Fprintf(system.Stdout, "  Latest (%s): %v\n", latest.StartedAt.Format("2006-01-02 15:04"), latest.Total.Round(time.Millisecond))
+    for _, phase := range latest.Phases {
+        fmt.Fprintf(system.Stdout, "    %-14s %v\n", phase.Name, phase.Duration.Round(time.Millisecond))
+    }
+
+    fmt.Fprintf(system.Stdout, "  Recent:\n")
+    for i := len(metrics.StartupTimings) - 1; i >= 0; i-- {
+        timing := metrics.StartupTimings[i]
+        marker := "✅"
+        if timing.OverBudget() {
+            marker = "⚠️ "
+        }
+        fmt.Fprintf(system.Stdout, "    %s %s  %v\n", marker, timing.StartedAt.Format("2006-01-02 15:04"), timing.Total.Round(time.Millisecond))
+    }
+}
 // handleEnableAutoStart processes the enable-autostart command
 func handleEnableAutoStart() error {
     app = &RESPAWNApp{}
@@ -721,41 +1614,514 @@ func handleDisableAutoStart() error {
 
 // handlePause runs the pause command 
 func handlePause() error {
-    // Create pause marker file
-    homeDir, _ := os.UserHomeDir()
-    pauseFile := filepath.Join(homeDir, ".respawn", "paused")
+    if err := config.LoadConfig(); err != nil {
+        return fmt.Errorf("Config load failed: %w", err)
+    }
 
-    if err := os.WriteFile(pauseFile, []byte(time.Now().String()), 0644); err != nil {
-        return fmt.Errorf("Failed to create pause marker: %w", err)
+    var until time.Time
+    if pauseDuration > 0 {
+        until = time.Now().Add(pauseDuration)
     }
+    if err := system.Pause(until); err != nil {
+        return err
+    }
+    notifyPauseChanged()
 
-    fmt.Println("✅ RESPAWN monitoring paused")
-    fmt.Println("Run 'respawn resume' to resume monitoring")
+    if pauseDuration > 0 {
+        fmt.Fprintf(system.Stdout, "✅ RESPAWN monitoring paused for %s\n", pauseDuration)
+        fmt.Fprintln(system.Stdout, "Run 'respawn resume' to resume earlier")
+    } else {
+        fmt.Fprintln(system.Stdout, "✅ RESPAWN monitoring paused")
+        fmt.Fprintln(system.Stdout, "Run 'respawn resume' to resume monitoring")
+    }
     
     return nil
 }
 
+// notifyPauseChanged tells a running daemon to pick up the pause marker now
+// rather than at its next monitoring cycle
+func notifyPauseChanged() {
+    if pid, running := runningDaemonPID(); running {
+        if err := syscall.Kill(pid, syscall.SIGUSR2); err != nil {
+            system.Debug("Failed to signal daemon:", err)
+        }
+    }
+}
+
 // handleResume runs the resume command 
 func handleResume() error {
-    // Remove pause marker file
-    homeDir, _ := os.UserHomeDir()
-    pauseFile := filepath.Join(homeDir, ".respawn", "paused")
+    if err := config.LoadConfig(); err != nil {
+        return fmt.Errorf("Config load failed: %w", err)
+    }
+
+    if err := system.Resume(); err != nil {
+        return err
+    }
+    notifyPauseChanged()
+
+    fmt.Fprintln(system.Stdout, "✅ RESPAWN monitoring resumed")
+
+    return nil
+}
+
+// permissionImpact describes what stops working without each permission
+var permissionImpact = map[system.Permission]string{
+    system.PermissionAutomation:      "RESPAWN cannot talk to System Events - no window state or app info",
+    system.PermissionAccessibility:   "Window states (minimized/maximized) cannot be detected or restored",
+    system.PermissionScreenRecording: "Window titles are not captured, only window geometry",
+    system.PermissionFullDiskAccess:  "Deep app integration (e.g. Safari data) is unavailable",
+}
+
+// handleDoctor runs the doctor command
+func handleDoctor() error {
+    if err := system.InitLogger(); err != nil {
+        return fmt.Errorf("Logger initialization failed: %w", err)
+    }
+
+    startupMgr, err := system.NewStartupManager()
+    if err != nil {
+        return fmt.Errorf("Startup manager creation failed: %w", err)
+    }
+
+    fmt.Fprintln(system.Stdout, "\n=== RESPAWN DOCTOR ===")
+
+    // Doctor checks live and refreshes the cache used by status
+    cache, err := startupMgr.RunPermissionPreflight()
+    if err != nil {
+        system.Warn("Failed to cache permission state:", err)
+    }
+
+    problems := 0
+    for _, permission := range []system.Permission{
+        system.PermissionAutomation,
+        system.PermissionAccessibility,
+        system.PermissionScreenRecording,
+        system.PermissionFullDiskAccess,
+    } {
+        granted := cache.States[permission]
+        fmt.Fprintf(system.Stdout, "%s: %s\n", permission, boolToGranted(granted))
+        if !granted {
+            problems++
+            fmt.Fprintf(system.Stdout, "   Limitation: %s\n", permissionImpact[permission])
+            fmt.Fprintf(system.Stdout, "   Fix: open %s\n", system.PermissionPaneURL(permission))
+        }
+    }
+
+    if problems == 0 {
+        fmt.Fprintln(system.Stdout, "\n✅ No problems found")
+    } else {
+        fmt.Fprintf(system.Stdout, "\n⚠️  %d permission(s) missing\n", problems)
+    }
+    return nil
+}
+
+// handleShow processes the show command
+func handleShow(id string) error {
+    if err := config.LoadConfig(); err != nil {
+        return fmt.Errorf("Config load failed: %w", err)
+    }
+
+    checkpointMgr, err := checkpoint.NewCheckpointManager()
+    if err != nil {
+        return fmt.Errorf("Checkpoint manager creation failed: %w", err)
+    }
+
+    // Raw output goes to stdout untouched so it can be redirected to a file
+    if rawOutput {
+        data, err := checkpointMgr.ExportCheckpointJSON(id)
+        if err != nil {
+            return err
+        }
+        fmt.Fprintln(system.Stdout, string(data))
+        return nil
+    }
+
+    cp, err := checkpointMgr.GetCheckpoint(id)
+    if err != nil {
+        return err
+    }
+
+    fmt.Fprintf(system.Stdout, "📦 Checkpoint %s\n", cp.ID)
+    fmt.Fprintf(system.Stdout, "   Created: %s\n", cp.Timestamp.Format("2006-01-02 15:04:05"))
+    if cp.CaptureMode != "" {
+        fmt.Fprintf(system.Stdout, "   Capture: %s\n", cp.CaptureMode)
+    }
+    if cp.Trigger != "" {
+        fmt.Fprintf(system.Stdout, "   Trigger: %s\n", cp.Trigger)
+    }
+    if cp.Note != "" {
+        fmt.Fprintf(system.Stdout, "   Note: %s\n", cp.Note)
+    }
+    fmt.Fprintf(system.Stdout, "   File: %s\n", cp.FilePath)
+
+    fmt.Fprintf(system.Stdout, "\n🖥️  Applications (%d):\n", len(cp.Processes))
+    for _, proc := range cp.Processes {
+        fmt.Fprintf(system.Stdout, "   • %s (%s)\n", proc.Name, proc.WindowState)
+        if len(proc.ListeningPorts) > 0 {
+            fmt.Fprintf(system.Stdout, "     Ports: %s\n", formatPorts(proc.ListeningPorts))
+        }
+    }
+
+    if len(cp.Volumes) > 0 {
+        fmt.Fprintln(system.Stdout, "\n💾 Network volumes:")
+        for _, volume := range cp.Volumes {
+            fmt.Fprintf(system.Stdout, "   • %s on %s\n", volume.Source, volume.MountPoint)
+        }
+    }
+
+    if len(cp.Displays) > 0 {
+        fmt.Fprintln(system.Stdout, "\n🖵  Displays:")
+        for _, display := range cp.Displays {
+            fmt.Fprintf(system.Stdout, "   • %s %dx%d\n", display.Name, display.Size.Width, display.Size.Height)
+        }
+    }
+
+    showManifest(cp)
+    return nil
+}
+
+// showManifest explains what a checkpoint can reconstruct and why anything is missing
+func showManifest(cp *types.Checkpoint) {
+    if len(cp.Providers) == 0 {
+        fmt.Fprintln(system.Stdout, "\n🧩 Captured data: not recorded (checkpoint predates capture manifests)")
+        return
+    }
+
+    fmt.Fprintf(system.Stdout, "\n🧩 Captured data: %s\n", strings.Join(checkpoint.CapturedCategories(cp), ", "))
+    missing := checkpoint.MissingProviders(cp)
+    if len(missing) == 0 {
+        return
+    }
+    fmt.Fprintln(system.Stdout, "   Not captured:")
+    for _, p := range missing {
+        fmt.Fprintf(system.Stdout, "   • %s (%s): %s\n", strings.Join(p.Categories, ", "), p.Name, p.Reason)
+    }
+}
+
+// handleList processes the list command
+func handleList() error {
+    if err := config.LoadConfig(); err != nil {
+        return fmt.Errorf("Config load failed: %w", err)
+    }
+
+    var summaries []checkpoint.CheckpointSummary
+    if fastList {
+        var err error
+        if summaries, err = checkpoint.ListSummariesFast(allMachines); err != nil {
+            return err
+        }
+    } else {
+        checkpointMgr, err := checkpoint.NewCheckpointManager()
+        if err != nil {
+            return fmt.Errorf("Checkpoint manager creation failed: %w", err)
+        }
+        checkpointMgr.SetAllMachines(allMachines)
+        if summaries, err = checkpointMgr.ListSummaries(); err != nil {
+            return err
+        }
+    }
+
+    if jsonOutput {
+        if summaries == nil {
+            summaries = []checkpoint.CheckpointSummary{} // Print [] rather than null
+        }
+        data, err := json.MarshalIndent(summaries, "", "  ")
+        if err != nil {
+            return err
+        }
+        fmt.Fprintln(system.Stdout, string(data))
+        return nil
+    }
+
+    if len(summaries) == 0 {
+        fmt.Fprintln(system.Stdout, "No checkpoints available.")
+        return nil
+    }
+
+    for _, summary := range summaries {
+        tags := ""
+        if summary.Partial {
+            tags += " (partial)"
+        }
+        if summary.CaptureMode == "basic" {
+            tags += " (basic)"
+        }
+        if !summary.ThisMachine {
+            tags += " @" + summary.Hostname
+        }
+        fmt.Fprintf(system.Stdout, "%s  %s  %d apps%s\n", summary.ID, summary.Timestamp.Format("2006-01-02 15:04"), len(summary.Apps), tags)
+        if summary.Note != "" {
+            fmt.Fprintf(system.Stdout, "   📝 %s\n", summary.Note)
+        }
+    }
+    return nil
+}
+
+// handleRecompress processes the storage recompress command
+func handleRecompress() error {
+    if err := config.LoadConfig(); err != nil {
+        return fmt.Errorf("Config load failed: %w", err)
+    }
+
+    if !forceMode && system.OnLowBattery() {
+        return fmt.Errorf("battery below %d%% - plug in or use --force", config.Current().MaintenanceMinBattery)
+    }
+
+    level := compressionLevel
+    if level == 0 {
+        level = config.Current().CompressionLevel
+    }
+
+    checkpointMgr, err := checkpoint.NewCheckpointManager()
+    if err != nil {
+        return fmt.Errorf("Checkpoint manager creation failed: %w", err)
+    }
+
+    fmt.Fprintf(system.Stdout, "📦 Recompressing checkpoints at level %d (low priority)...\n", level)
+    report, err := checkpointMgr.RecompressCheckpoints(level, func(id string, before, after int64) {
+        fmt.Fprintf(system.Stdout, "   %s: %d → %d bytes\n", id, before, after)
+    })
+    if err != nil {
+        return err
+    }
+
+    fmt.Fprintf(system.Stdout, "\n✅ Recompressed %d checkpoints, saved %.1f KB\n", report.Recompressed, float64(report.Saved())/1024)
+    if report.Skipped > 0 {
+        fmt.Fprintf(system.Stdout, "   %d uncompressed checkpoints skipped - they will use the new level when compressed\n", report.Skipped)
+    }
+    if len(report.Failed) > 0 {
+        fmt.Fprintf(system.Stdout, "⚠️  Failed: %s\n", strings.Join(report.Failed, ", "))
+    }
+
+    // Remember the level so future compression uses it too
+    if level != config.Current().CompressionLevel {
+        if err := config.Update(func(c *config.Config) { c.CompressionLevel = level }); err != nil {
+            system.Warn("Failed to save compression level:", err)
+        }
+    }
+
+    return nil
+}
+
+// handleVerify processes the verify command
+func handleVerify() error {
+    if err := config.LoadConfig(); err != nil {
+        return fmt.Errorf("Config load failed: %w", err)
+    }
+
+    checkpointMgr, err := checkpoint.NewCheckpointManager()
+    if err != nil {
+        return fmt.Errorf("Checkpoint manager creation failed: %w", err)
+    }
+
+    report, err := checkpointMgr.VerifyCheckpoints(quarantineFiles)
+    if err != nil {
+        return err
+    }
+
+    fmt.Fprintf(system.Stdout, "🔍 Checked %d checkpoints\n", report.Checked)
+    if len(report.Problems) == 0 {
+        fmt.Fprintln(system.Stdout, "✅ All checkpoints are intact")
+        return nil
+    }
+
+    fmt.Fprintf(system.Stdout, "\n⚠️  %d problems found:\n", len(report.Problems))
+    for _, problem := range report.Problems {
+        fmt.Fprintf(system.Stdout, "   [%s] %s: %s\n", problem.Kind, problem.CheckpointID, problem.Detail)
+    }
+
+    if len(report.Quarantined) > 0 {
+        fmt.Fprintf(system.Stdout, "\n📦 Quarantined %d files\n", len(report.Quarantined))
+    } else if !quarantineFiles {
+        fmt.Fprintln(system.Stdout, "\nRun 'respawn verify --quarantine' to move bad files aside")
+    }
+
+    return nil
+}
+
+// handleRepair processes the repair command
+func handleRepair() error {
+    if err := config.LoadConfig(); err != nil {
+        return fmt.Errorf("Config load failed: %w", err)
+    }
+
+    checkpointMgr, err := checkpoint.NewCheckpointManager()
+    if err != nil {
+        return fmt.Errorf("Checkpoint manager creation failed: %w", err)
+    }
+
+    report, err := checkpointMgr.RepairMetadata(rebuildAll)
+    if err != nil {
+        return err
+    }
+
+    if len(report.Conflicts) > 0 {
+        fmt.Fprintf(system.Stdout, "🔀 Resolved %d sync conflicts:\n", len(report.Conflicts))
+        for _, conflict := range report.Conflicts {
+            fmt.Fprintf(system.Stdout, "   %s\n", conflict)
+        }
+    }
+    fmt.Fprintf(system.Stdout, "🔧 Rebuilt metadata for %d checkpoints (%d already up to date)\n", len(report.Rebuilt), report.Unchanged)
+    if len(report.Unreadable) > 0 {
+        fmt.Fprintf(system.Stdout, "⚠️  %d checkpoints could not be read: %s\n", len(report.Unreadable), strings.Join(report.Unreadable, ", "))
+        fmt.Fprintln(system.Stdout, "   Run 'respawn verify --quarantine' to move them aside")
+    }
+
+    return nil
+}
+
+// handlePurge runs the purge command
+func handlePurge() error {
+    // The PID file is in the configured data dir
+    if err := config.LoadConfig(); err != nil {
+        system.Debug("Config load failed, purging the default locations:", err)
+    }
+    if pid, running := runningDaemonPID(); running {
+        return fmt.Errorf("RESPAWN is running (PID: %d), stop it before purging", pid)
+    }
+
+    dirs := purgeDirs()
+    if !assumeYes {
+        fmt.Fprintf(system.Stdout, "This will permanently delete all RESPAWN data in %s\n", strings.Join(dirs, ", "))
+        if !confirmTyped("Type 'yes' to continue: ") {
+            fmt.Fprintln(system.Stdout, "Purge cancelled")
+            return nil
+        }
+    }
+
+    deleted, err := system.PurgeData(dirs...)
+    if err != nil {
+        return err
+    }
+
+    for _, path := range deleted {
+        fmt.Fprintf(system.Stdout, "  deleted %s\n", path)
+    }
+    fmt.Fprintf(system.Stdout, "✅ Purged %d files\n", len(deleted))
+
+    return nil
+}
+
+// purgeDirs lists where RESPAWN keeps data: the data dir, with teams, templates and
+// the permission cache, the log dir, and ~/.respawn, which holds the first-run marker
+// and everything else when data_dir points elsewhere
+func purgeDirs() []string {
+    defaults := config.DefaultConfig()
+    cfg := config.Current()
+    if cfg == nil {
+        cfg = defaults
+    }
+
+    dirs := []string{cfg.DataDir}
+    for _, dir := range []string{cfg.LogDir, defaults.DataDir} {
+        if dir != "" && dir != cfg.DataDir {
+            dirs = append(dirs, dir)
+        }
+    }
+    return dirs
+}
+
+// runningDaemonPID returns the PID of the running RESPAWN daemon, if any
+func runningDaemonPID() (int, bool) {
+    pidData, err := os.ReadFile(system.PIDFile())
+    if err != nil {
+        return 0, false
+    }
 
-    if err := os.Remove(pauseFile); err != nil && !os.IsNotExist(err) {
-        return fmt.Errorf("Failed to remove pause marker: %w", err)
+    pid, err := strconv.Atoi(strings.TrimSpace(string(pidData)))
+    if err != nil || pid == os.Getpid() {
+        return 0, false
     }
 
-    fmt.Println("✅ RESPAWN monitoring resumed")
+    process, err := os.FindProcess(pid)
+    if err != nil {
+        return 0, false
+    }
+
+    if err := process.Signal(syscall.Signal(0)); err != nil {
+        return 0, false
+    }
+    return pid, true
+}
+
+// handlePrune deletes the oldest checkpoints until --free bytes are freed
+func handlePrune() error {
+    if err := config.LoadConfig(); err != nil {
+        return fmt.Errorf("Config load failed: %w", err)
+    }
+
+    bytes, err := checkpoint.ParseSize(pruneFree)
+    if err != nil {
+        return err
+    }
 
+    checkpointMgr, err := checkpoint.NewCheckpointManager()
+    if err != nil {
+        return fmt.Errorf("Checkpoint manager creation failed: %w", err)
+    }
+
+    plan, err := checkpointMgr.PlanPrune(bytes)
+    if err != nil {
+        return err
+    }
+    if len(plan.Checkpoints) == 0 {
+        fmt.Fprintln(system.Stdout, "Nothing to prune - only the newest and last restored checkpoints are left")
+        return nil
+    }
+
+    fmt.Fprintf(system.Stdout, "🧹 %d checkpoints (%s):\n", len(plan.Checkpoints), checkpoint.FormatBytes(plan.Bytes))
+    for _, cp := range plan.Checkpoints {
+        fmt.Fprintf(system.Stdout, "   • %s  %s\n", cp.ID, checkpoint.FormatBytes(cp.FileSize))
+    }
+    if plan.Bytes < plan.Requested {
+        fmt.Fprintf(system.Stdout, "   ⚠️  Checkpoints only free %s of the %s requested\n", checkpoint.FormatBytes(plan.Bytes), checkpoint.FormatBytes(plan.Requested))
+    }
+
+    if dryRun {
+        fmt.Fprintln(system.Stdout, "\nDry run - nothing deleted")
+        return nil
+    }
+    if !assumeYes && !confirm("\nDelete these checkpoints? [y/N]: ") {
+        fmt.Fprintln(system.Stdout, "Prune cancelled")
+        return nil
+    }
+
+    deleted, err := checkpointMgr.Prune(plan)
+    if err != nil {
+        return err
+    }
+    fmt.Fprintf(system.Stdout, "✅ Pruned %d checkpoints, freed %s\n", deleted, checkpoint.FormatBytes(plan.Bytes))
     return nil
 }
 
+// confirm asks a [y/N] question on the terminal, accepting "y" or "yes"
+func confirm(prompt string) bool {
+    answer := readAnswer(prompt)
+    return answer == "yes" || answer == "y"
+}
+
+// confirmTyped asks the user to type "yes" in full, for operations that can't be undone
+func confirmTyped(prompt string) bool {
+    return readAnswer(prompt) == "yes"
+}
+
+// readAnswer shows prompt and reads one line from the terminal, lowercased
+func readAnswer(prompt string) string {
+    fmt.Fprint(system.Stderr, prompt) // Shown even with --quiet
+    answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+    if err != nil {
+        return ""
+    }
+    return strings.ToLower(strings.TrimSpace(answer))
+}
+
 // setupGracefulShutdown handles graceful shutdown or signals 
 func setupGracefulShutdown() {
     sigChan :=  make(chan os.Signal, 1)
     signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
     go func() {
+        defer system.RecordPanics()
         sig := <-sigChan
         system.Info("Received signal:", sig)
 
@@ -768,6 +2134,71 @@ func setupGracefulShutdown() {
     }()
 }
 
+// setupControlSignals reloads the config on SIGHUP, checkpoints on SIGUSR1 and rereads
+// the pause marker on SIGUSR2, e.g. kill -USR1 $(cat ~/.respawn/respawn.pid) from a
+// script or pre-sleep hook
+func setupControlSignals() {
+    sigChan := make(chan os.Signal, 1)
+    signal.Notify(sigChan, syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2)
+
+    go func() {
+        defer system.RecordPanics()
+        for sig := range sigChan {
+            switch sig {
+            case syscall.SIGHUP:
+                reloadConfig()
+            case syscall.SIGUSR1:
+                system.Info("Received SIGUSR1 - creating checkpoint")
+                app.monitor.TriggerCheckpoint(config.TriggerPreSleep)
+            case syscall.SIGUSR2:
+                app.monitor.CheckPause()
+            }
+        }
+    }()
+}
+
+// reloadConfig re-reads the config and pushes it to the running components
+func reloadConfig() {
+    system.Info("Received SIGHUP - reloading configuration")
+    applyConfigReload()
+}
+
+// applyConfigReload re-reads the config file, e.g. after an optimization changed it
+func applyConfigReload() {
+    changes, err := config.Reload()
+    if err != nil {
+        system.Error("Config reload failed, keeping the current config:", err)
+        return
+    }
+    if len(changes) == 0 {
+        system.Info("Config reloaded - nothing changed")
+        return
+    }
+    system.Info("Config reloaded - changed:", strings.Join(changes, ", "))
+
+    app.monitor.ApplyConfig()
+    app.notifications().ApplyConfig()
+    if err := app.checkpointManager.ApplyConfig(); err != nil {
+        system.Warn("Failed to apply config to checkpoints:", err)
+    }
+    go applyBackupExclusions()
+
+    for _, change := range changes {
+        for _, setting := range config.RestartSettings {
+            if change == setting {
+                system.Warn("Setting", change, "takes effect after a restart")
+            }
+        }
+    }
+}
+
+// applyBackupExclusions logs rather than fails, checkpoints work either way
+func applyBackupExclusions() {
+    if err := checkpoint.ApplyBackupExclusions(); err != nil {
+        system.Warn("Failed to update Time Machine/Spotlight exclusions:", err)
+    }
+}
+
 // gracefulShutdown performs graceful shutdown with checkpoint logic
 func gracefulShutdown() error {
     system.Info("Starting graceful shutdown")
@@ -776,7 +2207,7 @@ func gracefulShutdown() error {
         return nil
     }
 
-    timeSinceLastCheckpoint := time.Since(app.lastCheckpointTime)
+    timeSinceLastCheckpoint := time.Since(app.getLastCheckpointTime())
 
     if timeSinceLastCheckpoint < 60*time.Minute {
         // Less than 1 hour - quit immediately
@@ -788,14 +2219,14 @@ func gracefulShutdown() error {
         // 2+ hours - ask user
         system.Info("Last checkpoint over 2 hours ago, asking user")
 
-        _, err := app.notificationManager.ShowPermissionRequest(
+        _, err := app.notifications().ShowPermissionRequest(
             "Checkpoint",
             "Last checkpoint was over 2 hours ago.\nCreate checkpoint before quitting?",
         )
 
         if err == nil {
             // User chose to create checkpoint
-            if _, err := app.checkpointManager.CreateCheckpoint(); err != nil {
+            if _, err := app.checkpointManager.CreateCheckpoint(config.TriggerManual); err != nil {
                 system.Error("Failed to create final checkpoint:", err)
             } else {
                 system.Info("Final checkpoint created successfully")
@@ -812,10 +2243,22 @@ func cleanup() error {
         app.startupManager.Cleanup()
     }
 
+    // Wait for a checkpoint the monitor just started, a half-written one is worse than none
     if app.monitor != nil {
-        app.monitor.Stop()
+        if !app.monitor.Shutdown(shutdownTimeout) {
+            system.Warn("Exiting with work still in flight")
+        }
     }
 
+    if app.apiServer != nil {
+        app.apiServer.Stop()
+    }
+
+    if app.pprofServer != nil {
+        app.pprofServer.Stop()
+    }
+
+    system.FlushEvents(eventFlushTimeout)
     system.Close()
 
     return nil 
@@ -836,6 +2279,15 @@ func isFirstRun() bool {
 func showFirstTimeExperience() error {
     system.Info("Showing first-time experience")
 
+    // Prefer the terminal wizard, it works over SSH and writes the config directly
+    if !guiSetup && isTerminal() {
+        if err := runSetupWizard(); err != nil {
+            return err
+        }
+        markFirstRunComplete()
+        return nil
+    }
+
     // Show welcome dialog using AppleScript
     welcomeScript := fmt.Sprintf(`
         display dialog "Welcome to RESPAWN
@@ -857,20 +2309,41 @@ Ready to begin setup?" with title "Welcome to RESPAWN" buttons {"Begin Setup", "
         return fmt.Errorf("User cancelled setup")
     }
 
-    // Mark first run complete
+    markFirstRunComplete()
+    return nil
+}
+
+// markFirstRunComplete writes the first run marker
+func markFirstRunComplete() {
     homeDir, _ := os.UserHomeDir()
     firstRunMarker := filepath.Join(homeDir, ".respawn", "first_run")
     os.MkdirAll(filepath.Dir(firstRunMarker), 0755)
     os.WriteFile(firstRunMarker, []byte(time.Now().String()), 0644)
 
     system.Info("First-time experience completed")    
-    return nil
+}
+
+// formatPorts joins port numbers for display
+func formatPorts(ports []int) string {
+    portStrs := make([]string, len(ports))
+    for i, port := range ports {
+        portStrs[i] = strconv.Itoa(port)
+    }
+    return strings.Join(portStrs, ", ")
+}
+
+// boolToGranted converts a permission state to a status string
+func boolToGranted(granted bool) string {
+    if granted {
+        return i18n.T("granted")
+    }
+    return i18n.T("not_granted")
 }
 
 //boolToStatus converts boolean to status string
 func boolToStatus(enabled bool) string {
     if enabled {
-        return "✅ Enabled"
+        return i18n.T("enabled")
     }
-    return "❌ Disabled"
+    return i18n.T("disabled")
 }
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"RESPAWN/internal/checkpoint"
+	"RESPAWN/internal/i18n"
+	"RESPAWN/internal/system"
+)
+
+// pickerSize is how many checkpoints the restore picker offers
+const pickerSize = 10
+
+// errRestoreCancelled is returned when the user backs out of the restore picker
+var errRestoreCancelled = errors.New("restore cancelled")
+
+// pickCheckpoint lists the latest checkpoints and asks which one to restore. It returns
+// the chosen ID, even for the newest, so a checkpoint taken while the user was choosing
+// isn't restored instead, and whether the newest was chosen. With nothing to choose
+// between it returns "" for the restore's usual path.
+func pickCheckpoint(checkpointMgr *checkpoint.CheckpointManager) (string, bool, error) {
+	list, err := checkpointMgr.GetAvailableCheckpoints()
+	if err != nil {
+		return "", false, err
+	}
+	if len(list.Checkpoints) <= 1 {
+		return "", true, nil // Nothing to choose between
+	}
+
+	shown := list.Checkpoints
+	if len(shown) > pickerSize {
+		shown = shown[:pickerSize]
+	}
+
+	// The picker goes to stderr like other prompts, keeping stdout for restore output
+	fmt.Fprintln(system.Stderr, i18n.T("restore.pick_header"))
+	for i := range shown {
+		cp := &shown[i]
+		line := i18n.T("restore.pick_entry", i+1, cp.Timestamp.Format("Mon Jan 02 15:04"), checkpoint.Digest(cp))
+		if cp.Note != "" {
+			line += i18n.T("restore.pick_note", cp.Note)
+		}
+		fmt.Fprintln(system.Stderr, line)
+	}
+	fmt.Fprint(system.Stderr, i18n.T("restore.pick_prompt"))
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return "", false, errRestoreCancelled
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	switch answer {
+	case "":
+		return shown[0].ID, true, nil
+	case "q", "quit":
+		return "", false, errRestoreCancelled
+	}
+
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(shown) {
+		return "", false, fmt.Errorf("invalid choice %q, expected a number from 1 to %d", answer, len(shown))
+	}
+	return shown[n-1].ID, n == 1, nil
+}
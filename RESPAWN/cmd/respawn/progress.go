@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"RESPAWN/internal/process"
+)
+
+// progressBarWidth is the number of cells in the restore progress bar
+const progressBarWidth = 10
+
+// restoreProgress prints a progress bar line per app to out and, when notifier is
+// set, mirrors the restore to notifications
+func restoreProgress(notifier Notifier, out io.Writer) process.ProgressFunc {
+	return func(p process.RestoreProgress) {
+		if !p.Done() {
+			if notifier != nil {
+				notifier.ShowRestorationProgress(p.Index, p.Total, p.App)
+			}
+			return
+		}
+
+		fmt.Fprintf(out, "%s %s %s\n", progressBar(p.Index, p.Total), p.App, progressMark(p.Status))
+		if notifier != nil && p.Status == process.ProgressLaunched && p.Result != nil {
+			notifier.ShowAppRestored(p.App, p.Result.LaunchTime)
+		}
+	}
+}
+
+// progressBar renders e.g. "[████░░░░░░] 4/10"
+func progressBar(done, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = done * progressBarWidth / total
+	}
+	return fmt.Sprintf("[%s%s] %d/%d", strings.Repeat("█", filled), strings.Repeat("░", progressBarWidth-filled), done, total)
+}
+
+// progressMark describes an app's final restore status
+func progressMark(status string) string {
+	switch status {
+	case process.ProgressLaunched:
+		return "✅"
+	case process.ProgressAlreadyRunning:
+		return "⏩ already running"
+	case process.ProgressLoginItem:
+		return "⏩ opened at login"
+	case process.ProgressReopenedByMacOS:
+		return "⏩ already restored by macOS"
+	case process.ProgressNotInstalled:
+		return "⏭️  not installed"
+	case process.ProgressManualOnly:
+		return "✋ manual restore only"
+	case process.ProgressBlacklisted:
+		return "🚫 blacklisted"
+	}
+	return "❌"
+}
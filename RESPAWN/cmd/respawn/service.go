@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"RESPAWN/internal/system"
+)
+
+// Service command flags
+var (
+	serviceBinary string // Binary path for the plist, instead of the detected one
+	serviceLabel  string // launchd label for the plist
+	serviceOutput string // Write the plist here instead of stdout
+)
+
+// Service command group
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Integrate with service managers such as brew services",
+	Long: `Helpers for running RESPAWN under an external service manager instead of
+'respawn install'. Under a service manager the daemon runs in the foreground and the
+manager keeps it alive.`,
+}
+
+// Service plist command
+var servicePlistCmd = &cobra.Command{
+	Use:   "plist",
+	Short: "Print a LaunchAgent plist for brew services",
+	Long: `Prints a LaunchAgent plist that runs 'respawn start --foreground'. For a Homebrew
+install it points at <prefix>/opt/respawn/bin/respawn, which survives 'brew upgrade',
+and uses the homebrew.mxcl.respawn label so 'brew services start respawn' manages it.
+Don't combine it with 'respawn install', or two daemons will be started.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := handleServicePlist(); err != nil {
+			printFailure("Plist", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// handleServicePlist writes the service plist to stdout or serviceOutput
+func handleServicePlist() error {
+	binary := serviceBinary
+	if binary == "" {
+		var ok bool
+		if binary, ok = system.HomebrewBinary(); !ok {
+			executablePath, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to locate respawn binary: %w", err)
+			}
+			binary = executablePath
+			fmt.Fprintf(system.Stderr, "⚠️  respawn doesn't look installed by Homebrew, using %s (override with --binary)\n", binary)
+		}
+	}
+
+	var out io.Writer = system.Stdout
+	if serviceOutput != "" {
+		file, err := os.Create(serviceOutput)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := system.WriteLaunchAgentPlist(out, serviceLabel, binary, "start", "--foreground"); err != nil {
+		return err
+	}
+	if serviceOutput != "" {
+		fmt.Fprintf(system.Stdout, "✅ Wrote %s\n", serviceOutput)
+	}
+	return nil
+}
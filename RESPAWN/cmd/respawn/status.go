@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"RESPAWN/internal/checkpoint"
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+	"RESPAWN/pkg/config"
+)
+
+// Health states reported by status --json
+const (
+	healthHealthy  = "healthy"
+	healthDegraded = "degraded" // Running, but crashes, missing permissions or an overdue checkpoint
+	healthPaused   = "paused"
+	healthStopped  = "stopped"
+)
+
+// buildStatusSummary collects what `respawn status` shows into one machine-readable object
+func buildStatusSummary(checkpointMgr *checkpoint.CheckpointManager, startupMgr *system.StartupManager, checkpointList *checkpoint.CheckpointList) types.StatusSummary {
+	pid, running := runningDaemonPID()
+	summary := types.StatusSummary{
+		Version:          Version,
+		Running:          running,
+		PID:              pid,
+		AutoStartEnabled: startupMgr.IsEnabled(),
+		Profile:          checkpointMgr.Profile(),
+		TotalCheckpoints: checkpointList.TotalCount,
+	}
+
+	if until, paused := system.PausedUntil(); paused {
+		summary.Paused = true
+		if !until.IsZero() {
+			summary.PausedUntil = &until
+		}
+	}
+
+	recent, window, disabled := startupMgr.CrashStatus()
+	summary.Crashes = types.CrashSummary{Recent: recent, Window: window, AutoStartDisabled: disabled}
+
+	for _, cp := range checkpointList.Checkpoints {
+		summary.Storage.CheckpointBytes += cp.FileSize
+	}
+	if disk, err := checkpointMgr.DiskStatus(); err == nil {
+		summary.Storage.DiskFreeBytes = disk.Free
+		summary.Storage.DiskUsedPercent = disk.UsedPercent
+	}
+
+	overdue := false
+	if len(checkpointList.Checkpoints) > 0 {
+		latest := checkpointList.Checkpoints[0]
+		summary.LastCheckpoint = latest.Timestamp
+		summary.LastCheckpointID = latest.ID
+		if running && !summary.Paused {
+			next := latest.Timestamp.Add(config.Current().CheckpointInterval)
+			summary.NextCheckpoint = &next
+			overdue = time.Now().After(next)
+		}
+	}
+
+	if running {
+		if state, err := system.LoadDaemonState(); err == nil {
+			summary.CheckpointGate = state.CheckpointGate
+		}
+	}
+
+	missingPermission := false
+	if cache, err := system.LoadPermissionCache(); err == nil {
+		summary.Permissions = make(map[string]bool, len(cache.States))
+		for permission, granted := range cache.States {
+			summary.Permissions[string(permission)] = granted
+		}
+		checkedAt := cache.CheckedAt
+		summary.PermissionsCheckedAt = &checkedAt
+		missingPermission = !cache.States[system.PermissionAutomation] || !cache.States[system.PermissionAccessibility]
+	}
+
+	switch {
+	case !running:
+		summary.HealthStatus = healthStopped
+	case summary.Paused:
+		summary.HealthStatus = healthPaused
+	case disabled || missingPermission || overdue:
+		summary.HealthStatus = healthDegraded
+	default:
+		summary.HealthStatus = healthHealthy
+	}
+	return summary
+}
+
+// printStatusJSON writes the status summary to stdout as indented JSON
+func printStatusJSON(summary types.StatusSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(system.Stdout, string(data))
+	return nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/team"
+	"RESPAWN/pkg/config"
+)
+
+// Team command group
+var teamCmd = &cobra.Command{
+	Use:   "team",
+	Short: "Manage teams",
+	Long:  "Create, join and leave teams that share checkpoints. Membership and keys are kept in teams.json in the data directory",
+}
+
+// Team create command
+var teamCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a team",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTeamCommand("Create", func(store *team.Store) error {
+			t, err := store.Create(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(system.Stdout, "✅ Created team %s (%s)\n", t.Name, t.ID)
+			fmt.Fprintf(system.Stdout, "   Invite members with: respawn team invite %s <member-name>\n", t.Name)
+			fmt.Fprintf(system.Stdout, "   Give members your key yourself, not with the invite: %s\n", store.Identity.PublicKey)
+			return nil
+		})
+	},
+}
+
+// Team invite command
+var teamInviteCmd = &cobra.Command{
+	Use:   "invite <team> <member-name>",
+	Short: "Invite a member",
+	Long:  "Prints an invite code for a new member. The code doesn't contain the team key, which is only sent once you accept them",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTeamCommand("Invite", func(store *team.Store) error {
+			code, err := store.Invite(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(system.Stdout, "✉️  Invite code for %s:\n\n%s\n\n", args[1], code)
+			fmt.Fprintln(system.Stdout, "   They join with: respawn team join --owner-key <your key> <code>")
+			fmt.Fprintln(system.Stdout, "   Give them your key separately ('respawn team list' shows it), so a forged invite can't pass")
+			return nil
+		})
+	},
+}
+
+// teamOwnerKey is the owner's public key, checked against the invite's signature
+var teamOwnerKey string
+
+// Team join command
+var teamJoinCmd = &cobra.Command{
+	Use:   "join --owner-key <key> <invite-code>",
+	Short: "Join a team",
+	Long:  "Joins a team from an invite code. The owner's key must come from the owner, not from the invite, so a forged invite is refused",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTeamCommand("Join", func(store *team.Store) error {
+			t, code, err := store.Join(args[0], teamOwnerKey)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(system.Stdout, "✅ Joined team %s, pending until the owner accepts you and sends the key\n", t.Name)
+			fmt.Fprintf(system.Stdout, "   Send the owner this code for 'respawn team accept':\n\n%s\n", code)
+			return nil
+		})
+	},
+}
+
+// Team accept command
+var teamAcceptCmd = &cobra.Command{
+	Use:   "accept <acceptance-code>",
+	Short: "Accept a member who joined",
+	Long:  "Records the key of an invited member from the code 'respawn team join' printed for them, and prints the team key encrypted to them",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTeamCommand("Accept", func(store *team.Store) error {
+			t, member, grant, err := store.Accept(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(system.Stdout, "✅ %s is now a member of %s (%d members)\n", member.Name, t.Name, len(t.ActiveMembers()))
+			fmt.Fprintf(system.Stdout, "   Send them this code for 'respawn team activate':\n\n%s\n", grant)
+			return nil
+		})
+	},
+}
+
+// Team activate command
+var teamActivateCmd = &cobra.Command{
+	Use:   "activate <key-code>",
+	Short: "Finish joining a team",
+	Long:  "Receives the team key from the code 'respawn team accept' printed for the owner. Only this machine can decrypt it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTeamCommand("Activate", func(store *team.Store) error {
+			t, err := store.Activate(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(system.Stdout, "✅ You're now a member of %s\n", t.Name)
+			return nil
+		})
+	},
+}
+
+// Team leave command
+var teamLeaveCmd = &cobra.Command{
+	Use:   "leave <team>",
+	Short: "Leave a team",
+	Long:  "Forgets the team and its key on this machine",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTeamCommand("Leave", func(store *team.Store) error {
+			if err := store.Leave(args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(system.Stdout, "✅ Left team %s\n", args[0])
+			return nil
+		})
+	},
+}
+
+// Team list command
+var teamListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List teams and members",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runTeamCommand("List", func(store *team.Store) error {
+			listTeams(store)
+			return nil
+		})
+	},
+}
+
+// runTeamCommand loads the team store, runs action and saves any changes
+func runTeamCommand(name string, action func(store *team.Store) error) {
+	// The store lives in the configured data dir
+	var store *team.Store
+	err := config.LoadConfig()
+	if err != nil {
+		err = fmt.Errorf("Config load failed: %w", err)
+	} else {
+		store, err = team.Load()
+	}
+	if err == nil {
+		err = action(store)
+	}
+	if err == nil {
+		err = store.Save()
+	}
+	if err != nil {
+		printFailure(name, err)
+		os.Exit(1)
+	}
+}
+
+// listTeams prints every team with its members
+func listTeams(store *team.Store) {
+	fmt.Fprintf(system.Stdout, "👤 You: %s\n   Key: %s\n", store.Identity.Name, store.Identity.PublicKey)
+
+	if len(store.Teams) == 0 {
+		fmt.Fprintln(system.Stdout, "\nNo teams yet. Create one with: respawn team create <name>")
+		return
+	}
+
+	for _, t := range store.Teams {
+		if t.Pending {
+			fmt.Fprintf(system.Stdout, "\n👥 %s (%s) [waiting for the owner's key code]\n", t.Name, t.ID)
+		} else {
+			fmt.Fprintf(system.Stdout, "\n👥 %s (%s)\n", t.Name, t.ID)
+		}
+		for _, m := range t.Members {
+			var tags []string
+			if m.PublicKey == t.Owner {
+				tags = append(tags, "owner")
+			}
+			if m.PublicKey == store.Identity.PublicKey {
+				tags = append(tags, "you")
+			}
+			if m.Invited {
+				tags = append(tags, "invited")
+			}
+
+			if len(tags) > 0 {
+				fmt.Fprintf(system.Stdout, "   • %s %v\n", m.Name, tags)
+			} else {
+				fmt.Fprintf(system.Stdout, "   • %s\n", m.Name)
+			}
+		}
+	}
+}
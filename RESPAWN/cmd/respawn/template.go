@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"RESPAWN/internal/checkpoint"
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/workspace"
+	"RESPAWN/pkg/config"
+)
+
+// Template command flags
+var (
+	templateOutput  string // Output path for export and suggest
+	suggestAppCount int    // Maximum apps in a suggested template
+)
+
+// Apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply <template>",
+	Short: "Open the workspace described by a template",
+	Long: `Launches the apps in a YAML workspace template and opens their documents and URLs.
+<template> is a name from the templates folder in the data directory or a path to a .yaml file.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := handleApply(args[0]); err != nil {
+			printFailure("Apply", err)
+			system.FlushEvents(eventFlushTimeout)
+			os.Exit(1)
+		}
+	},
+}
+
+// Template command group
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage workspace templates",
+	Long:  "Workspace templates are YAML files in the data directory's templates folder describing apps, documents, URLs and window layout",
+}
+
+// Template export command
+var templateExportCmd = &cobra.Command{
+	Use:   "export <checkpoint-id> <name>",
+	Short: "Save a checkpoint as an editable template",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := handleTemplateExport(args[0], args[1]); err != nil {
+			printFailure("Export", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// Template suggest command
+var templateSuggestCmd = &cobra.Command{
+	Use:   "suggest [name]",
+	Short: "Propose a template from the apps you use most",
+	Long: `Builds a starter template from the app usage RESPAWN has learned (falling back to
+checkpoint history) and saves it for editing. The default name is "core".`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := "core"
+		if len(args) == 1 {
+			name = args[0]
+		}
+		if err := handleTemplateSuggest(name); err != nil {
+			printFailure("Suggest", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// Template list command
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved templates",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.LoadConfig(); err != nil {
+			printFailure("List", fmt.Errorf("Config load failed: %w", err))
+			os.Exit(1)
+		}
+		names, err := workspace.List()
+		if err != nil {
+			printFailure("List", err)
+			os.Exit(1)
+		}
+		if len(names) == 0 {
+			fmt.Fprintln(system.Stdout, "No templates yet. Export one with: respawn template export <checkpoint-id> <name>")
+			return
+		}
+		fmt.Fprintf(system.Stdout, "📐 Templates (%s):\n", workspace.Dir())
+		for _, name := range names {
+			fmt.Fprintf(system.Stdout, "   • %s\n", name)
+		}
+	},
+}
+
+// handleApply loads a template and opens its workspace
+func handleApply(nameOrPath string) error {
+	if err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("Config load failed: %w", err)
+	}
+	if err := system.InitLogger(); err != nil {
+		return fmt.Errorf("Logger initialization failed: %w", err)
+	}
+
+	t, err := workspace.Load(nameOrPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(system.Stdout, "📐 Applying %s (%d apps)...\n", t.Name, len(t.Apps))
+	results, err := t.Apply(restoreProgress(nil, system.Stdout))
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if !result.Success && !result.AlreadyRunning {
+			fmt.Fprintf(system.Stdout, "   ⚠️  %s: %s\n", result.AppName, result.ErrorMsg)
+		}
+	}
+	fmt.Fprintf(system.Stdout, "✅ Applied %s\n", t.Name)
+	return nil
+}
+
+// handleTemplateExport writes a checkpoint out as a named template
+func handleTemplateExport(checkpointID, name string) error {
+	if err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("Config load failed: %w", err)
+	}
+
+	checkpointMgr, err := checkpoint.NewCheckpointManager()
+	if err != nil {
+		return fmt.Errorf("Checkpoint manager creation failed: %w", err)
+	}
+
+	cp, err := checkpointMgr.GetCheckpoint(checkpointID)
+	if err != nil {
+		return err
+	}
+
+	path, err := workspace.FromCheckpoint(cp, name).Save(templateOutput)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(system.Stdout, "✅ Exported %s to %s\n", cp.ID, path)
+	target := name
+	if templateOutput != "" {
+		target = path
+	}
+	fmt.Fprintf(system.Stdout, "   Edit it to add documents, URLs and window positions, then: respawn apply %s\n", target)
+	return nil
+}
+
+// handleTemplateSuggest saves a template of the most used apps
+func handleTemplateSuggest(name string) error {
+	if err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("Config load failed: %w", err)
+	}
+
+	var frequency map[string]int
+	var top []string
+	if pattern, err := system.LoadWorkPattern(); err == nil {
+		frequency = pattern.AppUsageFrequency
+		top = pattern.TopThreeApps
+	}
+
+	// Before the daemon has learned anything, count apps across saved checkpoints
+	if len(frequency) == 0 {
+		summaries, err := checkpoint.ListSummariesFast(false)
+		if err != nil {
+			return err
+		}
+		frequency = make(map[string]int)
+		for _, summary := range summaries {
+			for _, app := range summary.Apps {
+				frequency[app]++
+			}
+		}
+	}
+
+	t, err := workspace.Suggest(name, frequency, top, suggestAppCount)
+	if err != nil {
+		return err
+	}
+
+	path, err := t.Save(templateOutput)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(system.Stdout, "✨ Suggested %s with %d apps:\n", t.Name, len(t.Apps))
+	for _, app := range t.Apps {
+		fmt.Fprintf(system.Stdout, "   • %s\n", app.Name)
+	}
+	fmt.Fprintf(system.Stdout, "\n   Saved to %s\n", path)
+	fmt.Fprintln(system.Stdout, "   Edit it to add documents, URLs and window positions, then apply it")
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"RESPAWN/internal/system"
+)
+
+// URL command, invoked by the URL handler applet for respawn:// links
+var urlCmd = &cobra.Command{
+	Use:   "url <respawn://action>",
+	Short: "Run a respawn:// URL",
+	Long: `Runs an action from a respawn:// URL, as opened by Shortcuts or Focus automations:
+
+  respawn://checkpoint                  create a checkpoint now
+  respawn://checkpoint?note=Focus+on    ... with a note
+  respawn://pause?for=2h                pause checkpoints for 2 hours (no "for": until resumed)
+  respawn://resume                      resume checkpoints
+  respawn://restore                     restore the latest checkpoint
+  respawn://restore?id=<checkpoint-id>  restore a specific checkpoint`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := handleURL(args[0]); err != nil {
+			system.Error("URL action failed:", args[0], err)
+			printFailure(args[0], err)
+			system.FlushEvents(eventFlushTimeout)
+			os.Exit(1)
+		}
+	},
+}
+
+// URL scheme command group
+var urlSchemeCmd = &cobra.Command{
+	Use:   "url-scheme",
+	Short: "Manage the respawn:// URL scheme",
+	Long:  "Registers respawn:// so Shortcuts and Focus automations can trigger checkpoints, pauses and restores",
+}
+
+// URL scheme install command
+var urlSchemeInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Register the respawn:// URL scheme",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := installURLScheme(); err != nil {
+			printFailure("URL scheme install", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(system.Stdout, "✅ %s:// links now run RESPAWN (handler: %s)\n", system.URLScheme, system.URLHandlerPath())
+		fmt.Fprintln(system.Stdout, "   In Shortcuts, use \"Open URLs\" with e.g. respawn://checkpoint")
+	},
+}
+
+// URL scheme uninstall command
+var urlSchemeUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Unregister the respawn:// URL scheme",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := system.UninstallURLHandler(); err != nil {
+			printFailure("URL scheme uninstall", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(system.Stdout, "✅ %s:// URL scheme removed\n", system.URLScheme)
+	},
+}
+
+// installURLScheme registers the handler applet for this binary
+func installURLScheme() error {
+	executablePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate respawn binary: %w", err)
+	}
+	return system.InstallURLHandler(executablePath)
+}
+
+// handleURL parses a respawn:// URL and runs the matching command
+func handleURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != system.URLScheme {
+		return fmt.Errorf("not a %s:// URL", system.URLScheme)
+	}
+
+	// respawn://restore/latest parses with Host "restore" and Path "/latest"
+	action := strings.ToLower(u.Host)
+	if action == "" {
+		action = strings.ToLower(strings.Trim(u.Path, "/"))
+	}
+	query := u.Query()
+	system.Info("Running URL action:", rawURL)
+
+	switch action {
+	case "checkpoint":
+		checkpointApps = query.Get("apps")
+		checkpointNote = query.Get("note")
+		return handleCheckpoint()
+
+	case "pause":
+		if value := query.Get("for"); value != "" {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid pause duration %q: %w", value, err)
+			}
+			pauseDuration = duration
+		}
+		return handlePause()
+
+	case "resume":
+		return handleResume()
+
+	case "restore":
+		checkpointID = query.Get("id")
+		if strings.Trim(u.Path, "/") == "latest" {
+			checkpointID = ""
+		}
+		silentMode = true
+		return handleRestore()
+
+	case "prune":
+		// Any page or app can open a respawn:// URL, and the handler has no terminal
+		// to confirm at, so deleting checkpoints stays with 'respawn prune'
+		return fmt.Errorf("prune isn't available from URLs, run 'respawn prune --free <size>'")
+	}
+
+	return fmt.Errorf("unknown action %q", action)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/pkg/config"
+)
+
+// setupWizard walks the user through first-run configuration in the terminal
+type setupWizard struct {
+	reader *bufio.Reader
+	cfg    *config.Config
+}
+
+// isTerminal reports whether stdin is attached to an interactive terminal
+func isTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// runSetupWizard asks for apps, interval, notification level and auto-restore, then saves the config
+func runSetupWizard() error {
+	system.Info("Running terminal setup wizard")
+
+	if err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	w := &setupWizard{
+		reader: bufio.NewReader(os.Stdin),
+		cfg:    config.Current(),
+	}
+
+	fmt.Fprintln(system.Stdout, buildWelcomeMessage())
+	fmt.Fprintln(system.Stdout, "Let's set up RESPAWN. Press Enter to accept the [default].")
+
+	w.chooseApplications()
+	w.chooseInterval()
+	w.chooseNotificationLevel()
+	w.chooseAutoRestore()
+
+	if err := w.cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := w.cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(system.Stdout, "\n✅ Configuration saved to %s\n\n", w.cfg.ConfigPath)
+	return nil
+}
+
+// chooseApplications lets the user pick tracked apps and add new ones
+func (w *setupWizard) chooseApplications() {
+	fmt.Fprintln(system.Stdout, "\n1. Applications to track:")
+	for i, app := range w.cfg.Applications {
+		fmt.Fprintf(system.Stdout, "   %d. %s\n", i+1, app.Name)
+	}
+
+	answer := w.ask("Numbers to track, comma-separated", "all")
+	if answer != "all" {
+		selected := make(map[int]bool)
+		for _, field := range strings.Split(answer, ",") {
+			if n, err := strconv.Atoi(strings.TrimSpace(field)); err == nil {
+				selected[n-1] = true
+			}
+		}
+		for i := range w.cfg.Applications {
+			w.cfg.Applications[i].Enabled = selected[i]
+		}
+	}
+
+	extra := w.ask("Other apps to track (names as shown in the Dock), comma-separated", "none")
+	if extra != "none" {
+		for _, name := range strings.Split(extra, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			w.cfg.Applications = append(w.cfg.Applications, config.AppConfig{
+				Name:        name,
+				ProcessName: name,
+				Enabled:     true,
+			})
+		}
+	}
+}
+
+// chooseInterval asks for the checkpoint interval in minutes
+func (w *setupWizard) chooseInterval() {
+	current := strconv.Itoa(int(w.cfg.CheckpointInterval.Minutes()))
+	for {
+		answer := w.ask("\n2. Checkpoint interval in minutes", current)
+		minutes, err := strconv.Atoi(answer)
+		if err == nil && minutes > 0 {
+			w.cfg.CheckpointInterval = time.Duration(minutes) * time.Minute
+			return
+		}
+		fmt.Fprintln(system.Stdout, "   Please enter a whole number of minutes greater than 0")
+	}
+}
+
+// chooseNotificationLevel asks which notifications should be shown
+func (w *setupWizard) chooseNotificationLevel() {
+	for {
+		answer := strings.ToLower(w.ask("\n3. Notifications (all, errors, none)", w.cfg.NotificationLevel))
+		switch answer {
+		case "all", "errors", "none":
+			w.cfg.NotificationLevel = answer
+			return
+		}
+		fmt.Fprintln(system.Stdout, "   Please answer all, errors or none")
+	}
+}
+
+// chooseAutoRestore asks whether to restore automatically after a restart
+func (w *setupWizard) chooseAutoRestore() {
+	current := "y"
+	if !w.cfg.AutoRestore {
+		current = "n"
+	}
+	answer := strings.ToLower(w.ask("\n4. Restore automatically after a restart? (y/n)", current))
+	w.cfg.AutoRestore = answer == "y" || answer == "yes"
+}
+
+// ask prints a prompt and returns the trimmed answer or the default
+func (w *setupWizard) ask(prompt, def string) string {
+	fmt.Fprintf(system.Stdout, "%s [%s]: ", prompt, def)
+	answer, err := w.reader.ReadString('\n')
+	if err != nil {
+		return def
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def
+	}
+	return answer
+}
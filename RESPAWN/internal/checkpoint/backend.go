@@ -0,0 +1,311 @@
+package checkpoint
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "os"
+    "path"
+    "path/filepath"
+    "sort"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "time"
+
+    "RESPAWN/internal/system"
+)
+
+// StorageBackend is where Storage keeps checkpoint and metadata files.
+// Names are slash-separated and relative to the backend root, e.g. "ID.bin"
+// or "metadata/ID.json". Backends can be composed, e.g. a local disk mirrored elsewhere.
+type StorageBackend interface {
+    // Put stores data under name, replacing any existing object atomically
+    Put(name string, data []byte) error
+    // Get returns the data stored under name
+    Get(name string) ([]byte, error)
+    // List returns the names of the objects directly inside dir ("" for the root)
+    List(dir string) ([]string, error)
+    // Delete removes name; deleting a missing object is not an error
+    Delete(name string) error
+    // Stat describes the object stored under name
+    Stat(name string) (ObjectInfo, error)
+}
+
+// StreamingBackend is implemented by backends that can read an object
+// incrementally instead of returning all of it at once
+type StreamingBackend interface {
+    Open(name string) (io.ReadCloser, error)
+}
+
+// RemoteBackend is implemented by backends that serve objects from another machine,
+// e.g. an object store or a sync service. Checkpoints they serve are downloaded and
+// checked against their checksum before anything is decoded.
+type RemoteBackend interface {
+    Remote() bool
+}
+
+// openObject opens name for reading, streaming it when the backend supports it
+func openObject(backend StorageBackend, name string) (io.ReadCloser, error) {
+    if streaming, ok := backend.(StreamingBackend); ok {
+        return streaming.Open(name)
+    }
+    data, err := backend.Get(name)
+    if err != nil {
+        return nil, err
+    }
+    return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+    Name    string
+    Size    int64
+    ModTime time.Time
+}
+
+// ErrNotExist is returned by backends when an object is missing
+var ErrNotExist = os.ErrNotExist
+
+// LocalBackend stores objects as files under a root directory
+type LocalBackend struct {
+    root string
+}
+
+// NewLocalBackend creates a backend rooted at dir
+func NewLocalBackend(dir string) *LocalBackend {
+    return &LocalBackend{root: dir}
+}
+
+func (b *LocalBackend) path(name string) string {
+    return filepath.Join(b.root, filepath.FromSlash(name))
+}
+
+// Put writes to a temp file and renames it into place
+func (b *LocalBackend) Put(name string, data []byte) error {
+    target := b.path(name)
+    if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+        return err
+    }
+
+    tmp := target + tempSuffix
+    if err := os.WriteFile(tmp, data, 0644); err != nil {
+        os.Remove(tmp)
+        return err
+    }
+    if err := os.Rename(tmp, target); err != nil {
+        os.Remove(tmp)
+        return err
+    }
+    return nil
+}
+
+func (b *LocalBackend) Get(name string) ([]byte, error) {
+    return os.ReadFile(b.path(name))
+}
+
+func (b *LocalBackend) Open(name string) (io.ReadCloser, error) {
+    return os.Open(b.path(name))
+}
+
+func (b *LocalBackend) List(dir string) ([]string, error) {
+    entries, err := os.ReadDir(b.path(dir))
+    if err != nil {
+        if os.IsNotExist(err) {
+            return nil, nil
+        }
+        return nil, err
+    }
+
+    var names []string
+    for _, entry := range entries {
+        if !entry.IsDir() {
+            names = append(names, path.Join(dir, entry.Name()))
+        }
+    }
+    return names, nil
+}
+
+func (b *LocalBackend) Delete(name string) error {
+    if err := os.Remove(b.path(name)); err != nil && !os.IsNotExist(err) {
+        return err
+    }
+    return nil
+}
+
+func (b *LocalBackend) Stat(name string) (ObjectInfo, error) {
+    info, err := os.Stat(b.path(name))
+    if err != nil {
+        return ObjectInfo{}, err
+    }
+    return ObjectInfo{Name: name, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// MemoryBackend keeps objects in memory, for tests and dry runs
+type MemoryBackend struct {
+    mu      sync.Mutex
+    objects map[string]memoryObject
+}
+
+type memoryObject struct {
+    data    []byte
+    modTime time.Time
+}
+
+// NewMemoryBackend creates an empty in-memory backend
+func NewMemoryBackend() *MemoryBackend {
+    return &MemoryBackend{objects: make(map[string]memoryObject)}
+}
+
+func (b *MemoryBackend) Put(name string, data []byte) error {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    b.objects[name] = memoryObject{data: append([]byte(nil), data...), modTime: time.Now()}
+    return nil
+}
+
+func (b *MemoryBackend) Get(name string) ([]byte, error) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    object, ok := b.objects[name]
+    if !ok {
+        return nil, ErrNotExist
+    }
+    return append([]byte(nil), object.data...), nil
+}
+
+func (b *MemoryBackend) List(dir string) ([]string, error) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    prefix := ""
+    if dir != "" {
+        prefix = strings.TrimSuffix(dir, "/") + "/"
+    }
+
+    var names []string
+    for name := range b.objects {
+        if strings.HasPrefix(name, prefix) && !strings.Contains(name[len(prefix):], "/") {
+            names = append(names, name)
+        }
+    }
+    sort.Strings(names)
+    return names, nil
+}
+
+func (b *MemoryBackend) Delete(name string) error {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    delete(b.objects, name)
+    return nil
+}
+
+func (b *MemoryBackend) Stat(name string) (ObjectInfo, error) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    object, ok := b.objects[name]
+    if !ok {
+        return ObjectInfo{}, ErrNotExist
+    }
+    return ObjectInfo{Name: name, Size: int64(len(object.data)), ModTime: object.modTime}, nil
+}
+
+// MirrorBackend reads from a primary backend and writes to it and every mirror.
+// Mirror failures are logged but never fail the operation.
+type MirrorBackend struct {
+    primary StorageBackend
+    mirrors []StorageBackend
+    limits  func() SyncLimits      // See SetSyncLimits
+    lock    func() (func(), error) // See SetLocker
+    syncing atomic.Bool
+}
+
+// NewMirrorBackend composes a primary backend with one or more mirrors
+func NewMirrorBackend(primary StorageBackend, mirrors ...StorageBackend) *MirrorBackend {
+    return &MirrorBackend{primary: primary, mirrors: mirrors}
+}
+
+func (b *MirrorBackend) Put(name string, data []byte) error {
+    if err := b.primary.Put(name, data); err != nil {
+        return err
+    }
+    if b.currentLimits().limited() {
+        go func() {
+            defer system.RecordPanics()
+            if err := b.Sync(); err != nil {
+                system.Warn("Mirror sync failed:", err)
+            }
+        }()
+        return nil
+    }
+    for _, mirror := range b.mirrors {
+        if err := mirror.Put(name, data); err != nil {
+            system.Warn("Failed to mirror", name, ":", err)
+        }
+    }
+    return nil
+}
+
+func (b *MirrorBackend) Get(name string) ([]byte, error) {
+    return b.primary.Get(name)
+}
+
+func (b *MirrorBackend) Open(name string) (io.ReadCloser, error) {
+    return openObject(b.primary, name)
+}
+
+func (b *MirrorBackend) List(dir string) ([]string, error) {
+    return b.primary.List(dir)
+}
+
+func (b *MirrorBackend) Delete(name string) error {
+    if err := b.primary.Delete(name); err != nil {
+        return err
+    }
+    for _, mirror := range b.mirrors {
+        if err := mirror.Delete(name); err != nil {
+            system.Warn("Failed to delete mirrored", name, ":", err)
+        }
+    }
+    return nil
+}
+
+func (b *MirrorBackend) Stat(name string) (ObjectInfo, error) {
+    return b.primary.Stat(name)
+}
+
+// objectName joins name parts into a backend object name
+func objectName(parts ...string) string {
+    return path.Join(parts...)
+}
+
+// isRemote reports whether reads from b come from a remote source. Backends that
+// don't say are treated as remote, so a new backend is verified until it opts out.
+func isRemote(b StorageBackend) bool {
+    switch backend := b.(type) {
+    case *LocalBackend, *MemoryBackend:
+        return false
+    case *MirrorBackend:
+        return isRemote(backend.primary)
+    case RemoteBackend:
+        return backend.Remote()
+    }
+    return true
+}
+
+// describeBackend names a backend for logs
+func describeBackend(b StorageBackend) string {
+    switch backend := b.(type) {
+    case *LocalBackend:
+        return "local:" + backend.root
+    case *MemoryBackend:
+        return "memory"
+    case *MirrorBackend:
+        names := []string{describeBackend(backend.primary)}
+        for _, mirror := range backend.mirrors {
+            names = append(names, describeBackend(mirror))
+        }
+        return "mirror(" + strings.Join(names, ", ") + ")"
+    }
+    return fmt.Sprintf("%T", b)
+}
@@ -0,0 +1,201 @@
+package checkpoint
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "path"
+    "regexp"
+    "strings"
+    "time"
+
+    "RESPAWN/internal/system"
+    "RESPAWN/pkg/config"
+)
+
+// conflictMarker matches what sync clients append to a file name when two machines
+// wrote it: " 2" (iCloud Drive), " (MacBook's conflicted copy 2024-06-01)" (Dropbox)
+// or "(1)" (Google Drive). Checkpoint IDs never contain spaces or parentheses.
+var conflictMarker = regexp.MustCompile(`(?: \d+| ?\([^)]*\))$`)
+
+// conflictedCopy returns the name a conflicted copy was made of, e.g. "ID.bin" for
+// "ID 2.bin", and false for names that aren't conflicted copies
+func conflictedCopy(name string) (string, bool) {
+    dir, base := path.Split(name)
+    ext := path.Ext(base)
+    stem := strings.TrimSuffix(base, ext)
+    loc := conflictMarker.FindStringIndex(stem)
+    if loc == nil || loc[0] == 0 {
+        return "", false
+    }
+    return dir + stem[:loc[0]] + ext, true
+}
+
+// isConflictedCopy reports whether name is a sync client's conflicted copy. Listings
+// skip them until resolveConflicts has run, so one checkpoint never shows up twice.
+func isConflictedCopy(name string) bool {
+    _, ok := conflictedCopy(name)
+    return ok
+}
+
+// resolveConflicts settles the conflicted copies of checkpoint files by the
+// sync_conflicts policy and drops conflicted metadata, rebuilding the metadata of
+// every checkpoint involved from its file. The result is the same whichever machine
+// runs it. Returns what was done, one entry per copy; the caller holds the storage lock.
+func (s *Storage) resolveConflicts() ([]string, error) {
+    files, err := s.backend.List("")
+    if err != nil {
+        return nil, err
+    }
+
+    var resolved []string
+    involved := make(map[string]bool)
+    for _, name := range files {
+        original, ok := conflictedCopy(name)
+        if !ok || !strings.HasSuffix(original, ".bin") {
+            continue
+        }
+        kept, err := s.resolveConflict(name, original)
+        if err != nil {
+            system.Warn("Failed to resolve conflicted copy", name, ":", err)
+            continue
+        }
+        resolved = append(resolved, name+" -> "+kept)
+        involved[checkpointIDFromName(original)] = true
+        involved[checkpointIDFromName(kept)] = true
+    }
+
+    // Metadata is derived from the checkpoint file, so a conflicted copy of it carries
+    // nothing worth merging
+    metadataNames, err := s.backend.List(metadataDir)
+    if err != nil {
+        return resolved, err
+    }
+    for _, name := range metadataNames {
+        original, ok := conflictedCopy(name)
+        if !ok {
+            continue
+        }
+        if s.removeObject(name) {
+            resolved = append(resolved, name+" -> removed")
+            involved[strings.TrimSuffix(path.Base(original), ".json")] = true
+        }
+    }
+
+    for checkpointID := range involved {
+        if err := s.rebuildConflictMetadata(checkpointID); err != nil {
+            system.Warn("Failed to rebuild metadata for", checkpointID, ":", err)
+        }
+    }
+
+    if len(resolved) > 0 {
+        system.Info("Resolved", len(resolved), "sync conflicts")
+    }
+    return resolved, nil
+}
+
+// resolveConflict settles one conflicted copy of original and returns the name its
+// contents ended up under, or "removed" when they were dropped
+func (s *Storage) resolveConflict(name, original string) (string, error) {
+    data, err := s.backend.Get(name)
+    if err != nil {
+        return "", err
+    }
+
+    originalData, err := s.backend.Get(original)
+    if errors.Is(err, ErrNotExist) {
+        // Only the copy made it, it takes the original's place
+        return original, s.replaceObject(name, original, data)
+    }
+    if err != nil {
+        return "", err
+    }
+
+    checksum := s.calculateChecksum(data)
+    originalChecksum := s.calculateChecksum(originalData)
+    if checksum == originalChecksum {
+        return "removed", s.backend.Delete(name)
+    }
+
+    cfg := config.Current()
+    if cfg == nil {
+        cfg = config.DefaultConfig()
+    }
+
+    if cfg.SyncConflicts == config.ConflictsNewest {
+        if !s.copyWins(original, data, originalData, checksum, originalChecksum) {
+            return "removed", s.backend.Delete(name)
+        }
+        return original, s.replaceObject(name, original, data)
+    }
+
+    // Keep both: the copy becomes its own checkpoint, named after its contents so
+    // every machine renames it the same way
+    checkpointID := checkpointIDFromName(original)
+    renamed := strings.Replace(original, checkpointID, checkpointID+"-"+checksum[:8], 1)
+    if _, err := s.backend.Stat(renamed); err == nil {
+        return renamed, s.backend.Delete(name) // Renamed already, on another machine
+    }
+    return renamed, s.replaceObject(name, renamed, data)
+}
+
+// copyWins decides between two versions of original for the newest policy: the
+// later checkpoint timestamp wins, and the lower checksum breaks a tie so every
+// machine picks the same one. File times aren't used, sync clients set them when
+// each machine downloads the file. A version that can't be decoded loses.
+func (s *Storage) copyWins(original string, data, originalData []byte, checksum, originalChecksum string) bool {
+    taken, err := s.checkpointTimestamp(original, data)
+    if err != nil {
+        return false
+    }
+    originalTaken, err := s.checkpointTimestamp(original, originalData)
+    if err != nil {
+        return true
+    }
+    if !taken.Equal(originalTaken) {
+        return taken.After(originalTaken)
+    }
+    return checksum < originalChecksum
+}
+
+// checkpointTimestamp decodes when the checkpoint in data, stored under name, was taken
+func (s *Storage) checkpointTimestamp(name string, data []byte) (time.Time, error) {
+    if strings.HasSuffix(name, "_compressed.bin") {
+        decompressed, err := s.decompressor.DecodeAll(data, nil)
+        if err != nil {
+            return time.Time{}, fmt.Errorf("Failed to decompress checkpoint: %w", err)
+        }
+        data = decompressed
+    }
+    checkpoint, err := s.deserializeCheckpoint(bytes.NewReader(data))
+    if err != nil {
+        return time.Time{}, err
+    }
+    return checkpoint.Timestamp, nil
+}
+
+// replaceObject stores data, read from name, under target and deletes name
+func (s *Storage) replaceObject(name, target string, data []byte) error {
+    if err := s.backend.Put(target, data); err != nil {
+        return fmt.Errorf("failed to write %s: %w", target, err)
+    }
+    return s.backend.Delete(name)
+}
+
+// rebuildConflictMetadata rewrites a checkpoint's metadata from its file, keeping
+// the note. Checkpoints whose file is gone are left to garbage collection.
+func (s *Storage) rebuildConflictMetadata(checkpointID string) error {
+    name := s.checkpointName(checkpointID)
+    if _, err := s.backend.Stat(name); err != nil {
+        return nil
+    }
+
+    metadata, err := s.metadataFromFile(name)
+    if err != nil {
+        return err
+    }
+    if existing, err := s.loadMetadata(checkpointID); err == nil && existing.Note != "" {
+        metadata.Note = existing.Note
+    }
+    return s.saveMetadata(metadata)
+}
@@ -0,0 +1,70 @@
+package checkpoint
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+
+    "RESPAWN/internal/types"
+)
+
+// digestApps is how many apps a digest names
+const digestApps = 3
+
+// Digest summarizes a checkpoint in one line for notifications and menus, e.g.
+// "8 apps · Google Chrome (2 profiles) · Code ×2 · 3 MB"
+func Digest(cp *types.Checkpoint) string {
+    // Count instances per app, keeping capture order for ties
+    var order []string
+    instances := make(map[string]int)
+    profiles := make(map[string]int)
+    for _, proc := range cp.Processes {
+        if instances[proc.Name] == 0 {
+            order = append(order, proc.Name)
+        }
+        instances[proc.Name]++
+        profiles[proc.Name] += len(proc.Profiles)
+    }
+    if len(order) == 0 {
+        // Summaries from metadata carry app names only
+        for _, name := range cp.AppNames {
+            if instances[name] == 0 {
+                order = append(order, name)
+            }
+            instances[name]++
+        }
+    }
+
+    parts := []string{pluralize(len(order), "app")}
+
+    ranked := append([]string{}, order...)
+    sort.SliceStable(ranked, func(i, j int) bool {
+        return instances[ranked[i]] > instances[ranked[j]]
+    })
+    for i, name := range ranked {
+        if i >= digestApps {
+            break
+        }
+        part := name
+        if profiles[name] > 1 {
+            part += fmt.Sprintf(" (%d profiles)", profiles[name])
+        }
+        if instances[name] > 1 {
+            part += fmt.Sprintf(" ×%d", instances[name])
+        }
+        parts = append(parts, part)
+    }
+
+    if cp.FileSize > 0 {
+        parts = append(parts, FormatBytes(cp.FileSize))
+    }
+    return strings.Join(parts, " · ")
+}
+
+// pluralize formats a count with its noun, e.g. "1 app" or "8 apps"
+func pluralize(count int, noun string) string {
+    if count == 1 {
+        return fmt.Sprintf("1 %s", noun)
+    }
+    return fmt.Sprintf("%d %ss", count, noun)
+}
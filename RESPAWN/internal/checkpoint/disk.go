@@ -0,0 +1,178 @@
+package checkpoint
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "syscall"
+    "time"
+
+    "RESPAWN/internal/system"
+    "RESPAWN/internal/types"
+    "RESPAWN/pkg/config"
+)
+
+// diskAlertInterval keeps the low-disk alert from repeating every maintenance run
+const diskAlertInterval = 24 * time.Hour
+
+// DiskStatus describes the volume holding the checkpoints
+type DiskStatus struct {
+    Total       uint64
+    Free        uint64
+    UsedPercent float64
+    Critical    bool // Above disk_critical_percent, not just disk_warn_percent
+}
+
+// PrunePlan is the set of oldest checkpoints whose removal frees the requested space
+type PrunePlan struct {
+    Checkpoints []types.Checkpoint
+    Bytes       int64 // Space the plan frees
+    Requested   int64 // Space that was asked for; more than Bytes if checkpoints can't cover it
+}
+
+// IDs returns the IDs of the checkpoints in the plan
+func (p *PrunePlan) IDs() []string {
+    ids := make([]string, len(p.Checkpoints))
+    for i, cp := range p.Checkpoints {
+        ids[i] = cp.ID
+    }
+    return ids
+}
+
+// SetDiskAlertHandler sets the function called when disk usage crosses a threshold,
+// with a plan that would bring usage back under disk_warn_percent
+func (cm *CheckpointManager) SetDiskAlertHandler(handler func(status DiskStatus, plan *PrunePlan)) {
+    cm.mu.Lock()
+    defer cm.mu.Unlock()
+    cm.diskAlertHandler = handler
+}
+
+// DiskStatus reports how full the checkpoint volume is
+func (cm *CheckpointManager) DiskStatus() (DiskStatus, error) {
+    var fs syscall.Statfs_t
+    if err := syscall.Statfs(cm.checkpointDir, &fs); err != nil {
+        return DiskStatus{}, fmt.Errorf("failed to stat checkpoint volume: %w", err)
+    }
+
+    status := DiskStatus{
+        Total: fs.Blocks * uint64(fs.Bsize),
+        Free:  fs.Bavail * uint64(fs.Bsize),
+    }
+    if status.Total > 0 {
+        status.UsedPercent = float64(status.Total-status.Free) / float64(status.Total) * 100
+    }
+    status.Critical = status.UsedPercent >= float64(config.Current().DiskCriticalPercent)
+    return status, nil
+}
+
+// PlanPrune picks the oldest checkpoints that free at least bytes. The newest
+// checkpoint and the last one restored are always kept.
+func (cm *CheckpointManager) PlanPrune(bytes int64) (*PrunePlan, error) {
+    list, err := cm.GetAvailableCheckpoints()
+    if err != nil {
+        return nil, err
+    }
+
+    plan := &PrunePlan{Requested: bytes}
+    // Newest first, so walk backwards from the oldest
+    for i := len(list.Checkpoints) - 1; i > 0 && plan.Bytes < bytes; i-- {
+        cp := list.Checkpoints[i]
+        if cp.ID == list.LastUsed {
+            continue
+        }
+        plan.Checkpoints = append(plan.Checkpoints, cp)
+        plan.Bytes += cp.FileSize
+    }
+    return plan, nil
+}
+
+// Prune deletes the checkpoints in a plan and returns how many were removed
+func (cm *CheckpointManager) Prune(plan *PrunePlan) (int, error) {
+    deleted, err := cm.storage.DeleteCheckpoints(plan.IDs())
+    cm.recordRemovals()
+    if err != nil {
+        return deleted, err
+    }
+    system.Info("Pruned", deleted, "checkpoints, freeing", FormatBytes(plan.Bytes))
+    return deleted, nil
+}
+
+// AlertDiskSpace checks the checkpoint volume now and alerts even if an alert went
+// out recently, for re-delivering a snoozed one
+func (cm *CheckpointManager) AlertDiskSpace() error {
+    cm.mu.Lock()
+    cm.lastDiskAlert = time.Time{}
+    cm.mu.Unlock()
+    return cm.checkDiskSpace()
+}
+
+// checkDiskSpace alerts through the disk alert handler when the checkpoint volume
+// is above disk_warn_percent, suggesting which checkpoints to prune
+func (cm *CheckpointManager) checkDiskSpace() error {
+    status, err := cm.DiskStatus()
+    if err != nil {
+        return err
+    }
+
+    warnPercent := float64(config.Current().DiskWarnPercent)
+    if status.UsedPercent < warnPercent {
+        return nil
+    }
+    system.Warn(fmt.Sprintf("Checkpoint volume is %.0f%% full", status.UsedPercent))
+
+    cm.mu.Lock()
+    handler := cm.diskAlertHandler
+    recentlyAlerted := time.Since(cm.lastDiskAlert) < diskAlertInterval && !status.Critical
+    if handler != nil && !recentlyAlerted {
+        cm.lastDiskAlert = time.Now()
+    }
+    cm.mu.Unlock()
+
+    if handler == nil || recentlyAlerted {
+        return nil
+    }
+
+    // Free enough to get back under the warning threshold
+    target := uint64(float64(status.Total) * (100 - warnPercent) / 100)
+    plan, err := cm.PlanPrune(int64(target - status.Free))
+    if err != nil {
+        return err
+    }
+    handler(status, plan)
+    return nil
+}
+
+// FormatBytes renders a size for people, e.g. "800 MB" or "1.2 GB"
+func FormatBytes(bytes int64) string {
+    switch {
+    case bytes >= 1<<30:
+        return fmt.Sprintf("%.1f GB", float64(bytes)/(1<<30))
+    case bytes >= 1<<20:
+        return fmt.Sprintf("%d MB", bytes>>20)
+    case bytes >= 1<<10:
+        return fmt.Sprintf("%d KB", bytes>>10)
+    }
+    return fmt.Sprintf("%d B", bytes)
+}
+
+// ParseSize parses sizes like "1GB", "800MB" or "500M"; a bare number is bytes
+func ParseSize(value string) (int64, error) {
+    s := strings.ToUpper(strings.TrimSpace(value))
+    multiplier := int64(1)
+    for _, unit := range []struct {
+        suffix string
+        size   int64
+    }{{"GB", 1 << 30}, {"G", 1 << 30}, {"MB", 1 << 20}, {"M", 1 << 20}, {"KB", 1 << 10}, {"K", 1 << 10}, {"B", 1}} {
+        if strings.HasSuffix(s, unit.suffix) {
+            s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+            multiplier = unit.size
+            break
+        }
+    }
+
+    number, err := strconv.ParseFloat(s, 64)
+    if err != nil || number <= 0 {
+        return 0, fmt.Errorf("invalid size %q, use e.g. 1GB or 800MB", value)
+    }
+    return int64(number * float64(multiplier)), nil
+}
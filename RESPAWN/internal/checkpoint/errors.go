@@ -0,0 +1,21 @@
+package checkpoint
+
+import "errors"
+
+// Errors returned by the manager and storage, so callers can branch with errors.Is
+// instead of matching message text
+var (
+    // ErrCheckpointNotFound means no checkpoint matches the requested ID, or none exist yet
+    ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+    // ErrCorruptCheckpoint means a checkpoint exists but fails its checksum or can't be decoded
+    ErrCorruptCheckpoint = errors.New("checkpoint is corrupt")
+
+    // ErrUnverifiedCheckpoint means a remote backend served a checkpoint without a
+    // checksum to check it against, so it is not restored
+    ErrUnverifiedCheckpoint = errors.New("checkpoint can't be verified")
+
+    // ErrOtherMachine means a checkpoint was taken on another Mac and restoring it
+    // wasn't asked for explicitly
+    ErrOtherMachine = errors.New("checkpoint is from another machine")
+)
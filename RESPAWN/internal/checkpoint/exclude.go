@@ -0,0 +1,103 @@
+package checkpoint
+
+import (
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+
+    "RESPAWN/internal/system"
+    "RESPAWN/pkg/config"
+)
+
+// neverIndexFile tells Spotlight to skip the directory holding it
+const neverIndexFile = ".metadata_never_index"
+
+// checkpointRoot returns the directory holding every profile's checkpoints, in the
+// configured data_dir
+func checkpointRoot() (string, error) {
+    cfg := config.Current()
+    if cfg == nil {
+        cfg = config.DefaultConfig()
+    }
+    if cfg.DataDir == "" {
+        return "", fmt.Errorf("no data directory configured")
+    }
+    return filepath.Join(cfg.DataDir, "checkpoints"), nil
+}
+
+// ApplyBackupExclusions keeps Time Machine and Spotlight away from the checkpoint
+// directory, or lets them back in, per exclude_from_time_machine and
+// exclude_from_spotlight. Checkpoints churn constantly, so backing them up and
+// indexing them wastes space and I/O. Safe to call on every start.
+func ApplyBackupExclusions() error {
+    root, err := checkpointRoot()
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(root, 0755); err != nil {
+        return fmt.Errorf("Failed to create checkpoint directory: %w", err)
+    }
+
+    excludeTimeMachine, excludeSpotlight := true, true
+    if config.Current() != nil {
+        excludeTimeMachine = config.Current().ExcludeFromTimeMachine
+        excludeSpotlight = config.Current().ExcludeFromSpotlight
+    }
+
+    var errs []string
+    if err := setTimeMachineExclusion(root, excludeTimeMachine); err != nil {
+        errs = append(errs, err.Error())
+    }
+    if err := setSpotlightExclusion(root, excludeSpotlight); err != nil {
+        errs = append(errs, err.Error())
+    }
+    if len(errs) > 0 {
+        return fmt.Errorf("%s", strings.Join(errs, "; "))
+    }
+    return nil
+}
+
+// setTimeMachineExclusion adds or removes the sticky Time Machine exclusion on dir
+func setTimeMachineExclusion(dir string, exclude bool) error {
+    output, err := exec.Command("tmutil", "isexcluded", dir).Output()
+    if err != nil {
+        return fmt.Errorf("tmutil isexcluded failed: %w", err)
+    }
+    if strings.Contains(string(output), "[Excluded]") == exclude {
+        return nil // Already as configured
+    }
+
+    action := "removeexclusion"
+    if exclude {
+        action = "addexclusion"
+    }
+    if output, err := exec.Command("tmutil", action, dir).CombinedOutput(); err != nil {
+        return fmt.Errorf("tmutil %s failed: %w (%s)", action, err, strings.TrimSpace(string(output)))
+    }
+
+    system.Info("Time Machine exclusion of", dir, "set to", exclude)
+    return nil
+}
+
+// setSpotlightExclusion creates or removes the never-index marker in dir
+func setSpotlightExclusion(dir string, exclude bool) error {
+    marker := filepath.Join(dir, neverIndexFile)
+    _, statErr := os.Stat(marker)
+    exists := statErr == nil
+
+    switch {
+    case exclude && !exists:
+        if err := os.WriteFile(marker, nil, 0644); err != nil {
+            return fmt.Errorf("failed to create %s: %w", neverIndexFile, err)
+        }
+        system.Info("Excluded", dir, "from Spotlight")
+    case !exclude && exists:
+        if err := os.Remove(marker); err != nil {
+            return fmt.Errorf("failed to remove %s: %w", neverIndexFile, err)
+        }
+        system.Info("Spotlight may index", dir, "again")
+    }
+    return nil
+}
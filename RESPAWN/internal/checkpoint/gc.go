@@ -0,0 +1,202 @@
+package checkpoint
+
+import (
+    "os"
+    "path"
+    "path/filepath"
+    "strings"
+    "time"
+
+    "RESPAWN/internal/system"
+)
+
+// gcGracePeriod is how old temp files and snapshot directories must be before they
+// count as leftovers. A capture writes both before its .bin exists, and garbage
+// collection must not mistake a checkpoint in progress for an abandoned one.
+const gcGracePeriod = time.Hour
+
+// GCReport lists what a garbage collection pass cleaned up
+type GCReport struct {
+    OrphanMetadata    []string // Metadata or notes without a checkpoint file, deleted
+    RebuiltMetadata   []string // Checkpoint files without metadata that were still readable
+    Quarantined       []string // Unreadable checkpoint files without metadata, moved to quarantine
+    Duplicates        []string // Leftovers from interrupted compressions, deleted
+    TempFiles         []string // Half-written files older than gcGracePeriod, deleted
+    OrphanDirs        []string // Session/state-file snapshots of deleted checkpoints
+    Conflicts         []string // Conflicted copies left by sync clients, resolved
+}
+
+// Total returns how many items were cleaned up
+func (r *GCReport) Total() int {
+    return len(r.OrphanMetadata) + len(r.RebuiltMetadata) + len(r.Quarantined) +
+        len(r.Duplicates) + len(r.TempFiles) + len(r.OrphanDirs) + len(r.Conflicts)
+}
+
+// CollectGarbage removes files that no longer belong to a usable checkpoint.
+// These accumulate after failed compressions and interrupted writes.
+func (s *Storage) CollectGarbage() (*GCReport, error) {
+    system.Debug("Collecting orphaned checkpoint files")
+
+    unlock, err := s.lockExclusive()
+    if err != nil {
+        return nil, err
+    }
+    defer unlock()
+
+    // Conflicts first, so their files are sorted out before anything is judged orphaned
+    conflicts, err := s.resolveConflicts()
+    if err != nil {
+        return nil, err
+    }
+
+    files, err := s.backend.List("")
+    if err != nil {
+        return nil, err
+    }
+
+    report := &GCReport{Conflicts: conflicts}
+    plain := make(map[string]bool)
+    compressed := make(map[string]bool)
+
+    for _, name := range files {
+        switch {
+        case isConflictedCopy(name):
+            // Left by a failed resolution, kept for the next run
+        case strings.HasSuffix(name, tempSuffix):
+            if info, err := s.backend.Stat(name); err != nil || time.Since(info.ModTime) < gcGracePeriod {
+                continue // Possibly still being written
+            }
+            if s.removeObject(name) {
+                report.TempFiles = append(report.TempFiles, name)
+            }
+        case strings.HasSuffix(name, "_compressed.bin"):
+            compressed[strings.TrimSuffix(name, "_compressed.bin")] = true
+        case strings.HasSuffix(name, ".bin"):
+            plain[strings.TrimSuffix(name, ".bin")] = true
+        }
+    }
+
+    // Both versions exist when removing the original after compression failed,
+    // or when compression died before metadata was updated
+    for checkpointID := range compressed {
+        if !plain[checkpointID] {
+            continue
+        }
+
+        compressedName := checkpointID + "_compressed.bin"
+        plainName := checkpointID + ".bin"
+
+        remove := compressedName
+        if metadata, err := s.loadMetadata(checkpointID); err == nil && metadata.IsCompressed {
+            remove = plainName
+        }
+        if s.removeObject(remove) {
+            report.Duplicates = append(report.Duplicates, remove)
+        }
+        if remove == plainName {
+            delete(plain, checkpointID)
+        } else {
+            delete(compressed, checkpointID)
+        }
+    }
+
+    exists := func(checkpointID string) bool {
+        return plain[checkpointID] || compressed[checkpointID]
+    }
+
+    // Checkpoint files without metadata are rebuilt if readable, removed if not
+    for checkpointID := range plain {
+        s.collectUnindexed(checkpointID, checkpointID+".bin", report)
+    }
+    for checkpointID := range compressed {
+        s.collectUnindexed(checkpointID, checkpointID+"_compressed.bin", report)
+    }
+
+    // Metadata without a checkpoint file
+    metadataNames, _ := s.backend.List(metadataDir)
+    for _, name := range metadataNames {
+        if isConflictedCopy(name) {
+            continue
+        }
+        checkpointID := strings.TrimSuffix(path.Base(name), ".json")
+        if exists(checkpointID) {
+            continue
+        }
+        if s.removeObject(name) {
+            report.OrphanMetadata = append(report.OrphanMetadata, checkpointID)
+        }
+    }
+
+    // Notes of checkpoints that are gone
+    noteNames, _ := s.backend.List(notesDir)
+    for _, name := range noteNames {
+        if isConflictedCopy(name) {
+            continue
+        }
+        checkpointID := strings.TrimSuffix(path.Base(name), ".txt")
+        if exists(checkpointID) {
+            continue
+        }
+        if s.removeObject(name) {
+            report.OrphanMetadata = append(report.OrphanMetadata, checkpointID)
+        }
+    }
+
+    // Snapshot directories of checkpoints that are gone
+    for _, dir := range []string{"sessions", "state-files"} {
+        entries, _ := os.ReadDir(filepath.Join(s.baseDir, dir))
+        for _, entry := range entries {
+            if !entry.IsDir() || exists(entry.Name()) {
+                continue
+            }
+            if info, err := entry.Info(); err != nil || time.Since(info.ModTime()) < gcGracePeriod {
+                continue // The capture writing it hasn't saved its checkpoint yet
+            }
+            dirPath := filepath.Join(s.baseDir, dir, entry.Name())
+            if err := os.RemoveAll(dirPath); err != nil {
+                system.Warn("Failed to remove", dirPath, ":", err)
+                continue
+            }
+            report.OrphanDirs = append(report.OrphanDirs, filepath.Join(dir, entry.Name()))
+        }
+    }
+
+    if report.Total() > 0 {
+        system.Info("Garbage collection cleaned", report.Total(), "items")
+    }
+    return report, nil
+}
+
+// collectUnindexed handles a checkpoint file that has no metadata
+func (s *Storage) collectUnindexed(checkpointID, name string, report *GCReport) {
+    if _, err := s.loadMetadata(checkpointID); err == nil {
+        return
+    }
+
+    metadata, err := s.metadataFromFile(name)
+    if err != nil {
+        // Kept aside rather than deleted, it may still be recoverable by hand
+        system.Warn("Quarantining unreadable checkpoint without metadata", checkpointID, ":", err)
+        if err := s.quarantineFile(name); err != nil {
+            system.Warn("Failed to quarantine", name, ":", err)
+            return
+        }
+        report.Quarantined = append(report.Quarantined, checkpointID)
+        return
+    }
+
+    if err := s.saveMetadata(metadata); err != nil {
+        system.Warn("Failed to rebuild metadata for", checkpointID, ":", err)
+        return
+    }
+    report.RebuiltMetadata = append(report.RebuiltMetadata, checkpointID)
+}
+
+// removeObject deletes an object from the backend, logging failures
+func (s *Storage) removeObject(name string) bool {
+    if err := s.backend.Delete(name); err != nil {
+        system.Warn("Failed to remove", name, ":", err)
+        return false
+    }
+    return true
+}
@@ -0,0 +1,91 @@
+package checkpoint
+
+import (
+    "sort"
+    "time"
+
+    "RESPAWN/internal/system"
+    "RESPAWN/internal/types"
+)
+
+// CheckpointSummary is the compact listing entry printed by `respawn list --json`,
+// meant for launcher extensions (Raycast, Alfred) showing a checkpoint picker
+type CheckpointSummary struct {
+    ID          string    `json:"id"`
+    Timestamp   time.Time `json:"timestamp"`
+    Apps        []string  `json:"apps"`
+    Note        string    `json:"note,omitempty"`
+    CaptureMode string    `json:"capture_mode,omitempty"`
+    Partial     bool      `json:"partial,omitempty"`
+    Trigger     string    `json:"trigger,omitempty"`
+    Compressed  bool      `json:"compressed"`
+    Size        int64     `json:"size"`
+    Hostname    string    `json:"hostname,omitempty"`
+    ThisMachine bool      `json:"this_machine"`
+}
+
+// ListSummariesFast lists checkpoints from metadata only, newest first. It skips
+// building a manager (and its compressors) and never reads .bin files, so it
+// answers in milliseconds; checkpoints with missing metadata are left out.
+// Checkpoints from other Macs are only listed with allMachines.
+func ListSummariesFast(allMachines bool) ([]CheckpointSummary, error) {
+    checkpointDir, err := checkpointStorageDir()
+    if err != nil {
+        return nil, err
+    }
+
+    storage := &Storage{baseDir: checkpointDir, backend: newBackend(checkpointDir)}
+    checkpoints, err := storage.LoadMetadataSummaries()
+    if err != nil {
+        return nil, err
+    }
+    if !allMachines {
+        checkpoints = thisMachineOnly(checkpoints)
+    }
+    return summarize(checkpoints), nil
+}
+
+// ListSummaries lists every checkpoint, newest first, loading checkpoints whose metadata is missing
+func (cm *CheckpointManager) ListSummaries() ([]CheckpointSummary, error) {
+    list, err := cm.GetAvailableCheckpoints()
+    if err != nil {
+        return nil, err
+    }
+    return summarize(list.Checkpoints), nil
+}
+
+// summarize converts checkpoints to summaries sorted newest first
+func summarize(checkpoints []types.Checkpoint) []CheckpointSummary {
+    sort.Slice(checkpoints, func(i, j int) bool {
+        return checkpoints[i].Timestamp.After(checkpoints[j].Timestamp)
+    })
+
+    summaries := make([]CheckpointSummary, len(checkpoints))
+    for i, cp := range checkpoints {
+        summaries[i] = CheckpointSummary{
+            ID:          cp.ID,
+            Timestamp:   cp.Timestamp,
+            Apps:        cp.AppNames,
+            Note:        cp.Note,
+            CaptureMode: cp.CaptureMode,
+            Partial:     cp.Partial,
+            Trigger:     cp.Trigger,
+            Compressed:  cp.IsCompressed,
+            Size:        cp.FileSize,
+            Hostname:    cp.Hostname,
+            ThisMachine: system.IsThisMachine(cp.MachineID),
+        }
+    }
+    return summaries
+}
+
+// thisMachineOnly drops the checkpoints taken on other Macs
+func thisMachineOnly(checkpoints []types.Checkpoint) []types.Checkpoint {
+    local := checkpoints[:0]
+    for _, cp := range checkpoints {
+        if system.IsThisMachine(cp.MachineID) {
+            local = append(local, cp)
+        }
+    }
+    return local
+}
@@ -0,0 +1,48 @@
+package checkpoint
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "syscall"
+
+    "RESPAWN/internal/system"
+)
+
+// lockFileName is the advisory lock shared by every respawn process using a checkpoint directory
+const lockFileName = ".lock"
+
+// lockExclusive blocks until this process is the only one touching the checkpoint
+// directory. Saves, compressions and cleanups hold it so the daemon and a manual
+// `respawn checkpoint` can't interleave their writes.
+func (s *Storage) lockExclusive() (func(), error) {
+    return s.lock(syscall.LOCK_EX)
+}
+
+// lockShared blocks while another process holds the exclusive lock.
+// Readers hold it so they never see a checkpoint halfway through compression.
+func (s *Storage) lockShared() (func(), error) {
+    return s.lock(syscall.LOCK_SH)
+}
+
+// lock takes an flock on the directory's lock file and returns the release function.
+// Each call opens its own descriptor, so goroutines in one process exclude each other too.
+// Callers must not nest lock calls: an exclusive request under a held lock deadlocks.
+func (s *Storage) lock(how int) (func(), error) {
+    file, err := os.OpenFile(filepath.Join(s.baseDir, lockFileName), os.O_CREATE|os.O_RDWR, 0644)
+    if err != nil {
+        return nil, fmt.Errorf("Failed to open storage lock: %w", err)
+    }
+
+    if err := syscall.Flock(int(file.Fd()), how); err != nil {
+        file.Close()
+        return nil, fmt.Errorf("Failed to lock checkpoint storage: %w", err)
+    }
+
+    return func() {
+        if err := syscall.Flock(int(file.Fd()), syscall.LOCK_UN); err != nil {
+            system.Warn("Failed to release storage lock:", err)
+        }
+        file.Close()
+    }, nil
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 
@@ -20,6 +21,24 @@ type CheckpointManager struct {
 	checkpointDir string
 	storage       *Storage 
 	detector      *process.ProcessDetector
+
+	mu sync.Mutex
+
+	diskAlertHandler func(status DiskStatus, plan *PrunePlan)
+	lastDiskAlert    time.Time
+
+	restoreProgress         process.ProgressFunc
+	includeManualOnly       bool
+	checkpointBeforeRestore bool
+	allMachines             bool // See SetAllMachines
+	metrics                 MetricsRecorder
+}
+
+// MetricsRecorder receives performance data about checkpoint operations.
+// The system monitor implements it to feed its optimization metrics.
+type MetricsRecorder interface {
+	RecordCheckpointSize(size int64, appNames []string)
+	RecordCheckpointsRemoved(bytes int64)
 }
 
 
@@ -33,21 +52,18 @@ type CheckpointList struct {
 
 // NewCheckpointManager creates a new checkpoint manager
 func NewCheckpointManager() (*CheckpointManager, error) {
-	homeDir, err := os.UserHomeDir()
+	checkpointDir, err := checkpointStorageDir()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to get home directory: %w", err)
-	}
-
-	checkpointDir := filepath.Join(homeDir, ".respawn", "checkpoints")
-	if err := os.MkdirAll(checkpointDir, 0755); err != nil {
-		return nil, fmt.Errorf("Failed to create checkpoint directory: %w", err)
+		return nil, err
 	}
 
-	storage, err := NewStorage(checkpointDir)
+	storage, err := NewStorageWithBackend(checkpointDir, newBackend(checkpointDir))
 	if err != nil {
 		return nil, fmt.Errorf("Failed to initialize storage: %w", err)
 	}
 
+	applyStorageSettings(storage)
+
 	return &CheckpointManager{
 		checkpointDir: checkpointDir,
         storage:           storage,
@@ -55,23 +71,262 @@ func NewCheckpointManager() (*CheckpointManager, error) {
     }, nil
 }
 
-// Creates a new system checkpoint
-func (cm *CheckpointManager) CreateCheckpoint() (*types.Checkpoint, error) {
+// applyStorageSettings applies the configured compression level and debug side-car
+func applyStorageSettings(storage *Storage) {
+	storage.SetDebugJSON(config.Current() != nil && config.Current().WriteDebugJSON)
+	if config.Current() != nil && config.Current().CompressionLevel > 0 && config.Current().CompressionLevel != storage.compressionLevel {
+		if err := storage.SetCompressionLevel(config.Current().CompressionLevel); err != nil {
+			system.Warn("Ignoring compression level:", err)
+		}
+	}
+}
+
+// ApplyConfig picks up a reloaded config: the monitored apps and storage settings.
+// The profile and storage location only change on restart.
+func (cm *CheckpointManager) ApplyConfig() error {
+	unlock, err := cm.storage.lockExclusive()
+	if err != nil {
+		return err
+	}
+	applyStorageSettings(cm.storage)
+	unlock()
+
+	cm.mu.Lock()
+	cm.detector = process.NewProcessDetector()
+	cm.mu.Unlock()
+	return nil
+}
+
+// processDetector returns the detector for the current config
+func (cm *CheckpointManager) processDetector() *process.ProcessDetector {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.detector
+}
+
+// checkpointStorageDir returns the checkpoint directory, creating it if needed
+func checkpointStorageDir() (string, error) {
+	root, err := checkpointRoot()
+	if err != nil {
+		return "", err
+	}
+
+	checkpointDir := filepath.Join(root, profileSubdir())
+	if err := os.MkdirAll(checkpointDir, 0755); err != nil {
+		return "", fmt.Errorf("Failed to create checkpoint directory: %w", err)
+	}
+	return checkpointDir, nil
+}
+
+// profilesDir holds one checkpoint stream per named profile; the default profile
+// stays at the top level so existing checkpoints remain where they were
+const profilesDir = "profiles"
+
+// activeProfile returns the configured profile, empty for the default stream
+func activeProfile() string {
+	if config.Current() == nil {
+		return os.Getenv(config.ProfileEnv)
+	}
+	return config.Current().ActiveProfile()
+}
+
+// profileSubdir returns the active profile's directory relative to the checkpoint root
+func profileSubdir() string {
+	if profile := activeProfile(); profile != "" {
+		return filepath.Join(profilesDir, profile)
+	}
+	return ""
+}
+
+// newBackend builds the configured storage backend for checkpointDir
+func newBackend(checkpointDir string) StorageBackend {
+	var backend StorageBackend = NewLocalBackend(checkpointDir)
+	if config.Current() != nil && config.Current().MirrorDir != "" {
+		mirrorDir := filepath.Join(config.Current().MirrorDir, profileSubdir())
+		mirror := NewMirrorBackend(backend, NewLocalBackend(mirrorDir))
+		mirror.SetSyncLimits(syncLimitsFromConfig)
+		backend = mirror
+	}
+	return backend
+}
+
+// Creates a new system checkpoint, tagged with what triggered it (config.Trigger*)
+func (cm *CheckpointManager) CreateCheckpoint(trigger string) (*types.Checkpoint, error) {
+	start := time.Now()
+	checkpoint, err := cm.createCheckpoint(trigger)
+	cm.recordMetrics(start, checkpoint, err)
+	return emitCheckpointResult(checkpoint, err)
+}
+
+// createCheckpoint does the work of CreateCheckpoint
+func (cm *CheckpointManager) createCheckpoint(trigger string) (*types.Checkpoint, error) {
 	system.Info("Creating new checkpoint")
+	system.Publish(system.EventCheckpointStarted, nil)
 
 	// Detect running processes
-	processes, err := cm.detector.DetectRunningProcesses()
+	processes, err := cm.processDetector().DetectRunningProcesses()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to detect running processes: %w", err)
 	}
+	publishDetected(processes)
 
 	if len(processes) == 0 {
 		system.Warn ("No target application running, creating empty checkpoint")
 	}
 
-	// Create Checkpoint
+	checkpoint := cm.newCheckpoint(processes, trigger)
+	checkpointID := checkpoint.ID
+
+	// Record mounted network shares so restore can bring them back
+	if config.Current().CaptureNetworkVolumes {
+		volumes, err := process.DetectNetworkVolumes()
+		if err != nil {
+			system.Warn("Failed to detect network volumes:", err)
+		}
+		checkpoint.Volumes = volumes
+	}
+
+	if config.Current().CaptureAudioState {
+		audio, err := process.DetectAudioState()
+		if err != nil {
+			system.Warn("Failed to detect audio state:", err)
+		}
+		checkpoint.Audio = audio
+	}
+
+	if config.Current().RestorePlaybackState {
+		checkpoint.Playback = process.DetectPlaybackState()
+	}
+
+	if config.Current().CaptureDeveloperState {
+		checkpoint.Developer = process.DetectDeveloperState()
+		if checkpoint.Developer != nil && !config.Current().CaptureAllowed(config.PrivacyFull) {
+			checkpoint.Developer.XcodeDocuments = nil // Document paths need full window privacy
+		}
+	}
+
+	if config.Current().CaptureVirtualMachines {
+		checkpoint.VirtualMachines = process.DetectVirtualMachines()
+	}
+
+	// Snapshot the browsers' own session files for exact tab recovery; they hold every URL
+	if config.Current().CaptureBrowserSessions && config.Current().CaptureAllowed(config.PrivacyFull) {
+		sessionDir := filepath.Join(cm.checkpointDir, "sessions", checkpointID)
+		for _, proc := range processes {
+			if !process.IsSessionBrowser(proc.Name) {
+				continue
+			}
+			snapshots, err := process.CaptureSessionFiles(proc.Name, proc.Profiles, sessionDir)
+			if err != nil {
+				system.Warn("Failed to capture session files for", proc.Name, ":", err)
+			}
+			checkpoint.Sessions = append(checkpoint.Sessions, snapshots...)
+		}
+	}
+
+	// Snapshot window-state files of apps that persist their own geometry. They also
+	// hold workspace and document paths, so only at full privacy
+	if config.Current().CaptureWindowStateFiles && config.Current().CaptureAllowed(config.PrivacyFull) {
+		stateDir := filepath.Join(cm.checkpointDir, "state-files", checkpointID)
+		for _, proc := range processes {
+			if files := process.WindowStateFilesFor(proc.Name); len(files) > 0 {
+				checkpoint.WindowStateFiles = append(checkpoint.WindowStateFiles,
+					process.CaptureWindowStateFiles(proc.Name, files, stateDir)...)
+			}
+		}
+	}
+
+	// Record display arrangement so restore can validate the layout
+	displays, err := process.DetectDisplays()
+	if err != nil {
+		system.Debug("Failed to detect displays:", err)
+	}
+	checkpoint.Displays = displays
+	checkpoint.Providers = buildManifest(checkpoint, cm.processDetector().IsBasicMode())
+
+	return cm.saveCheckpoint(checkpoint)
+}
+
+// CreatePartialCheckpoint snapshots only the named apps, skipping the full detection pass
+func (cm *CheckpointManager) CreatePartialCheckpoint(appNames []string) (*types.Checkpoint, error) {
+	start := time.Now()
+	checkpoint, err := cm.createPartialCheckpoint(appNames)
+	cm.recordMetrics(start, checkpoint, err)
+	return emitCheckpointResult(checkpoint, err)
+}
+
+// SetMetricsRecorder sets where checkpoint timings are reported; nil stops reporting
+func (cm *CheckpointManager) SetMetricsRecorder(recorder MetricsRecorder) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.metrics = recorder
+}
+
+// recordMetrics reports how long a successful checkpoint took, from detection to
+// saved file, and what it contained
+func (cm *CheckpointManager) recordMetrics(start time.Time, checkpoint *types.Checkpoint, err error) {
+	cm.mu.Lock()
+	recorder := cm.metrics
+	cm.mu.Unlock()
+
+	if recorder == nil || err != nil {
+		return
+	}
+	duration := time.Since(start)
+	system.Debug("Checkpoint took", duration)
+	recorder.RecordCheckpointSize(checkpoint.FileSize, checkpoint.AppNames)
+}
+
+// recordRemovals reports the space freed by checkpoints deleted since the last report
+func (cm *CheckpointManager) recordRemovals() {
+	cm.mu.Lock()
+	recorder := cm.metrics
+	cm.mu.Unlock()
+
+	freed := cm.storage.TakeFreedBytes()
+	if recorder != nil {
+		recorder.RecordCheckpointsRemoved(freed)
+	}
+}
+
+// createPartialCheckpoint does the work of CreatePartialCheckpoint
+func (cm *CheckpointManager) createPartialCheckpoint(appNames []string) (*types.Checkpoint, error) {
+	system.Info("Creating partial checkpoint for", strings.Join(appNames, ", "))
+	system.Publish(system.EventCheckpointStarted, map[string]interface{}{"apps": appNames})
+
+	apps := process.ResolveApps(appNames)
+	if len(apps) == 0 {
+		return nil, fmt.Errorf("No applications given")
+	}
+
+	processes, err := cm.processDetector().DetectProcesses(apps)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to detect running processes: %w", err)
+	}
+
+	if len(processes) == 0 {
+		return nil, fmt.Errorf("None of the given applications are running")
+	}
+	publishDetected(processes)
+
+	checkpoint := cm.newCheckpoint(processes, config.TriggerManual) // Only ever requested by hand
+	checkpoint.Partial = true
+	checkpoint.Providers = buildManifest(checkpoint, cm.processDetector().IsBasicMode())
+
+	return cm.saveCheckpoint(checkpoint)
+}
+
+// checkpointIDLayout names new checkpoints after the time they were taken, e.g.
+// "2024-06-01_09-30-00". Older versions used "2006-01-15_15-04-05", which puts the
+// hour where the day belongs ("2024-06-09_09-30-00" for the same checkpoint), so a
+// directory can hold both. IDs are only names: listings sort by the checkpoint
+// Timestamp and nothing parses a time back out of an ID.
+const checkpointIDLayout = "2006-01-02_15-04-05"
+
+// newCheckpoint builds a checkpoint for the detected processes, stamped now
+func (cm *CheckpointManager) newCheckpoint(processes []types.ProcessInfo, trigger string) *types.Checkpoint {
 	timestamp := time.Now()
-	checkpointID := timestamp.Format("2006-01-15_15-04-05")
+	checkpointID := timestamp.Format(checkpointIDLayout)
 
 	// Extract app names for descriptive naming 
 	appNames := make([]string, len(processes))
@@ -85,9 +340,52 @@ func (cm *CheckpointManager) CreateCheckpoint() (*types.Checkpoint, error) {
         Processes:   processes,
         AppNames:    appNames,
         IsCompressed: false,	
+        CaptureMode:  "full",
+        Trigger:      trigger,
+        Hostname:     system.ThisMachine().Hostname,
+        MachineID:    system.ThisMachine().UUID,
 	}
-	
-	// Save checkpoint to storage
+
+	// Without Accessibility only the app list is captured
+	if cm.processDetector().IsBasicMode() {
+		checkpoint.CaptureMode = "basic"
+	}
+
+	return checkpoint
+}
+
+// publishDetected streams each app a checkpoint is about to capture
+func publishDetected(processes []types.ProcessInfo) {
+	for _, proc := range processes {
+		system.Publish(system.EventAppDetected, map[string]interface{}{
+			"name": proc.Name,
+			"pid":  proc.PID,
+		})
+	}
+}
+
+// emitCheckpointResult tells webhooks whether a checkpoint was created and passes the result through
+func emitCheckpointResult(checkpoint *types.Checkpoint, err error) (*types.Checkpoint, error) {
+	if err != nil {
+		system.Emit(system.EventCheckpointFailed, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	system.Emit(system.EventCheckpointCreated, map[string]interface{}{
+		"id":           checkpoint.ID,
+		"apps":         checkpoint.AppNames,
+		"size":         checkpoint.FileSize,
+		"capture_mode": checkpoint.CaptureMode,
+		"partial":      checkpoint.Partial,
+		"trigger":      checkpoint.Trigger,
+	})
+	return checkpoint, nil
+}
+
+// saveCheckpoint writes a checkpoint to storage and records where it went
+func (cm *CheckpointManager) saveCheckpoint(checkpoint *types.Checkpoint) (*types.Checkpoint, error) {
 	filePath, fileSize, err := cm.storage.SaveCheckpoint(checkpoint) 
 	if err != nil {
 		return nil, fmt.Errorf("Failed to save checkpoint: %w", err)
@@ -109,6 +407,9 @@ func (cm *CheckpointManager) GetAvailableCheckpoints() (*CheckpointList, error)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to load checkpoints: %w", err)
 	}
+	if !cm.showsAllMachines() {
+		checkpoints = thisMachineOnly(checkpoints)
+	}
 
 	// Sort by  timestamp (newest first)
 	sort.Slice(checkpoints, func(i, j int) bool {
@@ -131,29 +432,221 @@ func (cm *CheckpointManager) GetAvailableCheckpoints() (*CheckpointList, error)
     }, nil
 }
 
+// SetRestoreProgressHandler sets the function told about each app as restores proceed
+func (cm *CheckpointManager) SetRestoreProgressHandler(handler process.ProgressFunc) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.restoreProgress = handler
+}
+
+// SetAllMachines makes listings include, and restores accept, checkpoints taken on
+// other Macs syncing the same checkpoint directory. By default only this Mac's are used.
+func (cm *CheckpointManager) SetAllMachines(all bool) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.allMachines = all
+}
+
+// showsAllMachines reports whether SetAllMachines was switched on
+func (cm *CheckpointManager) showsAllMachines() bool {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.allMachines
+}
+
+// SetIncludeManualOnly makes restores also launch apps marked manual restore only
+func (cm *CheckpointManager) SetIncludeManualOnly(include bool) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.includeManualOnly = include
+}
+
+// SetCheckpointBeforeRestore makes restores first save the workspace they replace
+// as a pre-restore checkpoint
+func (cm *CheckpointManager) SetCheckpointBeforeRestore(enabled bool) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.checkpointBeforeRestore = enabled
+}
+
 // RestoreFromCheckpoint restores system state from a specific checkpoint
 func (cm *CheckpointManager) RestoreFromCheckpoint(checkpointID string) ([]types.LaunchResult, error) {
 	system.Info("Restoring from checkpoint:", checkpointID)
 
-	// Load the specific checkpoint
-	checkpoint, err := cm.storage.LoadCheckpoint(checkpointID)
+	// Load the specific checkpoint; the ID may be a prefix or note, so use the resolved one from here on
+	checkpoint, err := cm.storage.LoadCheckpointByID(checkpointID)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to load checkpoint %s: %w", checkpointID, err)
 	}
+	checkpointID = checkpoint.ID
+	if !cm.showsAllMachines() && !system.IsThisMachine(checkpoint.MachineID) {
+		return nil, fmt.Errorf("%w: %s was taken on %s", ErrOtherMachine, checkpointID, checkpoint.Hostname)
+	}
 
 	system.Info("Loaded checkpoint:", cm.formatCheckpointName(checkpoint))
 	system.Debug("Checkpoint contains", len(checkpoint.Processes), "applications")
+	for _, missing := range MissingProviders(checkpoint) {
+		system.Info("Checkpoint has no", strings.Join(missing.Categories, "/"), "to restore:", missing.Name, missing.Reason)
+	}
+	for _, change := range process.CompareVersions(checkpoint.Processes) {
+		system.Warn(change.App, "was updated from", change.From, "to", change.To, "since this checkpoint")
+	}
+
+	// Keep the workspace being replaced, so restoring an older checkpoint can be undone
+	cm.mu.Lock()
+	checkpointFirst := cm.checkpointBeforeRestore
+	cm.mu.Unlock()
+	if checkpointFirst {
+		if saved, err := cm.CreateCheckpoint(config.TriggerPreRestore); err != nil {
+			system.Warn("Failed to checkpoint before restoring:", err)
+		} else {
+			system.Info("Saved the current workspace as", saved.ID)
+		}
+	}
 
 	// Update last used checkpoint
 	cm.updateLastUsedCheckpoint(checkpointID)
 
 	// Launch applications
-	launcher := process.NewApplicationLauncher()
+	launcher := cm.newLauncher()
+	launcher.SetQueueSource(checkpointID)
+	cm.restoreBeforeLaunch(checkpoint, nil)
+	cm.applyDisplayLayout(checkpoint, launcher)
 	results, err := launcher.RestoreApplications(checkpoint.Processes)
 	if err != nil {
 		return results, fmt.Errorf("Failed to restore applications: %w", err)
 	}
 
+	cm.restoreAfterLaunch(checkpoint, launcher)
+	return results, nil
+} 
+
+// unmountedVolumes lists the sources of checkpointed shares that aren't mounted now
+func unmountedVolumes(volumes []types.MountedVolume) []string {
+	var missing []string
+	for _, volume := range volumes {
+		if _, err := os.Stat(volume.MountPoint); os.IsNotExist(err) {
+			missing = append(missing, volume.Source)
+		}
+	}
+	return missing
+}
+
+// restoreBeforeLaunch puts back what apps read when they start: network shares,
+// audio, browser sessions and window-state files. With apps set, sessions and
+// window-state files are only restored for those apps.
+func (cm *CheckpointManager) restoreBeforeLaunch(checkpoint *types.Checkpoint, apps map[string]bool) {
+	// Remount network shares first - documents on them are useless otherwise
+	if config.Current().RemountNetworkVolumes && len(checkpoint.Volumes) > 0 {
+		remounted, failedVolumes := process.RemountVolumes(checkpoint.Volumes)
+		if len(remounted) > 0 {
+			system.Info("Remounted volumes:", strings.Join(remounted, ", "))
+		}
+		if len(failedVolumes) > 0 {
+			system.Warn("Failed to remount volumes:", strings.Join(failedVolumes, ", "))
+		}
+	} else if missing := unmountedVolumes(checkpoint.Volumes); len(missing) > 0 {
+		system.Info("Network shares not mounted, set remount_network_volumes to remount them on restore:", strings.Join(missing, ", "))
+	}
+
+	if config.Current().RestoreAudioState && checkpoint.Audio != nil {
+		if err := process.RestoreAudioState(checkpoint.Audio); err != nil {
+			system.Warn("Failed to restore audio state:", err)
+		}
+	}
+
+	// Put browser session files back before the browsers launch and read them
+	var sessions []types.SessionSnapshot
+	for _, session := range checkpoint.Sessions {
+		if apps == nil || apps[session.AppName] {
+			sessions = append(sessions, session)
+		}
+	}
+	if len(sessions) > 0 {
+		restored := process.RestoreSessionFiles(sessions)
+		if len(restored) > 0 {
+			system.Info("Restored browser sessions:", strings.Join(restored, ", "))
+		}
+	}
+
+	var stateFiles []types.StateFileSnapshot
+	for _, file := range checkpoint.WindowStateFiles {
+		if apps == nil || apps[file.AppName] {
+			stateFiles = append(stateFiles, file)
+		}
+	}
+	if len(stateFiles) > 0 {
+		restored := process.RestoreWindowStateFiles(stateFiles)
+		if len(restored) > 0 {
+			system.Info("Restored window-state files:", strings.Join(restored, ", "))
+		}
+	}
+}
+
+// applyDisplayLayout compares the display arrangement and makes launcher fall
+// back to a degraded layout if it changed
+func (cm *CheckpointManager) applyDisplayLayout(checkpoint *types.Checkpoint, launcher *process.ApplicationLauncher) {
+	if len(checkpoint.Displays) == 0 {
+		return
+	}
+	currentDisplays, err := process.DetectDisplays()
+	if err != nil {
+		system.Debug("Failed to detect current displays:", err)
+	}
+	strategy, warning := process.CompareDisplays(checkpoint.Displays, currentDisplays)
+	if warning != "" {
+		system.Warn(warning)
+		fmt.Fprintf(system.Stdout, "⚠️  %s\n", warning)
+	}
+	launcher.SetLayoutStrategy(strategy)
+}
+
+// restoreAfterLaunch restores the state that needs its apps running, then
+// reports how the launches went
+func (cm *CheckpointManager) restoreAfterLaunch(checkpoint *types.Checkpoint, launcher *process.ApplicationLauncher) {
+	// Xcode is launched like any other app, its documents and simulators follow
+	if checkpoint.Developer != nil {
+		if err := process.RestoreDeveloperState(checkpoint.Developer); err != nil {
+			system.Warn("Failed to restore developer state:", err)
+		}
+	}
+
+	// Compose projects only restart with explicit opt-in
+	if checkpoint.Developer != nil && len(checkpoint.Developer.ComposeProjects) > 0 {
+		if config.Current().RestoreDockerProjects {
+			started, failed := process.RestoreComposeProjects(checkpoint.Developer.ComposeProjects)
+			if len(started) > 0 {
+				fmt.Fprintf(system.Stdout, "🐳 Started compose projects: %s\n", strings.Join(started, ", "))
+			}
+			if len(failed) > 0 {
+				fmt.Fprintf(system.Stdout, "⚠️  Failed to start compose projects: %s\n", strings.Join(failed, ", "))
+			}
+		} else {
+			var names []string
+			for _, project := range checkpoint.Developer.ComposeProjects {
+				names = append(names, project.Name)
+			}
+			fmt.Fprintf(system.Stdout, "💡 Docker compose projects were running: %s (set restore_docker_projects to start them)\n", strings.Join(names, ", "))
+		}
+	}
+
+	if len(checkpoint.VirtualMachines) > 0 {
+		started, failed := process.RestoreVirtualMachines(checkpoint.VirtualMachines, config.Current().VMRestoreExclude)
+		if len(started) > 0 {
+			fmt.Fprintf(system.Stdout, "🖥️  Started VMs: %s\n", strings.Join(started, ", "))
+		}
+		if len(failed) > 0 {
+			fmt.Fprintf(system.Stdout, "⚠️  Failed to start VMs: %s\n", strings.Join(failed, ", "))
+		}
+	}
+
+	// Players must be running again before playback can resume
+	if config.Current().RestorePlaybackState && len(checkpoint.Playback) > 0 {
+		if err := process.RestorePlaybackState(checkpoint.Playback); err != nil {
+			system.Warn("Failed to restore playback state:", err)
+		}
+	}
+
 	successful, failed, failedApps := launcher.GetLaunchSummary()
 	system.Info ("Restoration completed - Success:", successful, "Failed:", failed)
 
@@ -161,8 +654,70 @@ func (cm *CheckpointManager) RestoreFromCheckpoint(checkpointID string) ([]types
 		system.Warn("Failed applications:", strings.Join(failedApps, ", "))
 	} 
 
-	return results, nil
-} 
+	system.Emit(system.EventRestoreCompleted, map[string]interface{}{
+		"id":          checkpoint.ID,
+		"successful":  successful,
+		"failed":      failed,
+		"failed_apps": failedApps,
+	})
+}
+
+// EstimateCompressionSavings reports how much smaller checkpoints would be at level, in percent
+func (cm *CheckpointManager) EstimateCompressionSavings(level int) (float64, error) {
+	return cm.storage.EstimateCompressionSavings(level)
+}
+
+// RecompressCheckpoints re-encodes every compressed checkpoint at a new zstd level
+func (cm *CheckpointManager) RecompressCheckpoints(level int, progress func(id string, before, after int64)) (*RecompressReport, error) {
+	return cm.storage.RecompressAll(level, progress)
+}
+
+// VerifyCheckpoints audits every checkpoint, optionally quarantining bad files
+func (cm *CheckpointManager) VerifyCheckpoints(quarantine bool) (*VerifyReport, error) {
+	return cm.storage.Verify(quarantine)
+}
+
+// RepairMetadata rebuilds metadata from the checkpoint files
+func (cm *CheckpointManager) RepairMetadata(all bool) (*RepairReport, error) {
+	return cm.storage.RebuildMetadata(all)
+}
+
+// GetCheckpoint loads a single checkpoint with all of its recorded state.
+// checkpointID may be a full ID, a unique prefix or a note, see Storage.LoadCheckpointByID.
+func (cm *CheckpointManager) GetCheckpoint(checkpointID string) (*types.Checkpoint, error) {
+	return cm.storage.LoadCheckpointByID(checkpointID)
+}
+
+// ExportCheckpointJSON returns a checkpoint as pretty JSON for inspection
+func (cm *CheckpointManager) ExportCheckpointJSON(checkpointID string) ([]byte, error) {
+	return cm.storage.ExportJSON(checkpointID)
+}
+
+// AnnotateCheckpoint attaches a free-text note to a checkpoint
+func (cm *CheckpointManager) AnnotateCheckpoint(checkpointID, note string) error {
+	return cm.storage.SetNote(checkpointID, strings.TrimSpace(note))
+}
+
+// PreviewVersionChanges lists the apps in a checkpoint, or the latest one when
+// checkpointID is empty, whose installed version changed since it was taken
+func (cm *CheckpointManager) PreviewVersionChanges(checkpointID string) ([]process.VersionChange, error) {
+	if checkpointID == "" {
+		checkpointList, err := cm.GetAvailableCheckpoints()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get checkpoints: %w", err)
+		}
+		if len(checkpointList.Checkpoints) == 0 {
+			return nil, nil
+		}
+		checkpointID = checkpointList.Checkpoints[0].ID
+	}
+
+	checkpoint, err := cm.storage.LoadCheckpointByID(checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	return process.CompareVersions(checkpoint.Processes), nil
+}
 
 // RestoreLatestCheckpoint restores from the most recent checkpoint
 func (cm *CheckpointManager) RestoreLatestCheckpoint() ([]types.LaunchResult, error) {
@@ -174,13 +729,63 @@ func (cm *CheckpointManager) RestoreLatestCheckpoint() ([]types.LaunchResult, er
 	}
 
 	if len(checkpointList.Checkpoints) == 0 {
-		return nil, fmt.Errorf("No checkpoints available for restoration")
+		return nil, fmt.Errorf("%w: none available for restoration", ErrCheckpointNotFound)
 	}
 
 	latestCheckpoint := checkpointList.Checkpoints[0] // Already sorted by newest first
 	return cm.RestoreFromCheckpoint(latestCheckpoint.ID)
 }
 
+// ContinueRestore launches the apps an interrupted restore had not reached yet.
+// Returns process.ErrNoRestoreQueue when no restore was interrupted.
+func (cm *CheckpointManager) ContinueRestore() ([]types.LaunchResult, error) {
+	queue, err := process.LoadRestoreQueue()
+	if err != nil {
+		return nil, err
+	}
+	if queue.Running() {
+		return nil, fmt.Errorf("%w: process %d is restoring %s", process.ErrRestoreRunning, queue.PID, queue.Source)
+	}
+	system.Info("Continuing restore of", queue.Source, "with", len(queue.Remaining), "apps left")
+
+	// The source is a checkpoint ID unless a template was being restored
+	checkpoint, err := cm.storage.LoadCheckpointByID(queue.Source)
+	if err != nil {
+		system.Debug("Continuing without checkpoint state:", err)
+		checkpoint = nil
+	}
+
+	launcher := cm.newLauncher()
+	launcher.ResumeQueue(queue)
+	if checkpoint != nil {
+		remaining := make(map[string]bool, len(queue.Remaining))
+		for _, proc := range queue.Remaining {
+			remaining[proc.Name] = true
+		}
+		cm.restoreBeforeLaunch(checkpoint, remaining)
+		cm.applyDisplayLayout(checkpoint, launcher)
+	}
+	results, err := launcher.RestoreApplications(queue.Remaining)
+	if err != nil {
+		return results, fmt.Errorf("Failed to restore applications: %w", err)
+	}
+
+	if checkpoint != nil {
+		cm.restoreAfterLaunch(checkpoint, launcher)
+	}
+	return results, nil
+}
+
+// newLauncher creates an application launcher with the restore settings
+func (cm *CheckpointManager) newLauncher() *process.ApplicationLauncher {
+	launcher := process.NewApplicationLauncher()
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	launcher.SetProgressHandler(cm.restoreProgress)
+	launcher.SetIncludeManualOnly(cm.includeManualOnly)
+	return launcher
+}
+
 // DisplayCheckpointMenu shows available checkpoints with descriptive names and success icons
 func (cm *CheckpointManager) DisplayCheckpointMenu() error {
 	checkpointList, err := cm.GetAvailableCheckpoints()
@@ -189,23 +794,33 @@ func (cm *CheckpointManager) DisplayCheckpointMenu() error {
 	}
 
 	if len(checkpointList.Checkpoints) == 0 {
-		fmt.Println("No checkpoints available.")
+		fmt.Fprintln(system.Stdout, "No checkpoints available.")
 		return nil
 	}
 
-	fmt.Printf("\n=== AVAILABLE CHECKPOINTS ===\n")
-	fmt.Printf("Total: %d | Compressed: %d\n\n", checkpointList.TotalCount, checkpointList.CompressedCount)
+	fmt.Fprintf(system.Stdout, "\n=== AVAILABLE CHECKPOINTS ===\n")
+	fmt.Fprintf(system.Stdout, "Total: %d | Compressed: %d\n\n", checkpointList.TotalCount, checkpointList.CompressedCount)
 
 	for i, checkpoint := range checkpointList.Checkpoints {
 		status := "✅"
 		if checkpoint.IsCompressed {
 			status += " 📦" // Add compression indicator
 		}
-		fmt.Printf("%d. CP: [%s] %s\n", i+1, cm.formatCheckpointName(&checkpoint), status)  
+		if checkpoint.CaptureMode == "basic" {
+			status += " (basic)" // No window state
+		}
+		if checkpoint.Partial {
+			status += " (partial)"
+		}
+		fmt.Fprintf(system.Stdout, "%d. CP: [%s] %s\n", i+1, checkpoint.ID, status)
+		fmt.Fprintf(system.Stdout, "   %s\n", Digest(&checkpoint))
+		if checkpoint.Note != "" {
+			fmt.Fprintf(system.Stdout, "   📝 %s\n", checkpoint.Note)
+		}
 	}
 
 	if checkpointList.LastUsed != "" {
-		fmt.Printf("\nLast used: %s\n", checkpointList.LastUsed)
+		fmt.Fprintf(system.Stdout, "\nLast used: %s\n", checkpointList.LastUsed)
 	}
 	return nil 
 }
@@ -229,6 +844,24 @@ func (cm *CheckpointManager) PerformMaintenanceTasks() error {
 		system.Warn("Compression failed:", err)
 	}
 
+	// Remove orphaned metadata, duplicates and temp files left by failed writes
+	report, err := cm.storage.CollectGarbage()
+	if err != nil {
+		system.Warn("Garbage collection failed:", err)
+	} else if report.Total() > 0 {
+		system.Info("Cleaned orphans - metadata:", len(report.OrphanMetadata),
+			"quarantined:", len(report.Quarantined), "duplicates:", len(report.Duplicates),
+			"temp files:", len(report.TempFiles), "snapshot dirs:", len(report.OrphanDirs),
+			"rebuilt metadata:", len(report.RebuiltMetadata))
+	}
+
+	// Catch mirrors up on writes held back by sync limits or missed while unreachable
+	if mirror, ok := cm.storage.backend.(*MirrorBackend); ok {
+		if err := mirror.Sync(); err != nil {
+			system.Warn("Mirror sync failed:", err)
+		}
+	}
+
 	system.Debug("Maintenance tasks completed")
 	return nil
 }
@@ -244,36 +877,54 @@ func (cm *CheckpointManager) formatCheckpointName(checkpoint *types.Checkpoint)
 	return fmt.Sprintf("%s (%s)", checkpoint.ID, appList)
 }
 
-// getLastUsedCheckpoint determines which checkpoit was last used for restoration
+// getLastUsedCheckpoint determines which checkpoit was last used for restoration.
+// The record lives in the profile's own directory, so each profile tracks its own.
 func (cm *CheckpointManager) getLastUsedCheckpoint(checkpoints []types.Checkpoint) string {
-	// For now, we'll implement this as a simple file-based tracking
-	// in a more sophisticated version, this would be stored in metadata
-	return ""
+	lastUsed := cm.storage.LastUsed()
+	for _, cp := range checkpoints {
+		if cp.ID == lastUsed {
+			return lastUsed
+		}
+	}
+	return "" // Cleaned up since
 }
 
 //updateLastUsedCheckpoint updates the last used checkpoint record
 func (cm *CheckpointManager) updateLastUsedCheckpoint(checkpointID string) {
 	system.Debug("Updating last used checkpoint to:", checkpointID)
-	// Implementation would store this information persistently 
+	if err := cm.storage.SetLastUsed(checkpointID); err != nil {
+		system.Warn("Failed to record last used checkpoint:", err)
+	}
 }
 
-//checkDiskSpace monitors disk space and triggers cleanup if needed
-func (cm *CheckpointManager) checkDiskSpace() error {
-	// Implementation for disk space checking
-	// This would check if we're above 75% threshold
-	return nil
+// Profile returns the active profile name, "default" for the default stream
+func (cm *CheckpointManager) Profile() string {
+	return profileName(activeProfile())
+}
+
+// profileName names a profile for display
+func profileName(profile string) string {
+	if profile == "" {
+		return "default"
+	}
+	return profile
 }
 
 // cleanOldCheckpoints removes checkpoints older than retention period
 // This function `cleanOldCheckpoints` in the `CheckpointManager` struct is responsible for removing
 // checkpoints that are older than a specified retention period.
 func (cm *CheckpointManager) cleanOldCheckpoints() error {
-	retentionDays := config.GlobalConfig.DataRetentionDays
-	cutoffTime := time.Now().AddDate(0, 0, -retentionDays)
+	profile := activeProfile()
+	retentionDays := config.Current().RetentionDaysFor(profile)
+	now := time.Now()
 
-	system.Debug("Cleaning checkpoints older than", retentionDays, "days")
+	system.Debug("Cleaning checkpoints older than", retentionDays, "days", "(profile:", profileName(profile)+")")
 
-	return cm.storage.CleanOldCheckpoints(cutoffTime)
+	// Manual and pre-sleep checkpoints can be kept longer than routine ones
+	defer cm.recordRemovals()
+	return cm.storage.CleanOldCheckpoints(func(trigger string) time.Time {
+		return now.AddDate(0, 0, -config.Current().RetentionDaysForTrigger(profile, trigger))
+	})
 }
 
 // compressOldCheckpoints compresses checkpoints older than 24 hours from last used 
@@ -0,0 +1,167 @@
+package checkpoint
+
+import (
+    "fmt"
+    "sort"
+
+    "RESPAWN/internal/types"
+    "RESPAWN/pkg/config"
+)
+
+// Data categories a checkpoint can hold
+const (
+    CategoryApps       = "apps"
+    CategoryWindows    = "windows"
+    CategoryTitles     = "titles"
+    CategoryTabs       = "tabs"
+    CategorySessions   = "sessions"
+    CategoryDocs       = "docs"
+    CategoryProfiles   = "profiles"
+    CategoryPorts      = "ports"
+    CategoryVolumes    = "volumes"
+    CategoryAudio      = "audio"
+    CategoryPlayback   = "playback"
+    CategoryContainers = "containers"
+    CategoryVMs        = "vms"
+    CategoryDisplays   = "displays"
+)
+
+// Why a provider contributed nothing
+const (
+    reasonDisabled = "disabled in config"
+    reasonBasic    = "basic capture mode (no Accessibility permission)"
+    reasonPartial  = "partial checkpoint"
+)
+
+// buildManifest records which capture providers contributed to a checkpoint and,
+// for those that did not, why. It must run after capture with the same config.
+func buildManifest(cp *types.Checkpoint, basic bool) []types.CaptureProvider {
+    cfg := config.Current()
+    if cfg == nil {
+        cfg = config.DefaultConfig()
+    }
+
+    var windows, chat, profiles, ports int
+    for _, proc := range cp.Processes {
+        if proc.WindowState != "" {
+            windows++
+        }
+        if proc.Chat != nil {
+            chat++
+        }
+        if len(proc.Profiles) > 0 {
+            profiles++
+        }
+        if len(proc.ListeningPorts) > 0 {
+            ports++
+        }
+    }
+
+    var xcodeDocs, containers int
+    if cp.Developer != nil {
+        xcodeDocs = len(cp.Developer.XcodeDocuments)
+        containers = len(cp.Developer.Simulators) + len(cp.Developer.ComposeProjects) + len(cp.Developer.Containers)
+    }
+
+    // skip returns why a provider was not run, or "" if it was
+    skip := func(enabled bool, privacy string, needsAccessibility, detectorOnly bool) string {
+        switch {
+        case !enabled:
+            return reasonDisabled
+        case privacy != "" && !cfg.CaptureAllowed(privacy):
+            return fmt.Sprintf("window_privacy is %s", cfg.WindowPrivacy)
+        case needsAccessibility && basic:
+            return reasonBasic
+        case cp.Partial && !detectorOnly:
+            return reasonPartial
+        }
+        return ""
+    }
+
+    return []types.CaptureProvider{
+        provider("processes", "", len(cp.Processes), CategoryApps),
+        provider("accessibility", skip(true, config.PrivacyGeometry, true, true), windows, CategoryWindows),
+        provider("chat_context", skip(cfg.CaptureChatContext, config.PrivacyTitles, true, true), chat, CategoryTitles),
+        provider("browser_profiles", skip(cfg.CaptureBrowserProfiles, "", false, true), profiles, CategoryProfiles),
+        provider("listening_ports", skip(cfg.CaptureListeningPorts, "", false, true), ports, CategoryPorts),
+        provider("browser_sessions", skip(cfg.CaptureBrowserSessions, config.PrivacyFull, false, false), len(cp.Sessions), CategoryTabs, CategorySessions),
+        provider("window_state_files", skip(cfg.CaptureWindowStateFiles, config.PrivacyFull, false, false), len(cp.WindowStateFiles), CategoryWindows),
+        provider("xcode_documents", skip(cfg.CaptureDeveloperState, config.PrivacyFull, false, false), xcodeDocs, CategoryDocs),
+        provider("developer", skip(cfg.CaptureDeveloperState, "", false, false), containers, CategoryContainers),
+        provider("network_volumes", skip(cfg.CaptureNetworkVolumes, "", false, false), len(cp.Volumes), CategoryVolumes),
+        provider("audio", skip(cfg.CaptureAudioState, "", false, false), boolCount(cp.Audio != nil), CategoryAudio),
+        provider("playback", skip(cfg.RestorePlaybackState, "", false, false), len(cp.Playback), CategoryPlayback),
+        provider("virtual_machines", skip(cfg.CaptureVirtualMachines, "", false, false), len(cp.VirtualMachines), CategoryVMs),
+        provider("displays", skip(true, "", false, false), len(cp.Displays), CategoryDisplays),
+    }
+}
+
+// provider builds one manifest entry; an empty reason means it ran
+func provider(name, reason string, items int, categories ...string) types.CaptureProvider {
+    return types.CaptureProvider{
+        Name:       name,
+        Categories: categories,
+        Captured:   reason == "",
+        Items:      items,
+        Reason:     reason,
+    }
+}
+
+// boolCount is 1 for true, 0 for false
+func boolCount(b bool) int {
+    if b {
+        return 1
+    }
+    return 0
+}
+
+// CapturedCategories lists the data categories a checkpoint can reconstruct, sorted.
+// Checkpoints from before manifests were recorded return nil.
+func CapturedCategories(cp *types.Checkpoint) []string {
+    seen := make(map[string]bool)
+    for _, p := range cp.Providers {
+        if !p.Captured {
+            continue
+        }
+        for _, category := range p.Categories {
+            seen[category] = true
+        }
+    }
+    return sortedKeys(seen)
+}
+
+// MissingProviders returns the providers that did not contribute to a checkpoint
+// and whose categories no other provider covered
+func MissingProviders(cp *types.Checkpoint) []types.CaptureProvider {
+    covered := make(map[string]bool)
+    for _, category := range CapturedCategories(cp) {
+        covered[category] = true
+    }
+
+    var missing []types.CaptureProvider
+    for _, p := range cp.Providers {
+        if p.Captured {
+            continue
+        }
+        for _, category := range p.Categories {
+            if !covered[category] {
+                missing = append(missing, p)
+                break
+            }
+        }
+    }
+    return missing
+}
+
+// sortedKeys returns a set's members in order
+func sortedKeys(set map[string]bool) []string {
+    if len(set) == 0 {
+        return nil
+    }
+    keys := make([]string, 0, len(set))
+    for key := range set {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    return keys
+}
@@ -0,0 +1,84 @@
+package checkpoint
+
+import (
+    "encoding/json"
+    "fmt"
+
+    "RESPAWN/internal/system"
+)
+
+// CurrentFormatVersion is the checkpoint and metadata format written by this build.
+// Bump it and register a migration whenever a stored field changes shape.
+const CurrentFormatVersion = 2
+
+// legacyFormatVersion is assumed for files written before versioning existed
+const legacyFormatVersion = 1
+
+// migration upgrades a decoded document from one format version to the next
+type migration func(doc map[string]interface{}) error
+
+// checkpointMigrations maps a format version to the step that upgrades it to version+1
+var checkpointMigrations = map[int]migration{
+    1: func(doc map[string]interface{}) error {
+        // Version 1 predates basic capture, everything was a full capture
+        if _, ok := doc["capture_mode"]; !ok {
+            doc["capture_mode"] = "full"
+        }
+        return nil
+    },
+}
+
+// metadataMigrations maps a metadata format version to its upgrade step
+var metadataMigrations = map[int]migration{
+    1: func(doc map[string]interface{}) error {
+        if _, ok := doc["capture_mode"]; !ok {
+            doc["capture_mode"] = "full"
+        }
+        return nil
+    },
+}
+
+// migrateCheckpoint upgrades serialized checkpoint data to the current format
+func migrateCheckpoint(data []byte) ([]byte, error) {
+    return migrate(data, checkpointMigrations, "checkpoint")
+}
+
+// migrateMetadata upgrades serialized metadata to the current format
+func migrateMetadata(data []byte) ([]byte, error) {
+    return migrate(data, metadataMigrations, "metadata")
+}
+
+// migrate runs each registered step from the document's version up to CurrentFormatVersion.
+// Documents already at the current version are returned untouched.
+func migrate(data []byte, steps map[int]migration, kind string) ([]byte, error) {
+    var doc map[string]interface{}
+    if err := json.Unmarshal(data, &doc); err != nil {
+        return nil, fmt.Errorf("failed to decode %s: %w", kind, err)
+    }
+
+    version := legacyFormatVersion
+    if raw, ok := doc["format_version"].(float64); ok && raw > 0 {
+        version = int(raw)
+    }
+
+    if version == CurrentFormatVersion {
+        return data, nil
+    }
+    if version > CurrentFormatVersion {
+        return nil, fmt.Errorf("%s format v%d is newer than supported v%d - please update RESPAWN", kind, version, CurrentFormatVersion)
+    }
+
+    system.Debug("Migrating", kind, "from format v", version, "to v", CurrentFormatVersion)
+    for ; version < CurrentFormatVersion; version++ {
+        step, ok := steps[version]
+        if !ok {
+            return nil, fmt.Errorf("no migration from %s format v%d", kind, version)
+        }
+        if err := step(doc); err != nil {
+            return nil, fmt.Errorf("%s migration from v%d failed: %w", kind, version, err)
+        }
+    }
+
+    doc["format_version"] = CurrentFormatVersion
+    return json.Marshal(doc)
+}
@@ -0,0 +1,132 @@
+package checkpoint
+
+import (
+    "bytes"
+    "fmt"
+    "strings"
+
+    "RESPAWN/internal/system"
+)
+
+// RepairReport summarizes a metadata rebuild
+type RepairReport struct {
+    Rebuilt    []string
+    Unchanged  int
+    Unreadable []string
+    Conflicts  []string // Conflicted copies resolved before rebuilding
+}
+
+// RebuildMetadata regenerates metadata JSON from each .bin checkpoint, restoring the
+// fast listing path LoadAllCheckpoints relies on. Metadata that is missing, unreadable
+// or disagrees with its checkpoint file is rewritten; with all set, every entry is.
+func (s *Storage) RebuildMetadata(all bool) (*RepairReport, error) {
+    system.Info("Rebuilding checkpoint metadata")
+
+    unlock, err := s.lockExclusive()
+    if err != nil {
+        return nil, err
+    }
+    defer unlock()
+
+    conflicts, err := s.resolveConflicts()
+    if err != nil {
+        return nil, err
+    }
+
+    checkpointFiles, _, err := s.scanFiles()
+    if err != nil {
+        return nil, err
+    }
+
+    report := &RepairReport{Conflicts: conflicts}
+    for checkpointID, name := range checkpointFiles {
+        fresh, err := s.metadataFromFile(name)
+        if err != nil {
+            system.Warn("Cannot read checkpoint", checkpointID, ":", err)
+            report.Unreadable = append(report.Unreadable, checkpointID)
+            continue
+        }
+
+        if existing, err := s.loadMetadata(checkpointID); err == nil {
+            if !all && metadataMatches(existing, fresh) {
+                report.Unchanged++
+                continue
+            }
+            // Notes added after saving only live in metadata
+            if existing.Note != "" {
+                fresh.Note = existing.Note
+            }
+        }
+
+        if err := s.saveMetadata(fresh); err != nil {
+            return report, fmt.Errorf("failed to write metadata for %s: %w", checkpointID, err)
+        }
+        report.Rebuilt = append(report.Rebuilt, checkpointID)
+        system.Debug("Rebuilt metadata for", checkpointID)
+    }
+
+    system.Info("Metadata rebuild complete - rebuilt:", len(report.Rebuilt), "unchanged:", report.Unchanged, "unreadable:", len(report.Unreadable))
+    return report, nil
+}
+
+// metadataFromFile decodes a checkpoint file directly, without the metadata
+// checksum check LoadCheckpoint does, and derives its metadata
+func (s *Storage) metadataFromFile(name string) (*CheckpointMetadata, error) {
+    raw, err := s.backend.Get(name)
+    if err != nil {
+        return nil, err
+    }
+    if len(raw) == 0 {
+        return nil, fmt.Errorf("checkpoint file is empty")
+    }
+
+    isCompressed := strings.HasSuffix(name, "_compressed.bin")
+    data := raw
+    if isCompressed {
+        data, err = s.decompressor.DecodeAll(raw, nil)
+        if err != nil {
+            return nil, fmt.Errorf("failed to decompress: %w", err)
+        }
+    }
+
+    checkpoint, err := s.deserializeCheckpoint(bytes.NewReader(data))
+    if err != nil {
+        return nil, fmt.Errorf("failed to deserialize: %w", err)
+    }
+
+    metadata := &CheckpointMetadata{
+        FormatVersion: CurrentFormatVersion,
+        ID:            checkpointIDFromName(name), // A renamed conflicted copy still holds the ID it was saved under
+        Timestamp:     checkpoint.Timestamp,
+        IsCompressed:  isCompressed,
+        OriginalSize:  int64(len(data)),
+        Checksum:      s.calculateChecksum(raw),
+        AppCount:      len(checkpoint.Processes),
+        AppNames:      checkpoint.AppNames,
+        CaptureMode:   checkpoint.CaptureMode,
+        Partial:       checkpoint.Partial,
+        Trigger:       checkpoint.Trigger,
+        Note:          checkpoint.Note,
+        Providers:     checkpoint.Providers,
+        Hostname:      checkpoint.Hostname,
+        MachineID:     checkpoint.MachineID,
+    }
+    if isCompressed {
+        metadata.CompressedSize = int64(len(raw))
+    }
+    // Notes added after saving aren't in the file
+    if note, ok := s.loadNote(metadata.ID); ok {
+        metadata.Note = note
+    }
+
+    return metadata, nil
+}
+
+// metadataMatches reports whether existing metadata still describes the checkpoint file
+func metadataMatches(existing, fresh *CheckpointMetadata) bool {
+    return existing.ID == fresh.ID &&
+        existing.Checksum == fresh.Checksum &&
+        existing.IsCompressed == fresh.IsCompressed &&
+        existing.AppCount == fresh.AppCount &&
+        existing.Timestamp.Equal(fresh.Timestamp)
+}
@@ -1,13 +1,19 @@
 package checkpoint
 
 import (
+    "bytes"
     "crypto/sha256"
     "encoding/json"
+    "errors"
     "fmt"
     "io"
+    "path"
     "os"
     "path/filepath"
+    "sort"
     "strings"
+    "sync"
+    "sync/atomic"
     "time"
 
     "github.com/klauspost/compress/zstd"
@@ -18,12 +24,28 @@ import (
 
 type Storage struct {
 	baseDir    string
+	backend    StorageBackend
 	compressor     *zstd.Encoder
 	decompressor    *zstd.Decoder
 	compressionLevel    int 
+	writeDebugJSON      bool
+	freedBytes          atomic.Int64 // Size of checkpoints deleted since the last TakeFreedBytes
 }
 
+// debugJSONFile is the side-car copy of the latest checkpoint, written for debugging
+const debugJSONFile = "latest.json"
+
+// lastUsedFile records the ID of the checkpoint most recently restored
+const lastUsedFile = "last-used"
+
+// metadataReadWorkers bounds how many metadata files are read concurrently when listing
+const metadataReadWorkers = 16
+
+// tempSuffix marks files being written; they are renamed into place when complete
+const tempSuffix = ".tmp"
+
 type CheckpointMetadata struct {
+    FormatVersion int      `json:"format_version"`
 	ID           string    `json:"id"`
     Timestamp    time.Time `json:"timestamp"`
     IsCompressed bool      `json:"is_compressed"`
@@ -32,11 +54,23 @@ type CheckpointMetadata struct {
     Checksum     string    `json:"checksum"`
     AppCount     int       `json:"app_count"`
     AppNames     []string  `json:"app_names"`
-
+    CaptureMode  string    `json:"capture_mode,omitempty"`
+    Partial      bool      `json:"partial,omitempty"`
+    Trigger      string    `json:"trigger,omitempty"`
+    Note         string    `json:"note,omitempty"`
+    Providers    []types.CaptureProvider `json:"providers,omitempty"`
+    Hostname     string    `json:"hostname,omitempty"`
+    MachineID    string    `json:"machine_id,omitempty"`
 }
 
-// NewStorage creates a new storage manager
+// NewStorage creates a new storage manager backed by the local disk
 func NewStorage(baseDir string) (*Storage, error) {
+    return NewStorageWithBackend(baseDir, NewLocalBackend(baseDir))
+}
+
+// NewStorageWithBackend creates a storage manager on top of any backend.
+// baseDir is still used for local-only data such as session snapshots.
+func NewStorageWithBackend(baseDir string, backend StorageBackend) (*Storage, error) {
 	// Create zstd compressor with default level (user can change later)
 	compressor, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
 	if err != nil {
@@ -50,17 +84,17 @@ func NewStorage(baseDir string) (*Storage, error) {
 
 	storage := &Storage{
         baseDir:          baseDir,
+        backend:          backend,
         compressor:       compressor,
         decompressor:     decompressor,
         compressionLevel: int(zstd.SpeedDefault),
 	}
 
-    // Create metadata directory
-    metadataDir := filepath.Join(baseDir, "metadata")
-    if err := os.MkdirAll(metadataDir, 0755); err != nil {
-        return nil, fmt.Errorf("Failed to create metadata directory: %w", err)
+    if mirror, ok := backend.(*MirrorBackend); ok {
+        mirror.SetLocker(storage.lockShared)
     }
 
+    system.Debug("Checkpoint storage backend:", describeBackend(backend))
     return storage, nil 
 }
 
@@ -81,41 +115,48 @@ if err != nil {
     s.compressor = compressor 
     s.compressionLevel = level
 
-    system.Info("Compression level set to, level")
+    system.Info("Compression level set to", level)
     return nil 
 }
 
+// SetDebugJSON enables writing a pretty JSON side-car of every new checkpoint
+func (s *Storage) SetDebugJSON(enabled bool) {
+    s.writeDebugJSON = enabled
+}
+
 // This below is the function that saves a checkpoint to binary format.
 func (s *Storage) SaveCheckpoint(checkpoint *types.Checkpoint) (string, int64, error) {
     system.Debug("Saving checkpoint", checkpoint.ID)
 
+    unlock, err := s.lockExclusive()
+    if err != nil {
+        return "", 0, err
+    }
+    defer unlock()
+
     // This is how the binary file is created 
-    fileName := fmt.Sprint("%s.bin", checkpoint.ID)
-    filePath := filepath.Join(s.baseDir, fileName)
+    fileName := fmt.Sprintf("%s.bin", checkpoint.ID)
+    filePath := s.localPath(fileName)
 
     // Converts checkpoint to binary data
+    checkpoint.FormatVersion = CurrentFormatVersion
     data, err := s.serializeCheckpoint(checkpoint)
     if err != nil {
         return "", 0, fmt.Errorf("Failed to serialize checkpoint: %w", err)
     }
 
-    // Write binary data to file
-    file, err := os.Create(filePath)
-    if err != nil {
-        return "", 0, fmt.Errorf("Failed to create checkpoint file: %w", err)
-    }
-    defer file.Close()
-
-    bytesWritten, err := file.Write(data)
-    if err != nil {
+    // Write binary data to the backend
+    if err := s.backend.Put(fileName, data); err != nil {
         return "", 0, fmt.Errorf("Failed to write checkpoint data: %w", err)
     }
+    bytesWritten := len(data)
 
     // Calculate checksum for integrity
     checksum := s.calculateChecksum(data)
 
     // Saves metadata
     metadata := &CheckpointMetadata{
+        FormatVersion: CurrentFormatVersion,
         ID:           checkpoint.ID,
         Timestamp:    checkpoint.Timestamp,
         IsCompressed: false,
@@ -123,101 +164,209 @@ func (s *Storage) SaveCheckpoint(checkpoint *types.Checkpoint) (string, int64, e
         Checksum:     checksum,
         AppCount:     len(checkpoint.Processes),
         AppNames:     checkpoint.AppNames,
+        CaptureMode:  checkpoint.CaptureMode,
+        Partial:      checkpoint.Partial,
+        Trigger:      checkpoint.Trigger,
+        Note:         checkpoint.Note,
+        Providers:    checkpoint.Providers,
+        Hostname:     checkpoint.Hostname,
+        MachineID:    checkpoint.MachineID,
     }
 
     if err := s.saveMetadata(metadata); err != nil {
         system.Warn("Failed to save metadata for", checkpoint.ID, ":", err)
     }
 
+    if s.writeDebugJSON {
+        if err := s.writeDebugSideCar(checkpoint); err != nil {
+            system.Warn("Failed to write debug JSON for", checkpoint.ID, ":", err)
+        }
+    }
+
     system.Debug("Saved checkpoint", checkpoint.ID, "Size:", bytesWritten, "bytes")
     return filePath, int64(bytesWritten), nil 
 }
 
-// LoadCheckpoint loads a checkpoint from storage with streaming
-func (s *Storage) LoadCheckpoint(checkpointID string) (*types.Checkpoint, error) {
-    system.Debug("Loading checkpoint", checkpointID)
+// LoadCheckpointByID loads a checkpoint by reference. It is the one lookup used by
+// restore, show and export. A reference is, in order of precedence:
+//   - a full checkpoint ID
+//   - a unique ID prefix, e.g. "2024-06-01_09"
+//   - a checkpoint note, compared case-insensitively
+// The compressed file wins when both forms of a checkpoint exist. Returns
+// ErrCheckpointNotFound when nothing matches, and lists the candidates when the
+// reference is ambiguous.
+//
+// Prefixes match the ID text, not a date: checkpoints from older versions have
+// the hour in the day's place (see checkpointIDLayout), so "2024-06-09" can match
+// the 9 o'clock checkpoints of every day in June taken before the upgrade, next to
+// the ones of June 9 taken since. The ambiguity error lists them all.
+func (s *Storage) LoadCheckpointByID(ref string) (*types.Checkpoint, error) {
+    unlock, err := s.lockShared()
+    if err != nil {
+        return nil, err
+    }
+    defer unlock()
 
-// Try compressed version first, then uncompressed
-    filePath := s.getCheckpointPath(checkpointID)
-    isCompressed := strings.HasSuffix(filePath, "_compressed.bin")
+    checkpointID, err := s.resolveCheckpointID(ref)
+    if err != nil {
+        return nil, err
+    }
+    return s.loadCheckpoint(checkpointID)
+}
 
-    // This makes sure the file is validated before loading
-    if err := s.validateCheckpointFile(checkpointID); err != nil {
-        return nil, fmt.Errorf("checkpoint validation failed: %w", err) 
+// resolveCheckpointID maps a reference to a stored checkpoint ID; the caller holds the storage lock
+func (s *Storage) resolveCheckpointID(ref string) (string, error) {
+    ref = strings.TrimSpace(ref)
+    if ref == "" {
+        return "", fmt.Errorf("%w: no checkpoint ID given", ErrCheckpointNotFound)
     }
 
-    // Stream data from file
-    file, err := os.Open(filePath)
+    files, err := s.backend.List("")
     if err != nil {
-        return nil, fmt.Errorf("Failed to open checkpoint file: %w", err)
+        return "", fmt.Errorf("Failed to read checkpoint directory: %w", err)
     }
-    defer file.Close()
 
-    var reader io.Reader = file 
-
-    // Decompress if needed
-    if isCompressed {
-        decompressedData, err := s.decompressor.DecodeAll(nil, nil)
-        if err != nil {
-            return nil, fmt.Errorf("Failed to setup decpmpression: %w", err)
+    ids := make(map[string]bool)
+    for _, fileName := range files {
+        if strings.HasSuffix(fileName, ".bin") && !isConflictedCopy(fileName) {
+            ids[checkpointIDFromName(fileName)] = true
         }
+    }
+    if ids[ref] {
+        return ref, nil
+    }
 
-        // Read compressed data
-        compressedData, err := io.ReadAll(file)
-        if err != nil {
-            return nil, fmt.Errorf("Failed to read compressed data: %w", err)
+    var matches []string
+    for id := range ids {
+        if strings.HasPrefix(id, ref) {
+            matches = append(matches, id)
         }
+    }
 
-        // Decompress data
-        decompressedData, err = s.decompressor.DecodeAll(decompressedData, compressedData)
-        if err != nil {
-            return nil, fmt.Errorf("Failed to decompress checkpoint: %w", err)
+    // Notes are only consulted when the reference isn't an ID or prefix
+    if len(matches) == 0 {
+        for id := range ids {
+            if metadata, err := s.loadMetadata(id); err == nil && metadata.Note != "" && strings.EqualFold(metadata.Note, ref) {
+                matches = append(matches, id)
+            }
         }
+    }
+
+    switch len(matches) {
+    case 0:
+        return "", fmt.Errorf("%w: %s", ErrCheckpointNotFound, ref)
+    case 1:
+        return matches[0], nil
+    }
+    sort.Strings(matches)
+    return "", fmt.Errorf("%q matches %d checkpoints: %s", ref, len(matches), strings.Join(matches, ", "))
+}
+
+// loadCheckpoint does the work of LoadCheckpoint; the caller holds the storage lock
+func (s *Storage) loadCheckpoint(checkpointID string) (*types.Checkpoint, error) {
+    system.Debug("Loading checkpoint", checkpointID)
+
+// Try compressed version first, then uncompressed
+    name := s.checkpointName(checkpointID)
+    isCompressed := strings.HasSuffix(name, "_compressed.bin")
 
-        reader = strings.NewReader(string(decompressedData))
+    if _, err := s.backend.Stat(name); errors.Is(err, ErrNotExist) {
+        return nil, fmt.Errorf("%w: %s", ErrCheckpointNotFound, checkpointID)
     }
 
-    // Deserialize checkpoint data
-    checkpoint, err := s.deserializeCheckpoint(reader)
+    var checkpoint *types.Checkpoint
+    var err error
+    if isRemote(s.backend) {
+        // A remote object could change between two reads, so it is downloaded once
+        // and the verified bytes are the ones decoded
+        checkpoint, err = s.loadRemoteCheckpoint(checkpointID, name, isCompressed)
+        if errors.Is(err, ErrUnverifiedCheckpoint) || errors.Is(err, ErrCorruptCheckpoint) {
+            return nil, fmt.Errorf("checkpoint validation failed: %w", err)
+        }
+    } else {
+        // The checksum is computed while decoding, from the same single read, so
+        // large checkpoints are never held in memory as bytes
+        checkpoint, err = s.streamCheckpoint(checkpointID, name, isCompressed)
+        if errors.Is(err, errNeedsMigration) {
+            checkpoint, err = s.loadLegacyCheckpoint(checkpointID, name, isCompressed)
+        }
+        if errors.Is(err, ErrCorruptCheckpoint) {
+            return nil, fmt.Errorf("checkpoint validation failed: %w", err)
+        }
+    }
     if err != nil {
-        return nil, fmt.Errorf("Failed to deserialize checkpoint: %w", err)
+        return nil, fmt.Errorf("Failed to deserialize checkpoint: %w: %w", ErrCorruptCheckpoint, err)
     }
 
-    checkpoint.FilePath = filePath
+    checkpoint.ID = checkpointID // Differs from the stored ID for a renamed conflicted copy
+    checkpoint.FilePath = s.localPath(name)
     checkpoint.IsCompressed = isCompressed
 
+    // Notes can be added after the checkpoint was written, so metadata is authoritative
+    if metadata, err := s.loadMetadata(checkpointID); err == nil && metadata.Note != "" {
+        checkpoint.Note = metadata.Note
+    }
+
     system.Debug("Loaded checkpoint", checkpointID, "Apps:", len(checkpoint.Processes))
     return checkpoint, nil 
 }
 
+// ExportJSON returns a checkpoint as indented, human-readable JSON
+func (s *Storage) ExportJSON(checkpointID string) ([]byte, error) {
+    checkpoint, err := s.LoadCheckpointByID(checkpointID)
+    if err != nil {
+        return nil, err
+    }
+
+    // FilePath and IsCompressed describe where it was loaded from, not the checkpoint itself
+    checkpoint.FilePath = ""
+    checkpoint.IsCompressed = false
+
+    return json.MarshalIndent(checkpoint, "", "  ")
+}
+
+// writeDebugSideCar writes the checkpoint as pretty JSON next to the binary files
+func (s *Storage) writeDebugSideCar(checkpoint *types.Checkpoint) error {
+    data, err := json.MarshalIndent(checkpoint, "", "  ")
+    if err != nil {
+        return err
+    }
+    return s.backend.Put(debugJSONFile, data)
+}
+
 // LoadAllCheckpoints loads all available checkpoints with metadata
 func (s *Storage) LoadAllCheckpoints() ([]types.Checkpoint, error) {
     system.Debug("Loading all available checkpoints")
 
-    files, err := os.ReadDir(s.baseDir)
+    unlock, err := s.lockShared()
     if err != nil {
-        return nil, fmt.Errorf("Failed to read checkpoint directory: %w", err)
+        return nil, err
     }
+    defer unlock()
 
-    var checkpoints []types.Checkpoint
+    files, err := s.backend.List("")
+    if err != nil {
+        return nil, fmt.Errorf("Failed to read checkpoint directory: %w", err)
+    }
 
-    for _, file := range files {
-        if file.IsDir() || (!strings.HasSuffix(file.Name(), ".bin")) {
+    var checkpointIDs []string
+    for _, fileName := range files {
+        if !strings.HasSuffix(fileName, ".bin") || isConflictedCopy(fileName) {
             continue 
         }
+        checkpointIDs = append(checkpointIDs, checkpointIDFromName(fileName))
+    }
 
-        //Extract checkpoint ID from filename
-        fileName := file.Name()
-        checkpointID := strings.TrimSuffix(fileName, ".bin")
-        checkpointID = strings.TrimSuffix(checkpointID, "_compressed")
+    // Load metadata first (faster than full checkpoint)
+    metadatas, _ := s.loadMetadataParallel(checkpointIDs)
 
-        
-        // Load metadata first (faster than full checkpoint)
-        metadata, err := s.loadMetadata(checkpointID)
-        if err != nil {
+    var checkpoints []types.Checkpoint
+    for i, checkpointID := range checkpointIDs {
+        metadata := metadatas[i]
+        if metadata == nil {
             system.Warn("Failed to load metadata for", checkpointID, "- loading full checkpoint")
             // Fallback to loading full checkpoint
-            checkpoint, err := s.LoadCheckpoint(checkpointID)
+            checkpoint, err := s.loadCheckpoint(checkpointID)
             if err != nil {
                 system.Warn("Failed to load checkpoint", checkpointID, ":", err)
                 continue 
@@ -227,24 +376,100 @@ func (s *Storage) LoadAllCheckpoints() ([]types.Checkpoint, error) {
         }
 
         // Create checkpoint summary from metadata
-        checkpoint := types.Checkpoint{
-            ID:           metadata.ID,
-            Timestamp:    metadata.Timestamp,
-            AppNames:     metadata.AppNames,
-            IsCompressed: metadata.IsCompressed,
-            FilePath:     s.getCheckpointPath(checkpointID),
-            FileSize:     metadata.OriginalSize,
+        checkpoints = append(checkpoints, s.summaryFromMetadata(metadata))
+    }
+
+    system.Debug("Loaded", len(checkpoints), "checkpoint summaries")
+    return checkpoints, nil 
+}
+
+// LoadMetadataSummaries builds checkpoint summaries from metadata alone, never
+// reading .bin files. Checkpoints without metadata are left out.
+func (s *Storage) LoadMetadataSummaries() ([]types.Checkpoint, error) {
+    unlock, err := s.lockShared()
+    if err != nil {
+        return nil, err
+    }
+    defer unlock()
+
+    names, err := s.backend.List(metadataDir)
+    if err != nil {
+        return nil, fmt.Errorf("Failed to read metadata directory: %w", err)
+    }
+
+    var checkpointIDs []string
+    for _, name := range names {
+        if strings.HasSuffix(name, ".json") && !isConflictedCopy(name) {
+            checkpointIDs = append(checkpointIDs, strings.TrimSuffix(path.Base(name), ".json"))
         }
+    }
 
-        if metadata.IsCompressed {
-            checkpoint.FileSize = metadata.CompressedSize
+    metadatas, errs := s.loadMetadataParallel(checkpointIDs)
+    var checkpoints []types.Checkpoint
+    for i, metadata := range metadatas {
+        if metadata == nil {
+            system.Debug("Skipping unreadable metadata", checkpointIDs[i], ":", errs[i])
+            continue
         }
+        checkpoints = append(checkpoints, s.summaryFromMetadata(metadata))
+    }
+    return checkpoints, nil
+}
 
-        checkpoints = append(checkpoints, checkpoint)
+// loadMetadataParallel reads the metadata of many checkpoints at once, at most
+// metadataReadWorkers at a time. Results are in checkpointIDs order; a metadata
+// that failed to load is nil with its error at the same index.
+func (s *Storage) loadMetadataParallel(checkpointIDs []string) ([]*CheckpointMetadata, []error) {
+    metadatas := make([]*CheckpointMetadata, len(checkpointIDs))
+    errs := make([]error, len(checkpointIDs))
+
+    workers := metadataReadWorkers
+    if len(checkpointIDs) < workers {
+        workers = len(checkpointIDs)
+    }
+
+    next := make(chan int)
+    var wg sync.WaitGroup
+    for w := 0; w < workers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := range next {
+                metadatas[i], errs[i] = s.loadMetadata(checkpointIDs[i])
+            }
+        }()
     }
+    for i := range checkpointIDs {
+        next <- i
+    }
+    close(next)
+    wg.Wait()
 
-    system.Debug("Loaded", len(checkpoints), "checkpoint summaries")
-    return checkpoints, nil 
+    return metadatas, errs
+}
+
+// summaryFromMetadata creates a checkpoint summary (no processes) from its metadata
+func (s *Storage) summaryFromMetadata(metadata *CheckpointMetadata) types.Checkpoint {
+    checkpoint := types.Checkpoint{
+        ID:           metadata.ID,
+        Timestamp:    metadata.Timestamp,
+        AppNames:     metadata.AppNames,
+        IsCompressed: metadata.IsCompressed,
+        FilePath:     s.localPath(fmt.Sprintf("%s.bin", metadata.ID)),
+        FileSize:     metadata.OriginalSize,
+        CaptureMode:  metadata.CaptureMode,
+        Partial:      metadata.Partial,
+        Trigger:      metadata.Trigger,
+        Note:         metadata.Note,
+        Hostname:     metadata.Hostname,
+        MachineID:    metadata.MachineID,
+    }
+
+    if metadata.IsCompressed {
+        checkpoint.FilePath = s.localPath(fmt.Sprintf("%s_compressed.bin", metadata.ID))
+        checkpoint.FileSize = metadata.CompressedSize
+    }
+    return checkpoint
 }
 
 // CompressCheckpoint compress an existing checkpoint
@@ -255,11 +480,17 @@ func (s *Storage) CompressCheckpoint(checkpoint *types.Checkpoint) error {
 
     system.Debug("Compressing checkpoint", checkpoint.ID)
 
-    originalPath := s.getCheckpointPath(checkpoint.ID)
-    compressedPath := filepath.Join(s.baseDir, fmt.Sprintf("%s_compressed.bin", checkpoint.ID))
+    unlock, err := s.lockExclusive()
+    if err != nil {
+        return err
+    }
+    defer unlock()
+
+    originalName := s.checkpointName(checkpoint.ID)
+    compressedName := fmt.Sprintf("%s_compressed.bin", checkpoint.ID)
 
     // Read original file
-    originalData, err := os.ReadFile(originalPath)
+    originalData, err := s.backend.Get(originalName)
     if err != nil {
         return fmt.Errorf("Failed to read original checkpoint: %w", err)
     }
@@ -269,7 +500,7 @@ func (s *Storage) CompressCheckpoint(checkpoint *types.Checkpoint) error {
 
 
     // This function writes compressed file
-    if err := os.WriteFile(compressedPath, compressedData, 0644); err != nil {
+    if err := s.backend.Put(compressedName, compressedData); err != nil {
         return fmt.Errorf("Failed to write compressed checkpoint: %w", err)
     }
 
@@ -283,8 +514,8 @@ func (s *Storage) CompressCheckpoint(checkpoint *types.Checkpoint) error {
     }
 
     //Remove original file
-    if err := os.Remove(originalPath); err != nil {
-        system.Warn("Failed to remove original file", originalPath, ":", err)
+    if err := s.backend.Delete(originalName); err != nil {
+        system.Warn("Failed to remove original file", originalName, ":", err)
     }
 
     compressionRatio := float64(len(compressedData)) / float64(len(originalData)) * 100
@@ -292,7 +523,7 @@ func (s *Storage) CompressCheckpoint(checkpoint *types.Checkpoint) error {
                 fmt.Sprintf("(%.1f%%)", compressionRatio))
 
     checkpoint.IsCompressed = true
-    checkpoint.FilePath = compressedPath
+    checkpoint.FilePath = s.localPath(compressedName)
     checkpoint.FileSize = int64(len(compressedData))
 
     return nil
@@ -300,76 +531,107 @@ func (s *Storage) CompressCheckpoint(checkpoint *types.Checkpoint) error {
 
 //This function validates checkpoint integrity using checksums
 func (s *Storage) validateCheckpointFile(checkpointID string) error {
-    filePath := s.getCheckpointPath(checkpointID)
+    _, err := s.loadValidatedData(checkpointID)
+    return err
+}
 
-    // Check if file exista and it's readable
-    fileInfo, err := os.Stat(filePath)
+// loadValidatedData reads a checkpoint's stored bytes and checks them against its metadata checksum
+func (s *Storage) loadValidatedData(checkpointID string) ([]byte, error) {
+    //Read file and calculate checksum
+    data, err := s.backend.Get(s.checkpointName(checkpointID))
     if err != nil {
-        return fmt.Errorf("checkpoint file not accessible: %w", err)
+        return nil, fmt.Errorf("checkpoint file not accessible: %w", err)
     }
 
     //Basic size check
-    if fileInfo.Size() == 0 {
-        return fmt.Errorf("checkpoint file is empty")
+    if len(data) == 0 {
+        return nil, fmt.Errorf("%w: file is empty", ErrCorruptCheckpoint)
     }
 
     // This loads metadata for checksum validation
     metadata, err := s.loadMetadata(checkpointID)
     if err != nil {
         system.Debug("No metadata found for", checkpointID, "-skipping checksum validation")
-        return nil 
-    }
-
-    //Read file and calculate checksum
-    data, err := os.ReadFile(filePath)
-    if err != nil {
-        return fmt.Errorf("Failed to read checkpoint file: %w", err)
+        return data, nil 
     }
 
     actualChecksum := s.calculateChecksum(data)
     if actualChecksum != metadata.Checksum {
-        return fmt.Errorf("Checksum mismatch - file may be corrupted (expected: %s, got: %s)", metadata.Checksum, actualChecksum)
+        return nil, fmt.Errorf("%w: checksum mismatch (expected: %s, got: %s)", ErrCorruptCheckpoint, metadata.Checksum, actualChecksum)
     } 
 
     system.Debug("Checkpoint", checkpointID, "validation passed")
-    return nil 
+    return data, nil 
 }
 
-// CleanOldCheckpoints removes checkpoints older than the cuttoff time 
-func (s *Storage) CleanOldCheckpoints(cutoffTime time.Time) error {
-    system.Debug("Cleaning checkpoints older than", cutoffTime.Format("2006-01-02 15:04:05"))
+// SetNote attaches a free-text note to a checkpoint's metadata, replacing any previous note
+func (s *Storage) SetNote(checkpointID, note string) error {
+    unlock, err := s.lockExclusive()
+    if err != nil {
+        return err
+    }
+    defer unlock()
+
+    checkpointID, err = s.resolveCheckpointID(checkpointID)
+    if err != nil {
+        return err
+    }
 
-    files, err := os.ReadDir(s.baseDir)
+    metadata, err := s.loadMetadata(checkpointID)
+    if err != nil {
+        return fmt.Errorf("No metadata for checkpoint %s (run respawn repair): %w", checkpointID, err)
+    }
+
+    // The sidecar outlives the metadata, which repair and conflict resolution rebuild
+    // from the checkpoint file
+    if err := s.saveNote(checkpointID, note); err != nil {
+        return fmt.Errorf("Failed to save note for %s: %w", checkpointID, err)
+    }
+    metadata.Note = note
+    return s.saveMetadata(metadata)
+}
+
+// CleanOldCheckpoints removes checkpoints older than the cuttoff time for their trigger
+func (s *Storage) CleanOldCheckpoints(cutoffFor func(trigger string) time.Time) error {
+    system.Debug("Cleaning checkpoints older than", cutoffFor("").Format("2006-01-02 15:04:05"))
+
+    unlock, err := s.lockExclusive()
+    if err != nil {
+        return err
+    }
+    defer unlock()
+
+    files, err := s.backend.List("")
     if err != nil {
         return fmt.Errorf("Failed to read checkpoint directory: %w", err)
     }
 
     deletedCount := 0
 
-    for _, file := range files {
-        if file.IsDir() || !strings.HasSuffix(file.Name(), ".bin") {
+    for _, fileName := range files {
+        if !strings.HasSuffix(fileName, ".bin") {
             continue
         }
 
-        filePath := filepath.Join(s.baseDir, file.Name())
-        fileInfo, err := file.Info()
+        fileInfo, err := s.backend.Stat(fileName)
         if err != nil {
             continue
         }
 
-        if fileInfo.ModTime().Before(cutoffTime) {
-            if err := os.Remove(filePath); err != nil {
-                system.Warn("Failed to delete old checkpoint", file.Name(), ";", err)
+        // Checkpoints without metadata, or from before triggers were recorded, get the default retention
+        var trigger string
+        if metadata, err := s.loadMetadata(checkpointIDFromName(fileName)); err == nil {
+            trigger = metadata.Trigger
+        }
+
+        if fileInfo.ModTime.Before(cutoffFor(trigger)) {
+            if err := s.deleteCheckpointFiles(fileName); err != nil {
+                system.Warn("Failed to delete old checkpoint", fileName, ";", err)
                 continue
             }
 
-            //Also remove metadata 
-            checkpointID := strings.TrimSuffix(file.Name(), ".bin")
-            checkpointID = strings.TrimSuffix(checkpointID, "_compressed")
-            s.deleteMetadata(checkpointID)
-
             deletedCount++
-            system.Debug("Deleted old checkpoint:", file.Name())
+            system.Debug("Deleted old checkpoint:", fileName)
         }
     }
 
@@ -380,6 +642,49 @@ func (s *Storage) CleanOldCheckpoints(cutoffTime time.Time) error {
     return nil 
 }
 
+// DeleteCheckpoints removes the given checkpoints and everything stored with them
+func (s *Storage) DeleteCheckpoints(checkpointIDs []string) (int, error) {
+    unlock, err := s.lockExclusive()
+    if err != nil {
+        return 0, err
+    }
+    defer unlock()
+
+    deletedCount := 0
+    for _, checkpointID := range checkpointIDs {
+        if err := s.deleteCheckpointFiles(s.checkpointName(checkpointID)); err != nil {
+            system.Warn("Failed to delete checkpoint", checkpointID, ":", err)
+            continue
+        }
+        deletedCount++
+    }
+    return deletedCount, nil
+}
+
+// deleteCheckpointFiles removes a checkpoint file along with its metadata and snapshot directories
+func (s *Storage) deleteCheckpointFiles(fileName string) error {
+    info, statErr := s.backend.Stat(fileName)
+    if err := s.backend.Delete(fileName); err != nil {
+        return err
+    }
+    if statErr == nil {
+        s.freedBytes.Add(info.Size)
+    }
+
+    //Also remove metadata 
+    checkpointID := checkpointIDFromName(fileName)
+    s.deleteMetadata(checkpointID)
+    s.backend.Delete(noteName(checkpointID)) // Ignore ERRORS
+    os.RemoveAll(filepath.Join(s.baseDir, "sessions", checkpointID))
+    os.RemoveAll(filepath.Join(s.baseDir, "state-files", checkpointID))
+    return nil
+}
+
+// TakeFreedBytes returns how much space deleted checkpoints freed since it was last called
+func (s *Storage) TakeFreedBytes() int64 {
+    return s.freedBytes.Swap(0)
+}
+
 // Helper functions
 
 // serializeCheckpoints converts checkpoint to binary format
@@ -393,6 +698,25 @@ func (s *Storage) serializeCheckpoint(checkpoint *types.Checkpoint) ([]byte, err
     return data, nil 
 }
 
+// loadLegacyCheckpoint reads an older-format checkpoint fully into memory so it can be
+// migrated, verifying the checksum of the bytes it decodes
+func (s *Storage) loadLegacyCheckpoint(checkpointID, name string, isCompressed bool) (*types.Checkpoint, error) {
+    data, err := s.backend.Get(name)
+    if err != nil {
+        return nil, err
+    }
+    if err := s.verifyChecksum(checkpointID, s.calculateChecksum(data), int64(len(data))); err != nil {
+        return nil, err
+    }
+    if isCompressed {
+        data, err = s.decompressor.DecodeAll(data, nil)
+        if err != nil {
+            return nil, fmt.Errorf("Failed to decompress checkpoint: %w", err)
+        }
+    }
+    return s.deserializeCheckpoint(bytes.NewReader(data))
+}
+
 // deserializeCheckpoint converts binary data back to checkpoint
 func (s *Storage) deserializeCheckpoint(reader io.Reader) (*types.Checkpoint, error) {
     data, err := io.ReadAll(reader)
@@ -400,6 +724,12 @@ func (s *Storage) deserializeCheckpoint(reader io.Reader) (*types.Checkpoint, er
         return nil, err 
     }
 
+    // Bring older formats up to date before decoding into the current struct
+    data, err = migrateCheckpoint(data)
+    if err != nil {
+        return nil, err
+    }
+
     var checkpoint types.Checkpoint
     if err := json.Unmarshal(data, &checkpoint); err != nil {
         return nil, err
@@ -410,14 +740,29 @@ func (s *Storage) deserializeCheckpoint(reader io.Reader) (*types.Checkpoint, er
 
 // getCheckpointPath returns the file path for a checkpoint
 func (s *Storage) getCheckpointPath(checkpointID string) string {
+    return s.localPath(s.checkpointName(checkpointID))
+}
+
+// checkpointName returns the backend object name for a checkpoint
+func (s *Storage) checkpointName(checkpointID string) string {
     // Check for compressed version first
-    compressedPath := filepath.Join(s.baseDir, fmt.Sprintf("%s_compressed.bin", checkpointID))
-    if _, err := os.Stat(compressedPath); err == nil {
-        return compressedPath
+    compressedName := fmt.Sprintf("%s_compressed.bin", checkpointID)
+    if _, err := s.backend.Stat(compressedName); err == nil {
+        return compressedName
     }
 
-    //Return uncompressed path
-    return filepath.Join(s.baseDir, fmt.Sprintf("%s.bin", checkpointID))
+    //Return uncompressed name
+    return fmt.Sprintf("%s.bin", checkpointID)
+}
+
+// checkpointIDFromName is the inverse of checkpointName, for "ID.bin" and "ID_compressed.bin"
+func checkpointIDFromName(name string) string {
+    return strings.TrimSuffix(strings.TrimSuffix(name, ".bin"), "_compressed")
+}
+
+// localPath maps an object name to where the local backend keeps it, for display
+func (s *Storage) localPath(name string) string {
+    return filepath.Join(s.baseDir, filepath.FromSlash(name))
 }
 
 //This functions calculates SHA256 checksum for integrity validation ; [calculateChecksum]
@@ -426,24 +771,35 @@ func (s *Storage) calculateChecksum(data []byte) string {
     return fmt.Sprintf("%x", hash)
 }
 
+// metadataDir is the backend directory holding metadata JSON files
+const metadataDir = "metadata"
+
+// metadataName returns the backend object name of a checkpoint's metadata
+func metadataName(checkpointID string) string {
+    return objectName(metadataDir, fmt.Sprintf("%s.json", checkpointID))
+}
+
 //This method saves checkpoint metadata
 func (s *Storage) saveMetadata(metadata *CheckpointMetadata) error {
-    metadataPath := filepath.Join(s.baseDir, "metadata", fmt.Sprintf("%s.json", metadata.ID))
     data, err := json.MarshalIndent(metadata, "", " ")
     if err != nil {
         return err
     }
-    return os.WriteFile(metadataPath, data, 0644)
+    return s.backend.Put(metadataName(metadata.ID), data)
 }
 
 //This method loads checkpoint metadata
 func (s *Storage) loadMetadata(checkpointID string) (*CheckpointMetadata, error) {
-    metadataPath := filepath.Join(s.baseDir, "metadata", fmt.Sprintf("%s.json", checkpointID))
-    data, err := os.ReadFile(metadataPath)
+    data, err := s.backend.Get(metadataName(checkpointID))
     if err != nil {
         return nil, err 
     }
 
+    data, err = migrateMetadata(data)
+    if err != nil {
+        return nil, err
+    }
+
     var metadata CheckpointMetadata
     if err := json.Unmarshal(data, &metadata); err != nil {
         return nil, err 
@@ -454,8 +810,50 @@ func (s *Storage) loadMetadata(checkpointID string) (*CheckpointMetadata, error)
 
 // deleteMetadata removes metadata for a checkpoint
 func (s *Storage) deleteMetadata(checkpointID string) {
-    metadataPath := filepath.Join(s.baseDir, "metadata", fmt.Sprintf("%s.json", checkpointID))
-    os.Remove(metadataPath) // Ignore ERRORS
+    s.backend.Delete(metadataName(checkpointID)) // Ignore ERRORS
+}
+
+// notesDir is the backend directory holding the notes added to checkpoints after
+// they were saved, one sidecar file each
+const notesDir = "notes"
+
+// noteName returns the backend object name of a checkpoint's note
+func noteName(checkpointID string) string {
+    return objectName(notesDir, checkpointID+".txt")
+}
+
+// saveNote writes a checkpoint's note sidecar, removing it for an empty note
+func (s *Storage) saveNote(checkpointID, note string) error {
+    if note == "" {
+        if err := s.backend.Delete(noteName(checkpointID)); err != nil && !errors.Is(err, ErrNotExist) {
+            return err
+        }
+        return nil
+    }
+    return s.backend.Put(noteName(checkpointID), []byte(note))
+}
+
+// loadNote reads a checkpoint's note sidecar, false when it has none
+func (s *Storage) loadNote(checkpointID string) (string, bool) {
+    data, err := s.backend.Get(noteName(checkpointID))
+    if err != nil {
+        return "", false
+    }
+    return string(data), true
+}
+
+// LastUsed returns the ID of the checkpoint most recently restored, empty if none
+func (s *Storage) LastUsed() string {
+    data, err := s.backend.Get(lastUsedFile)
+    if err != nil {
+        return ""
+    }
+    return strings.TrimSpace(string(data))
+}
+
+// SetLastUsed records the checkpoint that was just restored
+func (s *Storage) SetLastUsed(checkpointID string) error {
+    return s.backend.Put(lastUsedFile, []byte(checkpointID+"\n"))
 }
 
 // This method cleans up storage resources
@@ -0,0 +1,112 @@
+package checkpoint
+
+import (
+    "flag"
+    "fmt"
+    "testing"
+    "time"
+
+    "RESPAWN/internal/types"
+)
+
+// listingBudget is how long listing benchmarkCheckpoints checkpoints may take
+const listingBudget = 100 * time.Millisecond
+
+// checkBudget turns on TestListingBudget, a wall-clock check that only means
+// something on an idle machine: go test -run ListingBudget -listing-budget
+var checkBudget = flag.Bool("listing-budget", false, "fail if listing checkpoints takes longer than its budget")
+
+// benchmarkCheckpoints is the checkpoint count listing is benchmarked against
+const benchmarkCheckpoints = 1000
+
+// newBenchmarkStorage fills a temporary directory with checkpoints that have metadata
+func newBenchmarkStorage(b testing.TB, count int) *Storage {
+    b.Helper()
+
+    dir := b.TempDir()
+    storage := &Storage{baseDir: dir, backend: NewLocalBackend(dir)}
+    start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    for i := 0; i < count; i++ {
+        timestamp := start.Add(time.Duration(i) * time.Minute)
+        id := timestamp.Format("2006-01-02_15-04-05")
+        if err := storage.backend.Put(id+".bin", []byte("checkpoint")); err != nil {
+            b.Fatal(err)
+        }
+        metadata := &CheckpointMetadata{
+            FormatVersion: CurrentFormatVersion,
+            ID:            id,
+            Timestamp:     timestamp,
+            OriginalSize:  4096,
+            Checksum:      fmt.Sprintf("%064d", i),
+            AppCount:      3,
+            AppNames:      []string{"Safari", "Terminal", "Xcode"},
+            CaptureMode:   "full",
+        }
+        if err := storage.saveMetadata(metadata); err != nil {
+            b.Fatal(err)
+        }
+    }
+    return storage
+}
+
+// reportListingTime reports the average listing time against listingBudget
+func reportListingTime(b *testing.B) {
+    perOp := b.Elapsed() / time.Duration(b.N)
+    b.ReportMetric(float64(perOp)/float64(time.Millisecond), "ms/list")
+    b.ReportMetric(float64(perOp)/float64(listingBudget), "budget/list")
+}
+
+// TestListingBudget fails if listing benchmarkCheckpoints checkpoints takes longer
+// than listingBudget. Off unless -listing-budget is given.
+func TestListingBudget(t *testing.T) {
+    if !*checkBudget {
+        t.Skip("wall-clock budget check, run with -listing-budget")
+    }
+    storage := newBenchmarkStorage(t, benchmarkCheckpoints)
+
+    listings := map[string]func() ([]types.Checkpoint, error){
+        "LoadAllCheckpoints":    storage.LoadAllCheckpoints,
+        "LoadMetadataSummaries": storage.LoadMetadataSummaries,
+    }
+    for name, list := range listings {
+        start := time.Now()
+        if _, err := list(); err != nil {
+            t.Fatalf("%s failed: %v", name, err)
+        }
+        if elapsed := time.Since(start); elapsed > listingBudget {
+            t.Errorf("%s listed %d checkpoints in %v, want under %v", name, benchmarkCheckpoints, elapsed, listingBudget)
+        }
+    }
+}
+
+func BenchmarkLoadAllCheckpoints(b *testing.B) {
+    storage := newBenchmarkStorage(b, benchmarkCheckpoints)
+    b.ResetTimer()
+
+    for i := 0; i < b.N; i++ {
+        checkpoints, err := storage.LoadAllCheckpoints()
+        if err != nil {
+            b.Fatal(err)
+        }
+        if len(checkpoints) != benchmarkCheckpoints {
+            b.Fatalf("loaded %d checkpoints, want %d", len(checkpoints), benchmarkCheckpoints)
+        }
+    }
+    reportListingTime(b)
+}
+
+func BenchmarkLoadMetadataSummaries(b *testing.B) {
+    storage := newBenchmarkStorage(b, benchmarkCheckpoints)
+    b.ResetTimer()
+
+    for i := 0; i < b.N; i++ {
+        checkpoints, err := storage.LoadMetadataSummaries()
+        if err != nil {
+            b.Fatal(err)
+        }
+        if len(checkpoints) != benchmarkCheckpoints {
+            b.Fatalf("loaded %d checkpoints, want %d", len(checkpoints), benchmarkCheckpoints)
+        }
+    }
+    reportListingTime(b)
+}
@@ -0,0 +1,243 @@
+package checkpoint
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+
+    "github.com/klauspost/compress/zstd"
+
+    "RESPAWN/internal/system"
+    "RESPAWN/internal/types"
+)
+
+// errNeedsMigration means a streamed checkpoint is in an older format and must be
+// loaded the slow way, through migrateCheckpoint
+var errNeedsMigration = errors.New("checkpoint format needs migration")
+
+// streamedLists are the potentially large arrays decoded element by element
+// instead of being buffered whole by the JSON decoder
+var streamedLists = map[string]bool{
+    "processes":          true,
+    "sessions":           true,
+    "window_state_files": true,
+}
+
+// verifyChecksum checks the hash of a stored checkpoint's bytes against its
+// metadata. Checkpoints without metadata pass unchecked.
+func (s *Storage) verifyChecksum(checkpointID string, actualChecksum string, size int64) error {
+    if size == 0 {
+        return fmt.Errorf("%w: file is empty", ErrCorruptCheckpoint)
+    }
+
+    metadata, err := s.loadMetadata(checkpointID)
+    if err != nil {
+        system.Debug("No metadata found for", checkpointID, "-skipping checksum validation")
+        return nil
+    }
+    if actualChecksum != metadata.Checksum {
+        return fmt.Errorf("%w: checksum mismatch (expected: %s, got: %s)", ErrCorruptCheckpoint, metadata.Checksum, actualChecksum)
+    }
+    return nil
+}
+
+// loadRemoteCheckpoint downloads a checkpoint from a remote backend and decodes it
+// only once it matches its metadata checksum. Unlike local checkpoints, one without
+// metadata or checksum is refused with ErrUnverifiedCheckpoint.
+func (s *Storage) loadRemoteCheckpoint(checkpointID, name string, isCompressed bool) (*types.Checkpoint, error) {
+    metadata, err := s.loadMetadata(checkpointID)
+    if err != nil {
+        return nil, fmt.Errorf("%w: %s has no metadata on %s", ErrUnverifiedCheckpoint, checkpointID, describeBackend(s.backend))
+    }
+    if len(metadata.Checksum) != sha256.Size*2 {
+        return nil, fmt.Errorf("%w: %s has no checksum on %s", ErrUnverifiedCheckpoint, checkpointID, describeBackend(s.backend))
+    }
+
+    data, err := s.backend.Get(name)
+    if err != nil {
+        return nil, fmt.Errorf("checkpoint download failed: %w", err)
+    }
+    if len(data) == 0 {
+        return nil, fmt.Errorf("%w: file is empty", ErrCorruptCheckpoint)
+    }
+    if actualChecksum := s.calculateChecksum(data); actualChecksum != metadata.Checksum {
+        return nil, fmt.Errorf("%w: checksum mismatch (expected: %s, got: %s)", ErrCorruptCheckpoint, metadata.Checksum, actualChecksum)
+    }
+    system.Debug("Checkpoint", checkpointID, "verified from", describeBackend(s.backend))
+
+    if isCompressed {
+        data, err = s.decompressor.DecodeAll(data, nil)
+        if err != nil {
+            return nil, fmt.Errorf("Failed to decompress checkpoint: %w", err)
+        }
+    }
+    checkpoint, err := decodeCheckpointStream(bytes.NewReader(data))
+    if errors.Is(err, errNeedsMigration) {
+        checkpoint, err = s.deserializeCheckpoint(bytes.NewReader(data))
+    }
+    return checkpoint, err
+}
+
+// streamCheckpoint decodes a stored checkpoint straight from the backend, decompressing
+// on the fly, so neither the compressed nor the decompressed bytes are held in memory.
+// The file is opened once and hashed as it is decoded, so the bytes checked against
+// the metadata checksum are the bytes decoded; a mismatch discards the result.
+func (s *Storage) streamCheckpoint(checkpointID, name string, isCompressed bool) (*types.Checkpoint, error) {
+    file, err := openObject(s.backend, name)
+    if err != nil {
+        return nil, fmt.Errorf("checkpoint file not accessible: %w", err)
+    }
+    defer file.Close()
+
+    hash := sha256.New()
+    counted := &countingReader{r: io.TeeReader(file, hash)}
+
+    var checkpoint *types.Checkpoint
+    if isCompressed {
+        decoder, err := zstd.NewReader(counted, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+        if err != nil {
+            return nil, fmt.Errorf("Failed to decompress checkpoint: %w", err)
+        }
+        checkpoint, err = decodeCheckpointStream(decoder)
+        decoder.Close()
+    } else {
+        checkpoint, err = decodeCheckpointStream(counted)
+    }
+
+    // Hash whatever the decoder didn't read, so the checksum covers the whole file
+    if _, copyErr := io.Copy(io.Discard, counted); copyErr != nil {
+        return nil, fmt.Errorf("checkpoint file not readable: %w", copyErr)
+    }
+    if verifyErr := s.verifyChecksum(checkpointID, fmt.Sprintf("%x", hash.Sum(nil)), counted.n); verifyErr != nil {
+        return nil, verifyErr
+    }
+    return checkpoint, err
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+    r io.Reader
+    n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+    n, err := c.r.Read(p)
+    c.n += int64(n)
+    return n, err
+}
+
+// decodeCheckpointStream walks the top-level checkpoint object token by token. The large
+// lists are decoded one element at a time, everything else is collected and decoded at
+// the end. Returns errNeedsMigration for documents not in the current format.
+func decodeCheckpointStream(r io.Reader) (*types.Checkpoint, error) {
+    decoder := json.NewDecoder(r)
+    if err := expectDelim(decoder, '{'); err != nil {
+        return nil, err
+    }
+
+    checkpoint := &types.Checkpoint{}
+    rest := make(map[string]json.RawMessage)
+    version := legacyFormatVersion
+
+    for decoder.More() {
+        token, err := decoder.Token()
+        if err != nil {
+            return nil, err
+        }
+        key, ok := token.(string)
+        if !ok {
+            return nil, fmt.Errorf("unexpected token %v in checkpoint", token)
+        }
+
+        switch {
+        case key == "format_version":
+            if err := decoder.Decode(&version); err != nil {
+                return nil, fmt.Errorf("invalid format_version: %w", err)
+            }
+        case streamedLists[key]:
+            if err := decodeList(decoder, key, checkpoint); err != nil {
+                return nil, fmt.Errorf("failed to decode %s: %w", key, err)
+            }
+        default:
+            var raw json.RawMessage
+            if err := decoder.Decode(&raw); err != nil {
+                return nil, err
+            }
+            rest[key] = raw
+        }
+    }
+
+    if err := expectDelim(decoder, '}'); err != nil {
+        return nil, err
+    }
+    if version != CurrentFormatVersion {
+        return nil, errNeedsMigration
+    }
+
+    // The remaining fields are small; round-trip them through the regular decoder
+    // so the struct tags stay the single source of truth
+    header, err := json.Marshal(rest)
+    if err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal(header, checkpoint); err != nil {
+        return nil, err
+    }
+    checkpoint.FormatVersion = version
+
+    return checkpoint, nil
+}
+
+// decodeList decodes one of the streamedLists into checkpoint element by element
+func decodeList(decoder *json.Decoder, key string, checkpoint *types.Checkpoint) error {
+    token, err := decoder.Token()
+    if err != nil {
+        return err
+    }
+    if token == nil {
+        return nil
+    }
+    if delim, ok := token.(json.Delim); !ok || delim != '[' {
+        return fmt.Errorf("expected array, got %v", token)
+    }
+
+    for decoder.More() {
+        switch key {
+        case "processes":
+            var process types.ProcessInfo
+            if err := decoder.Decode(&process); err != nil {
+                return err
+            }
+            checkpoint.Processes = append(checkpoint.Processes, process)
+        case "sessions":
+            var session types.SessionSnapshot
+            if err := decoder.Decode(&session); err != nil {
+                return err
+            }
+            checkpoint.Sessions = append(checkpoint.Sessions, session)
+        case "window_state_files":
+            var file types.StateFileSnapshot
+            if err := decoder.Decode(&file); err != nil {
+                return err
+            }
+            checkpoint.WindowStateFiles = append(checkpoint.WindowStateFiles, file)
+        }
+    }
+
+    return expectDelim(decoder, ']')
+}
+
+// expectDelim reads the next token and checks it is the given delimiter
+func expectDelim(decoder *json.Decoder, want json.Delim) error {
+    token, err := decoder.Token()
+    if err != nil {
+        return err
+    }
+    if delim, ok := token.(json.Delim); !ok || delim != want {
+        return fmt.Errorf("malformed checkpoint: expected %q, got %v", want, token)
+    }
+    return nil
+}
@@ -0,0 +1,246 @@
+package checkpoint
+
+import (
+    "encoding/json"
+    "errors"
+    "path"
+    "strings"
+    "time"
+
+    "RESPAWN/internal/system"
+    "RESPAWN/pkg/config"
+)
+
+// SyncLimits keep mirroring from competing with the user's session, for mirrors that
+// a sync client uploads (iCloud Drive, Dropbox) or that sit on a network volume
+type SyncLimits struct {
+    MaxBytesPerSecond int64 // 0 is unlimited
+    OnlyOnPower       bool
+    OnlyOnWiFi        bool  // Wi-Fi or wired, see system.OnUnmeteredNetwork
+}
+
+// syncLimitsFromConfig reads the mirror_* settings, so a config reload applies to the next write
+func syncLimitsFromConfig() SyncLimits {
+    cfg := config.Current()
+    if cfg == nil {
+        return SyncLimits{}
+    }
+    return SyncLimits{
+        MaxBytesPerSecond: int64(cfg.MirrorMaxKBps) << 10,
+        OnlyOnPower:       cfg.MirrorOnlyOnPower,
+        OnlyOnWiFi:        cfg.MirrorOnlyOnWiFi,
+    }
+}
+
+// limited reports whether any limit is set, when mirror writes move to the background
+func (l SyncLimits) limited() bool {
+    return l.MaxBytesPerSecond > 0 || l.OnlyOnPower || l.OnlyOnWiFi
+}
+
+// heldBack names the rule keeping mirroring back right now, or "" when it may run
+func (l SyncLimits) heldBack() string {
+    if l.OnlyOnPower && !system.PowerConnected() {
+        return "on battery"
+    }
+    if l.OnlyOnWiFi && !system.OnUnmeteredNetwork() {
+        return "not on Wi-Fi"
+    }
+    return ""
+}
+
+// pace sleeps after writing n bytes in elapsed, long enough to stay under the cap
+func (l SyncLimits) pace(n int, elapsed time.Duration) {
+    if l.MaxBytesPerSecond <= 0 {
+        return
+    }
+    if wait := time.Duration(int64(n)*int64(time.Second)/l.MaxBytesPerSecond) - elapsed; wait > 0 {
+        time.Sleep(wait)
+    }
+}
+
+// SetSyncLimits makes the mirror check limits before writing. With any limit set,
+// Put only writes the primary and mirroring catches up in the background with Sync.
+func (b *MirrorBackend) SetSyncLimits(limits func() SyncLimits) {
+    b.limits = limits
+}
+
+// currentLimits returns the limits in force, none when SetSyncLimits wasn't called
+func (b *MirrorBackend) currentLimits() SyncLimits {
+    if b.limits == nil {
+        return SyncLimits{}
+    }
+    return b.limits()
+}
+
+// SetLocker makes Sync hold the storage's shared lock around each object, so it never
+// copies back an object that compression or a delete is replacing or removing
+func (b *MirrorBackend) SetLocker(lock func() (func(), error)) {
+    b.lock = lock
+}
+
+// lockObject takes the lock set with SetLocker, if any
+func (b *MirrorBackend) lockObject() (func(), error) {
+    if b.lock == nil {
+        return func() {}, nil
+    }
+    return b.lock()
+}
+
+// Sync copies objects the mirrors are missing, or hold an older copy of, from the
+// primary, and removes mirrored objects the primary no longer has. It stops as soon
+// as a limit holds mirroring back and paces writes to the bandwidth cap; the next
+// checkpoint or maintenance run picks up where it left off. It also repairs mirrors
+// that missed writes or deletes while unreachable.
+func (b *MirrorBackend) Sync() error {
+    if !b.syncing.CompareAndSwap(false, true) {
+        return nil // Already running
+    }
+    defer b.syncing.Store(false)
+
+    names, err := listSyncNames(b.primary)
+    if err != nil {
+        return err
+    }
+
+    // The power and network checks run commands, so they are only made before a
+    // copy, never for objects the mirrors already hold
+    limits := b.currentLimits()
+    copied := 0
+    for _, name := range names {
+        n, heldBack, err := b.syncObject(name, limits)
+        if err != nil {
+            return err
+        }
+        copied += n
+        if heldBack {
+            b.logMirrored(copied, 0)
+            return nil
+        }
+    }
+
+    removed, err := b.removeStale()
+    b.logMirrored(copied, removed)
+    return err
+}
+
+// syncObject copies one object to the mirrors that need it, under the storage lock.
+// Returns how many mirrors were written and whether a limit stopped the sync.
+func (b *MirrorBackend) syncObject(name string, limits SyncLimits) (int, bool, error) {
+    unlock, err := b.lockObject()
+    if err != nil {
+        return 0, false, err
+    }
+    defer unlock()
+
+    info, err := b.primary.Stat(name)
+    if err != nil {
+        return 0, false, nil // Deleted since it was listed
+    }
+    copied := 0
+    for _, mirror := range b.mirrors {
+        if mirrored, err := mirror.Stat(name); err == nil && mirrored.Size == info.Size && !mirrored.ModTime.Before(info.ModTime) {
+            continue
+        }
+        if reason := limits.heldBack(); reason != "" {
+            system.Debug("Mirroring held back -", reason)
+            return copied, true, nil
+        }
+
+        data, err := b.primary.Get(name)
+        if err != nil {
+            break
+        }
+        start := time.Now()
+        if err := mirror.Put(name, data); err != nil {
+            system.Warn("Failed to mirror", name, ":", err)
+            continue
+        }
+        copied++
+        limits.pace(len(data), time.Since(start))
+    }
+    return copied, false, nil
+}
+
+// removeStale deletes mirrored objects that are gone from the primary, such as
+// checkpoints deleted or compressed while a mirror was unreachable. Checkpoints
+// another Mac wrote to a shared mirror are left alone.
+func (b *MirrorBackend) removeStale() (int, error) {
+    removed := 0
+    for _, mirror := range b.mirrors {
+        names, err := listSyncNames(mirror)
+        if err != nil {
+            system.Warn("Failed to list mirror", describeBackend(mirror), ":", err)
+            continue
+        }
+        for _, name := range names {
+            gone, err := b.goneFromPrimary(name)
+            if err != nil {
+                return removed, err
+            }
+            if !gone || fromOtherMachine(mirror, name) {
+                continue
+            }
+            if err := mirror.Delete(name); err != nil && !errors.Is(err, ErrNotExist) {
+                system.Warn("Failed to delete mirrored", name, ":", err)
+                continue
+            }
+            removed++
+        }
+    }
+    return removed, nil
+}
+
+// goneFromPrimary checks under the storage lock that the primary has no such object,
+// so an object being written right now isn't taken for a stale one
+func (b *MirrorBackend) goneFromPrimary(name string) (bool, error) {
+    unlock, err := b.lockObject()
+    if err != nil {
+        return false, err
+    }
+    defer unlock()
+
+    _, err = b.primary.Stat(name)
+    return errors.Is(err, ErrNotExist), nil
+}
+
+// fromOtherMachine reports whether the mirror's metadata says another Mac made the
+// checkpoint an object belongs to
+func fromOtherMachine(mirror StorageBackend, name string) bool {
+    id := checkpointIDFromName(strings.TrimSuffix(strings.TrimSuffix(path.Base(name), ".json"), ".txt"))
+    data, err := mirror.Get(metadataName(id))
+    if err != nil {
+        return false
+    }
+    var metadata CheckpointMetadata
+    if err := json.Unmarshal(data, &metadata); err != nil {
+        return false
+    }
+    return metadata.MachineID != "" && !system.IsThisMachine(metadata.MachineID)
+}
+
+// logMirrored reports how many objects a Sync copied and removed
+func (b *MirrorBackend) logMirrored(copied, removed int) {
+    if copied > 0 {
+        system.Info("Mirrored", copied, "objects to", describeBackend(b))
+    }
+    if removed > 0 {
+        system.Info("Removed", removed, "stale objects from", describeBackend(b))
+    }
+}
+
+// listSyncNames lists the checkpoint, metadata and note objects of a backend
+func listSyncNames(backend StorageBackend) ([]string, error) {
+    var names []string
+    for _, dir := range []string{"", metadataDir, notesDir} {
+        listed, err := backend.List(dir)
+        if err != nil {
+            return nil, err
+        }
+        for _, name := range listed {
+            if !strings.HasSuffix(name, tempSuffix) {
+                names = append(names, name)
+            }
+        }
+    }
+    return names, nil
+}
@@ -0,0 +1,182 @@
+package checkpoint
+
+import (
+    "fmt"
+    "path"
+    "strings"
+
+    "RESPAWN/internal/system"
+)
+
+// Problem kinds reported by Verify
+const (
+    ProblemCorrupt      = "corrupt"      // Checksum mismatch or undecodable
+    ProblemInconsistent = "inconsistent" // Metadata disagrees with the checkpoint file
+    ProblemOrphan       = "orphan"       // Metadata without a checkpoint file, or the reverse
+)
+
+// quarantineDir is where Verify moves bad files instead of deleting them
+const quarantineDir = "quarantine"
+
+// VerifyProblem describes one file that failed verification
+type VerifyProblem struct {
+    CheckpointID string
+    Path         string // Backend object name
+    Kind         string
+    Detail       string
+}
+
+// VerifyReport summarizes an integrity audit
+type VerifyReport struct {
+    Checked     int
+    Problems    []VerifyProblem
+    Quarantined []string
+}
+
+// Verify validates every checkpoint's checksum and metadata and reports corrupt
+// or orphaned files. With quarantine set, problem files are moved aside.
+func (s *Storage) Verify(quarantine bool) (*VerifyReport, error) {
+    system.Info("Verifying checkpoint storage")
+
+    checkpointFiles, metadataFiles, err := s.scanFiles()
+    if err != nil {
+        return nil, err
+    }
+
+    report := &VerifyReport{}
+
+    for checkpointID, path := range checkpointFiles {
+        report.Checked++
+
+        if _, ok := metadataFiles[checkpointID]; !ok {
+            report.Problems = append(report.Problems, VerifyProblem{
+                CheckpointID: checkpointID, Path: path, Kind: ProblemOrphan,
+                Detail: "no metadata (run respawn repair to rebuild it)",
+            })
+        }
+
+        if problem := s.verifyCheckpoint(checkpointID, path); problem != nil {
+            report.Problems = append(report.Problems, *problem)
+        }
+    }
+
+    for checkpointID, path := range metadataFiles {
+        if _, ok := checkpointFiles[checkpointID]; !ok {
+            report.Problems = append(report.Problems, VerifyProblem{
+                CheckpointID: checkpointID, Path: path, Kind: ProblemOrphan,
+                Detail: "metadata without a checkpoint file",
+            })
+        }
+    }
+
+    if quarantine && len(report.Problems) > 0 {
+        unlock, err := s.lockExclusive()
+        if err != nil {
+            return report, err
+        }
+        defer unlock()
+
+        for _, problem := range report.Problems {
+            // Missing metadata alone is repairable, keep the checkpoint
+            if problem.Kind == ProblemOrphan && !strings.HasSuffix(problem.Path, ".json") {
+                continue
+            }
+            if err := s.quarantineFile(problem.Path); err != nil {
+                system.Warn("Failed to quarantine", problem.Path, ":", err)
+                continue
+            }
+            report.Quarantined = append(report.Quarantined, problem.Path)
+        }
+    }
+
+    system.Info("Verified", report.Checked, "checkpoints -", len(report.Problems), "problems")
+    return report, nil
+}
+
+// verifyCheckpoint checks one checkpoint's checksum, decoding and metadata consistency
+func (s *Storage) verifyCheckpoint(checkpointID, path string) *VerifyProblem {
+    problem := func(kind, detail string) *VerifyProblem {
+        return &VerifyProblem{CheckpointID: checkpointID, Path: path, Kind: kind, Detail: detail}
+    }
+
+    if err := s.validateCheckpointFile(checkpointID); err != nil {
+        return problem(ProblemCorrupt, err.Error())
+    }
+
+    checkpoint, err := s.LoadCheckpointByID(checkpointID)
+    if err != nil {
+        return problem(ProblemCorrupt, err.Error())
+    }
+
+    metadata, err := s.loadMetadata(checkpointID)
+    if err != nil {
+        return nil // Reported as orphan by the caller
+    }
+
+    info, err := s.backend.Stat(path)
+    if err != nil {
+        return problem(ProblemCorrupt, err.Error())
+    }
+
+    expectedSize := metadata.OriginalSize
+    if metadata.IsCompressed {
+        expectedSize = metadata.CompressedSize
+    }
+
+    switch {
+    case metadata.ID != checkpointID:
+        return problem(ProblemInconsistent, fmt.Sprintf("metadata ID is %s", metadata.ID))
+    case metadata.IsCompressed != strings.HasSuffix(path, "_compressed.bin"):
+        return problem(ProblemInconsistent, "metadata compression flag does not match file")
+    case expectedSize != info.Size:
+        return problem(ProblemInconsistent, fmt.Sprintf("metadata size %d, file size %d", expectedSize, info.Size))
+    case metadata.AppCount != len(checkpoint.Processes):
+        return problem(ProblemInconsistent, fmt.Sprintf("metadata lists %d apps, checkpoint has %d", metadata.AppCount, len(checkpoint.Processes)))
+    }
+
+    return nil
+}
+
+// scanFiles maps checkpoint IDs to their .bin and metadata object names
+func (s *Storage) scanFiles() (map[string]string, map[string]string, error) {
+    files, err := s.backend.List("")
+    if err != nil {
+        return nil, nil, fmt.Errorf("Failed to read checkpoint directory: %w", err)
+    }
+
+    checkpointFiles := make(map[string]string)
+    for _, fileName := range files {
+        if !strings.HasSuffix(fileName, ".bin") || isConflictedCopy(fileName) {
+            continue
+        }
+        checkpointID := checkpointIDFromName(fileName)
+        checkpointFiles[checkpointID] = fileName
+    }
+
+    metadataFiles := make(map[string]string)
+    entries, err := s.backend.List(metadataDir)
+    if err != nil {
+        return nil, nil, fmt.Errorf("Failed to read metadata directory: %w", err)
+    }
+    for _, name := range entries {
+        if !strings.HasSuffix(name, ".json") || isConflictedCopy(name) {
+            continue
+        }
+        checkpointID := strings.TrimSuffix(path.Base(name), ".json")
+        metadataFiles[checkpointID] = name
+    }
+
+    return checkpointFiles, metadataFiles, nil
+}
+
+// quarantineFile moves an object into the quarantine directory
+func (s *Storage) quarantineFile(name string) error {
+    data, err := s.backend.Get(name)
+    if err != nil {
+        return err
+    }
+    if err := s.backend.Put(objectName(quarantineDir, path.Base(name)), data); err != nil {
+        return err
+    }
+    return s.backend.Delete(name)
+}
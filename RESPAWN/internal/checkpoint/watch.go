@@ -0,0 +1,90 @@
+package checkpoint
+
+import (
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/fsnotify/fsnotify"
+
+    "RESPAWN/internal/system"
+)
+
+// watchSettleDelay lets a burst of events (a sync tool copying .bin and metadata,
+// or our own tmp+rename writes) settle before the listing is reloaded
+const watchSettleDelay = 2 * time.Second
+
+// WatchCheckpoints watches the checkpoint directory for checkpoints added or
+// removed by sync tools or other machines. After each burst of changes the
+// listing is reloaded and passed to onChange.
+// The returned function stops the watcher.
+func (cm *CheckpointManager) WatchCheckpoints(onChange func(*CheckpointList)) (func(), error) {
+    watcher, err := fsnotify.NewWatcher()
+    if err != nil {
+        return nil, fmt.Errorf("failed to create directory watcher: %w", err)
+    }
+
+    if err := watcher.Add(cm.checkpointDir); err != nil {
+        watcher.Close()
+        return nil, fmt.Errorf("failed to watch %s: %w", cm.checkpointDir, err)
+    }
+
+    done := make(chan struct{})
+    go cm.watchLoop(watcher, onChange, done)
+
+    system.Info("Watching", cm.checkpointDir, "for external changes")
+    return func() {
+        close(done)
+        watcher.Close()
+    }, nil
+}
+
+// watchLoop debounces relevant events and reloads the listing once they settle
+func (cm *CheckpointManager) watchLoop(watcher *fsnotify.Watcher, onChange func(*CheckpointList), done chan struct{}) {
+    defer system.RecordPanics()
+    settle := time.NewTimer(watchSettleDelay)
+    settle.Stop()
+
+    for {
+        select {
+        case <-done:
+            settle.Stop()
+            return
+
+        case event, ok := <-watcher.Events:
+            if !ok {
+                return
+            }
+            if !isCheckpointEvent(event) {
+                continue
+            }
+            system.Debug("Checkpoint directory changed:", event.Op, event.Name)
+            settle.Reset(watchSettleDelay)
+
+        case err, ok := <-watcher.Errors:
+            if !ok {
+                return
+            }
+            system.Warn("Checkpoint directory watcher error:", err)
+
+        case <-settle.C:
+            list, err := cm.GetAvailableCheckpoints()
+            if err != nil {
+                system.Warn("Failed to refresh checkpoint listing:", err)
+                continue
+            }
+            system.Info("Checkpoint listing refreshed -", list.TotalCount, "checkpoints")
+            if onChange != nil {
+                onChange(list)
+            }
+        }
+    }
+}
+
+// isCheckpointEvent reports whether an event can change the set of checkpoints
+func isCheckpointEvent(event fsnotify.Event) bool {
+    if !strings.HasSuffix(event.Name, ".bin") {
+        return false // Temp files, the lock file and the debug side-car
+    }
+    return event.Has(fsnotify.Create) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename)
+}
@@ -0,0 +1,310 @@
+package i18n
+
+// catalog maps locale -> message key -> format string. Keys are grouped by
+// where the message appears: notify.* and dialog.* for notifications and
+// dialogs, status.*, checkpoint.*, restore.*, crashes.* and blacklist.* for CLI output.
+var catalog = map[string]map[string]string{
+	"en": {
+		// Shared
+		"enabled":     "✅ Enabled",
+		"disabled":    "❌ Disabled",
+		"granted":     "✅ Granted",
+		"not_granted": "❌ Not granted",
+
+		"duration.seconds":         "%d seconds",
+		"duration.minutes":         "%d minutes",
+		"duration.minutes_seconds": "%d minutes %d seconds",
+
+		// Notifications
+		"notify.app_restored":        "%s ✅",
+		"notify.restore_complete":    "✅ Restored %d applications in %s",
+		"notify.restore_partial":     "⚠️ Restored %d/%d applications\n%d failed\n\nCheck: respawn status",
+		"notify.not_installed":       "Not installed: %s",
+		"notify.already_running":     "Already running: %s",
+		"notify.reopened":            "Already restored by macOS: %s",
+		"notify.woke":                "Slept %s–%s (%s)",
+		"notify.manual_only":         "Open yourself: %s",
+		"notify.manual_only_marked":  "Failed repeatedly, now manual restore only: %s",
+		"notify.blacklisted":         "Blacklisted after timing out: %s",
+		"notify.restart_dev_servers": "Restart dev servers: %s",
+		"notify.checkpoint_failed":   "❌ Checkpoint Failed\n\n%s\n\nTime: %s",
+		"notify.checkpoint_saved":    "📸 Checkpoint saved",
+		"notify.digest":              "🌙 While quiet hours were on:",
+		"notify.digest_more":         "…and %d more",
+		"notify.disk_elsewhere":      "Checkpoints take little space - free up disk space elsewhere.",
+		"notify.disk_prune":          "Prune %d old checkpoints to free %s.",
+		"notify.disk_full":           "Disk is %.0f%% full. %s",
+		"notify.disk_run_prune":      "Run: respawn prune --free %s",
+		"notify.team_shared":         "📤 Checkpoint shared with team (%d members)\n%s",
+		"notify.team_available":      "📥 New team checkpoint available\nFrom: %s\n%s",
+		"notify.restore_progress":    "Restoring: %s\n%d of %d applications",
+		"notify.status_summary":      "RESPAWN Status\n\nLast Checkpoint: %s\nTotal Checkpoints: %d\nAuto-start: %s\nHealth: %s",
+		"notify.paused_title":        "RESPAWN Paused",
+		"notify.paused":              "Checkpoints paused until you run: respawn resume",
+		"notify.paused_until":        "Checkpoints paused until %s",
+		"notify.resumed_title":       "RESPAWN Resumed",
+		"notify.resumed":             "Monitoring workspace",
+
+		// Dialogs
+		"dialog.ok":               "OK",
+		"dialog.not_now":          "Not Now",
+		"dialog.later":            "Remind Me Later",
+		"dialog.prune":            "Prune",
+		"dialog.restore":          "Restore",
+		"dialog.cancel":           "Cancel",
+		"dialog.grant":            "Grant Permission",
+		"dialog.quit":             "Quit",
+		"dialog.disk_title":       "RESPAWN - Low Disk Space",
+		"dialog.restore_title":    "RESPAWN - Restore Workspace",
+		"dialog.restore_prompt":   "Your Mac restarted. Restore the workspace from %s?\n\n%s",
+		"dialog.permission_title": "Permission Required",
+		"dialog.permission":       "RESPAWN needs %s permission.\n\n%s",
+		"dialog.select_title":     "Select Checkpoint",
+		"dialog.select":           "Available Checkpoints:\n\n%s\n\nEnter checkpoint number to restore:",
+		"dialog.crash_title":      "RESPAWN Auto-start disabled",
+		"dialog.crash":            "RESPAWN has crashed %d times in the last %v.\n\nAuto-start has been disabled for safety.\n\nTo re-enable:\nOpen Terminal and run: respawn crashes reset",
+
+		// status
+		"status.header":          "=== RESPAWN STATUS ===",
+		"status.version":         "Version: %s",
+		"status.running":         "Running: %s",
+		"status.autostart":       "Auto-start: %s",
+		"status.profile":         "Profile: %s",
+		"status.crashes":         "Crashes: %d in the last %v",
+		"status.crash_disabled":  "  ⚠️  Auto-start disabled after repeated crashes (see: respawn crashes, re-enable: respawn crashes reset)",
+		"status.paused":          "Status: ⏸️  PAUSED",
+		"status.paused_until":    "Status: ⏸️  PAUSED until %s",
+		"status.active":          "Status: ✅ ACTIVE - Monitoring",
+		"status.stopped":         "Status: ❌ STOPPED",
+		"status.daemon":          "Daemon:",
+		"status.pid":             "  PID: %d",
+		"status.uptime":          "  Uptime: %s",
+		"status.heartbeat":       "  Last heartbeat: %s ago",
+		"status.last_checkpoint": "  Last checkpoint took: %s",
+		"status.resources":       "  Resource usage: %d MB, %.1f%% CPU",
+		"status.blocked_by":      "  Checkpoints blocked by: %s",
+		"status.blocked_nothing": "  Checkpoints blocked by: nothing",
+		"status.last_sleep":      "  Last sleep: %s–%s (%s)",
+		"status.checkpoints":     "Checkpoints:",
+		"status.total":           "  Total: %d",
+		"status.latest":          "  Latest: %s",
+		"status.basic_capture":   "  Capture: basic (app list only, window state needs Accessibility)",
+		"status.created":         "  Created: %s",
+		"status.note":            "  Note: %s",
+		"status.latest_apps":     "  Apps in latest: %d",
+		"status.applications":    "  Applications:",
+		"status.more_apps":       "    ... and %d more",
+		"status.next_checkpoint": "  Next checkpoint in: %s",
+		"status.overdue":         "  Next checkpoint: Overdue (should create soon)",
+		"status.no_checkpoints":  "  No checkpoints yet",
+		"status.restores":        "Restores:",
+		"status.interrupted":     "  ⏸️  Interrupted restore of %s, %d apps left (finish with: respawn restore --continue)",
+		"status.restoring":       "  ▶️  Restoring %s, %d apps left (process %d)",
+		"status.success_rate":    "  App success rate: %.0f%%",
+		"status.manual_only":     "  ✋ Manual restore only: %s (retry with: respawn restore --include-manual)",
+		"status.blacklisted":     "  🚫 Blacklisted: %s (see: respawn blacklist)",
+		"status.permissions":     "Permissions:",
+		"status.not_checked":     "  Not checked yet - run 'respawn doctor'",
+		"status.automation":      "  Automation: %s",
+		"status.accessibility":   "  Accessibility: %s",
+		"status.screen":          "  Screen Recording: %s",
+		"status.screen_missing":  "  Screen Recording: %s - window titles not captured",
+		"status.checked_at":      "  Last checked: %s",
+		"status.configuration":   "Configuration:",
+		"status.interval":        "  Checkpoint interval: %v",
+		"status.retention":       "  Data retention: %d days",
+		"status.loops":           "  Loop intervals: monitor %v, heartbeat %v, learning %v",
+
+		// checkpoint
+		"checkpoint.created":         "✅ Checkpoint created: %s",
+		"checkpoint.created_partial": "✅ Partial checkpoint created: %s",
+		"checkpoint.note":            "   Note: %s",
+
+		// restore
+		"restore.done":        "✅ Restored %d applications in %s",
+		"restore.running":     "⏩ Already running: %s",
+		"restore.reopened":    "⏩ Already restored by macOS: %s",
+		"restore.interrupted": "⏸️  A restore of %s was interrupted with %d apps left. Finish it with: respawn restore --continue",
+		"restore.continuing":  "▶️  Continuing the restore of %s: %d apps left",
+		"restore.upgraded":    "🔄 %s changed from %s to %s since this checkpoint",
+		"restore.upgraded_major": "⚠️  %s was upgraded from %s to %s since this checkpoint; its session may not restore",
+		"restore.manual_only": "✋ Manual restore only, open them yourself: %s (retry with: respawn restore --include-manual)",
+		"restore.manual_only_marked": "⚠️  Failed %d restores in a row, now manual restore only: %s",
+		"restore.blacklisted": "🚫 Blacklisted, skipped: %s (see: respawn blacklist)",
+		"restore.blacklisted_now": "🚫 Timed out repeatedly, blacklisted for %s: %s (clear with: respawn blacklist clear)",
+		"restore.failed":      "⚠️  %d applications failed to restore",
+		"restore.skipped":     "⏭️  Skipped - no longer installed:",
+		"restore.dev_servers": "💡 These apps were serving ports at checkpoint time - restart your dev servers:",
+		"restore.pick_header": "Restore which checkpoint?",
+		"restore.pick_entry":  "  %2d. %s  %s",
+		"restore.pick_note":   "  \"%s\"",
+		"restore.pick_prompt": "Number [1], or q to cancel: ",
+
+		// crashes
+		"crashes.none":      "No crashes recorded",
+		"crashes.summary":   "💥 %d crashes recorded, %d in the last %v",
+		"crashes.unknown":   "unknown",
+		"crashes.disabled":  "⚠️  Auto-start was disabled after repeated crashes. Re-enable it with: respawn crashes reset",
+		"crashes.cleared":   "✅ Crash history cleared",
+		"crashes.reenabled": "   Auto-start re-enabled",
+
+		// blacklist
+		"blacklist.none":    "No apps are blacklisted",
+		"blacklist.header":  "🚫 Apps skipped by restores after repeated launch timeouts:",
+		"blacklist.entry":   "   %s  until %s",
+		"blacklist.hint":    "Clear with: respawn blacklist clear [app]",
+		"blacklist.cleared": "✅ Cleared %d blacklisted apps",
+	},
+
+	"es": {
+		// Shared
+		"enabled":     "✅ Activado",
+		"disabled":    "❌ Desactivado",
+		"granted":     "✅ Concedido",
+		"not_granted": "❌ No concedido",
+
+		"duration.seconds":         "%d segundos",
+		"duration.minutes":         "%d minutos",
+		"duration.minutes_seconds": "%d minutos %d segundos",
+
+		// Notifications
+		"notify.app_restored":        "%s ✅",
+		"notify.restore_complete":    "✅ %d aplicaciones restauradas en %s",
+		"notify.restore_partial":     "⚠️ %d/%d aplicaciones restauradas\n%d fallaron\n\nRevisa: respawn status",
+		"notify.not_installed":       "No instaladas: %s",
+		"notify.already_running":     "Ya en ejecución: %s",
+		"notify.reopened":            "Ya restauradas por macOS: %s",
+		"notify.woke":                "En reposo %s–%s (%s)",
+		"notify.manual_only":         "Ábrelas tú: %s",
+		"notify.manual_only_marked":  "Fallaron repetidamente, ahora solo restauración manual: %s",
+		"notify.blacklisted":         "En la lista negra por agotar el tiempo: %s",
+		"notify.restart_dev_servers": "Reinicia los servidores de desarrollo: %s",
+		"notify.checkpoint_failed":   "❌ Falló el punto de control\n\n%s\n\nHora: %s",
+		"notify.checkpoint_saved":    "📸 Punto de control guardado",
+		"notify.digest":              "🌙 Mientras duraban las horas de silencio:",
+		"notify.digest_more":         "…y %d más",
+		"notify.disk_elsewhere":      "Los puntos de control ocupan poco espacio: libera espacio en otro lugar.",
+		"notify.disk_prune":          "Elimina %d puntos de control antiguos para liberar %s.",
+		"notify.disk_full":           "El disco está al %.0f%%. %s",
+		"notify.disk_run_prune":      "Ejecuta: respawn prune --free %s",
+		"notify.team_shared":         "📤 Punto de control compartido con el equipo (%d miembros)\n%s",
+		"notify.team_available":      "📥 Nuevo punto de control del equipo\nDe: %s\n%s",
+		"notify.restore_progress":    "Restaurando: %s\n%d de %d aplicaciones",
+		"notify.status_summary":      "Estado de RESPAWN\n\nÚltimo punto de control: %s\nPuntos de control: %d\nInicio automático: %s\nSalud: %s",
+		"notify.paused_title":        "RESPAWN en pausa",
+		"notify.paused":              "Puntos de control en pausa hasta que ejecutes: respawn resume",
+		"notify.paused_until":        "Puntos de control en pausa hasta las %s",
+		"notify.resumed_title":       "RESPAWN reanudado",
+		"notify.resumed":             "Supervisando el espacio de trabajo",
+
+		// Dialogs
+		"dialog.ok":               "Aceptar",
+		"dialog.not_now":          "Ahora no",
+		"dialog.later":            "Recordármelo más tarde",
+		"dialog.prune":            "Eliminar",
+		"dialog.restore":          "Restaurar",
+		"dialog.cancel":           "Cancelar",
+		"dialog.grant":            "Conceder permiso",
+		"dialog.quit":             "Salir",
+		"dialog.disk_title":       "RESPAWN - Poco espacio en disco",
+		"dialog.restore_title":    "RESPAWN - Restaurar espacio de trabajo",
+		"dialog.restore_prompt":   "Tu Mac se reinició. ¿Restaurar el espacio de trabajo de %s?\n\n%s",
+		"dialog.permission_title": "Permiso necesario",
+		"dialog.permission":       "RESPAWN necesita el permiso de %s.\n\n%s",
+		"dialog.select_title":     "Seleccionar punto de control",
+		"dialog.select":           "Puntos de control disponibles:\n\n%s\n\nEscribe el número del punto de control a restaurar:",
+		"dialog.crash_title":      "Inicio automático de RESPAWN desactivado",
+		"dialog.crash":            "RESPAWN ha fallado %d veces en los últimos %v.\n\nEl inicio automático se ha desactivado por seguridad.\n\nPara reactivarlo:\nAbre Terminal y ejecuta: respawn crashes reset",
+
+		// status
+		"status.header":          "=== ESTADO DE RESPAWN ===",
+		"status.version":         "Versión: %s",
+		"status.running":         "En ejecución: %s",
+		"status.autostart":       "Inicio automático: %s",
+		"status.profile":         "Perfil: %s",
+		"status.crashes":         "Fallos: %d en los últimos %v",
+		"status.crash_disabled":  "  ⚠️  Inicio automático desactivado tras fallos repetidos (ver: respawn crashes, reactivar: respawn crashes reset)",
+		"status.paused":          "Estado: ⏸️  EN PAUSA",
+		"status.paused_until":    "Estado: ⏸️  EN PAUSA hasta las %s",
+		"status.active":          "Estado: ✅ ACTIVO - Supervisando",
+		"status.stopped":         "Estado: ❌ DETENIDO",
+		"status.daemon":          "Demonio:",
+		"status.pid":             "  PID: %d",
+		"status.uptime":          "  Tiempo activo: %s",
+		"status.heartbeat":       "  Último latido: hace %s",
+		"status.last_checkpoint": "  El último punto de control tardó: %s",
+		"status.resources":       "  Uso de recursos: %d MB, %.1f%% CPU",
+		"status.blocked_by":      "  Puntos de control bloqueados por: %s",
+		"status.blocked_nothing": "  Puntos de control bloqueados por: nada",
+		"status.last_sleep":      "  Último reposo: %s–%s (%s)",
+		"status.checkpoints":     "Puntos de control:",
+		"status.total":           "  Total: %d",
+		"status.latest":          "  Último: %s",
+		"status.basic_capture":   "  Captura: básica (solo aplicaciones, el estado de las ventanas requiere Accesibilidad)",
+		"status.created":         "  Creado: %s",
+		"status.note":            "  Nota: %s",
+		"status.latest_apps":     "  Aplicaciones en el último: %d",
+		"status.applications":    "  Aplicaciones:",
+		"status.more_apps":       "    ... y %d más",
+		"status.next_checkpoint": "  Próximo punto de control en: %s",
+		"status.overdue":         "  Próximo punto de control: atrasado (se creará pronto)",
+		"status.no_checkpoints":  "  Aún no hay puntos de control",
+		"status.restores":        "Restauraciones:",
+		"status.interrupted":     "  ⏸️  Restauración de %s interrumpida, %d aplicaciones pendientes (terminar con: respawn restore --continue)",
+		"status.restoring":       "  ▶️  Restaurando %s, %d aplicaciones pendientes (proceso %d)",
+		"status.success_rate":    "  Tasa de éxito por aplicación: %.0f%%",
+		"status.manual_only":     "  ✋ Solo restauración manual: %s (reintentar con: respawn restore --include-manual)",
+		"status.blacklisted":     "  🚫 En la lista negra: %s (ver: respawn blacklist)",
+		"status.permissions":     "Permisos:",
+		"status.not_checked":     "  Sin comprobar - ejecuta 'respawn doctor'",
+		"status.automation":      "  Automatización: %s",
+		"status.accessibility":   "  Accesibilidad: %s",
+		"status.screen":          "  Grabación de pantalla: %s",
+		"status.screen_missing":  "  Grabación de pantalla: %s - no se capturan los títulos de las ventanas",
+		"status.checked_at":      "  Última comprobación: %s",
+		"status.configuration":   "Configuración:",
+		"status.interval":        "  Intervalo de puntos de control: %v",
+		"status.retention":       "  Retención de datos: %d días",
+		"status.loops":           "  Intervalos: supervisión %v, latido %v, aprendizaje %v",
+
+		// checkpoint
+		"checkpoint.created":         "✅ Punto de control creado: %s",
+		"checkpoint.created_partial": "✅ Punto de control parcial creado: %s",
+		"checkpoint.note":            "   Nota: %s",
+
+		// restore
+		"restore.done":        "✅ %d aplicaciones restauradas en %s",
+		"restore.running":     "⏩ Ya en ejecución: %s",
+		"restore.reopened":    "⏩ Ya restauradas por macOS: %s",
+		"restore.interrupted": "⏸️  Se interrumpió una restauración de %s con %d aplicaciones pendientes. Termínala con: respawn restore --continue",
+		"restore.continuing":  "▶️  Continuando la restauración de %s: %d aplicaciones pendientes",
+		"restore.upgraded":    "🔄 %s cambió de %s a %s desde este punto de control",
+		"restore.upgraded_major": "⚠️  %s se actualizó de %s a %s desde este punto de control; puede que su sesión no se restaure",
+		"restore.manual_only": "✋ Solo restauración manual, ábrelas tú: %s (reintentar con: respawn restore --include-manual)",
+		"restore.manual_only_marked": "⚠️  Fallaron %d restauraciones seguidas, ahora solo restauración manual: %s",
+		"restore.blacklisted": "🚫 En la lista negra, omitidas: %s (ver: respawn blacklist)",
+		"restore.blacklisted_now": "🚫 Agotaron el tiempo repetidamente, en la lista negra durante %s: %s (borrar con: respawn blacklist clear)",
+		"restore.failed":      "⚠️  %d aplicaciones no se pudieron restaurar",
+		"restore.skipped":     "⏭️  Omitidas - ya no están instaladas:",
+		"restore.dev_servers": "💡 Estas aplicaciones servían puertos al crear el punto de control - reinicia tus servidores de desarrollo:",
+		"restore.pick_header": "¿Qué punto de control restaurar?",
+		"restore.pick_entry":  "  %2d. %s  %s",
+		"restore.pick_note":   "  «%s»",
+		"restore.pick_prompt": "Número [1], o q para cancelar: ",
+
+		// crashes
+		"crashes.none":      "No hay fallos registrados",
+		"crashes.summary":   "💥 %d fallos registrados, %d en los últimos %v",
+		"crashes.unknown":   "desconocido",
+		"crashes.disabled":  "⚠️  El inicio automático se desactivó tras fallos repetidos. Reactívalo con: respawn crashes reset",
+		"crashes.cleared":   "✅ Historial de fallos borrado",
+		"crashes.reenabled": "   Inicio automático reactivado",
+
+		// blacklist
+		"blacklist.none":    "No hay aplicaciones en la lista negra",
+		"blacklist.header":  "🚫 Aplicaciones omitidas en las restauraciones tras agotar el tiempo de inicio:",
+		"blacklist.entry":   "   %s  hasta %s",
+		"blacklist.hint":    "Borrar con: respawn blacklist clear [app]",
+		"blacklist.cleared": "✅ %d aplicaciones quitadas de la lista negra",
+	},
+}
@@ -0,0 +1,74 @@
+// Package i18n holds the message catalog for user-facing strings: CLI output,
+// notifications and dialogs. Messages are looked up by key in the active locale,
+// falling back to English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"RESPAWN/pkg/config"
+)
+
+// DefaultLocale is used when no supported locale is configured or detected
+const DefaultLocale = "en"
+
+// Supported reports whether a locale has a catalog
+func Supported(locale string) bool {
+	_, ok := catalog[locale]
+	return ok
+}
+
+// Locale returns the active locale: the language setting from the config, else
+// the first of LC_ALL, LC_MESSAGES and LANG, else English
+func Locale() string {
+	if config.Current() != nil && Supported(config.Current().Language) {
+		return config.Current().Language
+	}
+
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		// e.g. "es_ES.UTF-8" -> "es"
+		language := strings.ToLower(strings.SplitN(strings.SplitN(value, ".", 2)[0], "_", 2)[0])
+		if Supported(language) {
+			return language
+		}
+		break // The first one set wins, even if unsupported
+	}
+	return DefaultLocale
+}
+
+// Duration formats d for humans in the active locale, e.g. "2 minutes 5 seconds"
+func Duration(d time.Duration) string {
+	seconds := int(d.Seconds())
+	if seconds < 60 {
+		return T("duration.seconds", seconds)
+	}
+
+	minutes := seconds / 60
+	remainingSeconds := seconds % 60
+	if remainingSeconds == 0 {
+		return T("duration.minutes", minutes)
+	}
+	return T("duration.minutes_seconds", minutes, remainingSeconds)
+}
+
+// T returns the message for key in the active locale, formatted with args
+func T(key string, args ...interface{}) string {
+	message, ok := catalog[Locale()][key]
+	if !ok {
+		if message, ok = catalog[DefaultLocale][key]; !ok {
+			message = key
+		}
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
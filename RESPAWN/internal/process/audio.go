@@ -0,0 +1,100 @@
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+)
+
+// DetectAudioState returns the current sound output device and volume
+func DetectAudioState() (*types.AudioState, error) {
+	cmd := exec.Command("osascript", "-e", "get volume settings")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume settings: %w", err)
+	}
+
+	// Output looks like: "output volume:50, input volume:75, alert volume:100, output muted:false"
+	state := &types.AudioState{}
+	for _, field := range strings.Split(strings.TrimSpace(string(output)), ", ") {
+		parts := strings.SplitN(field, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		switch parts[0] {
+		case "output volume":
+			if volume, err := strconv.Atoi(parts[1]); err == nil {
+				state.Volume = volume
+			}
+		case "output muted":
+			state.Muted = parts[1] == "true"
+		}
+	}
+
+	device, err := getOutputDevice()
+	if err != nil {
+		system.Debug("Could not determine output device:", err)
+	}
+	state.OutputDevice = device
+
+	return state, nil
+}
+
+// getOutputDevice returns the name of the default sound output device
+func getOutputDevice() (string, error) {
+	// Prefer SwitchAudioSource when installed, it is fast and exact
+	if _, err := exec.LookPath("SwitchAudioSource"); err == nil {
+		output, err := exec.Command("SwitchAudioSource", "-c", "-t", "output").Output()
+		if err == nil {
+			return strings.TrimSpace(string(output)), nil
+		}
+	}
+
+	output, err := exec.Command("system_profiler", "SPAudioDataType").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to execute system_profiler: %w", err)
+	}
+
+	// Device names are the "Name:" lines preceding their properties
+	var currentDevice string
+	for _, line := range strings.Split(string(output), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasSuffix(trimmed, ":") {
+			currentDevice = strings.TrimSuffix(trimmed, ":")
+			continue
+		}
+		if trimmed == "Default Output Device: Yes" {
+			return currentDevice, nil
+		}
+	}
+
+	return "", fmt.Errorf("default output device not found")
+}
+
+// RestoreAudioState applies a recorded output device and volume
+func RestoreAudioState(state *types.AudioState) error {
+	if state == nil {
+		return nil
+	}
+
+	if state.OutputDevice != "" {
+		if _, err := exec.LookPath("SwitchAudioSource"); err != nil {
+			system.Warn("SwitchAudioSource not installed - cannot restore output device", state.OutputDevice)
+		} else if err := exec.Command("SwitchAudioSource", "-t", "output", "-s", state.OutputDevice).Run(); err != nil {
+			system.Warn("Failed to switch output device to", state.OutputDevice, ":", err)
+		}
+	}
+
+	script := fmt.Sprintf("set volume output volume %d output muted %t", state.Volume, state.Muted)
+	if err := exec.Command("osascript", "-e", script).Run(); err != nil {
+		return fmt.Errorf("failed to set volume: %w", err)
+	}
+
+	system.Info("Restored audio state - device:", state.OutputDevice, "volume:", state.Volume, "muted:", state.Muted)
+	return nil
+}
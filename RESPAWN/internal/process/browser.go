@@ -0,0 +1,109 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"RESPAWN/internal/system"
+)
+
+// chromiumProfileDirs maps Chromium-based browsers to their support directory
+// under ~/Library/Application Support
+var chromiumProfileDirs = map[string]string{
+	"Google Chrome": "Google/Chrome",
+	"Brave Browser": "BraveSoftware/Brave-Browser",
+}
+
+// chromiumLocalState is the part of Chromium's "Local State" file we need
+type chromiumLocalState struct {
+	Profile struct {
+		LastActiveProfiles []string `json:"last_active_profiles"`
+	} `json:"profile"`
+}
+
+// IsProfileBrowser reports whether RESPAWN knows how to capture profiles for an app
+func IsProfileBrowser(appName string) bool {
+	_, chromium := chromiumProfileDirs[appName]
+	return chromium || appName == "Firefox"
+}
+
+// DetectBrowserProfiles returns the profiles a running browser has windows open in.
+// An empty result means only the default profile is in use.
+func DetectBrowserProfiles(appName string, pid int) ([]string, error) {
+	if dir, ok := chromiumProfileDirs[appName]; ok {
+		return detectChromiumProfiles(dir)
+	}
+	if appName == "Firefox" {
+		return detectFirefoxProfile(pid)
+	}
+	return nil, nil
+}
+
+// detectChromiumProfiles reads the profiles with open windows from "Local State"
+func detectChromiumProfiles(supportDir string) ([]string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(homeDir, "Library", "Application Support", supportDir, "Local State"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Local State: %w", err)
+	}
+
+	var state chromiumLocalState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse Local State: %w", err)
+	}
+
+	// A single default profile needs no special handling on restore
+	profiles := state.Profile.LastActiveProfiles
+	if len(profiles) == 1 && profiles[0] == "Default" {
+		return nil, nil
+	}
+	return profiles, nil
+}
+
+// detectFirefoxProfile reads the -P/-profile argument Firefox was started with
+func detectFirefoxProfile(pid int) ([]string, error) {
+	output, err := exec.Command("ps", "-o", "args=", "-p", fmt.Sprint(pid)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read process arguments: %w", err)
+	}
+
+	args := strings.Fields(string(output))
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == "-P" || args[i] == "-profile" || args[i] == "--profile" {
+			return []string{args[i+1]}, nil
+		}
+	}
+	return nil, nil
+}
+
+// profileArgs returns the command line that opens a browser in a given profile
+func profileArgs(appName, profile string) []string {
+	if appName == "Firefox" {
+		if filepath.IsAbs(profile) {
+			return []string{"-profile", profile}
+		}
+		return []string{"-P", profile}
+	}
+	return []string{"--profile-directory=" + profile}
+}
+
+// openProfile launches another window of a running browser in the given profile
+func openProfile(appTarget []string, appName, profile string) error {
+	args := append([]string{"-n"}, appTarget...)
+	args = append(args, "--args")
+	args = append(args, profileArgs(appName, profile)...)
+
+	system.Debug("Opening", appName, "profile", profile)
+	if err := exec.Command("open", args...).Run(); err != nil {
+		return fmt.Errorf("failed to open %s profile %s: %w", appName, profile, err)
+	}
+	return nil
+}
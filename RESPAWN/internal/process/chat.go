@@ -0,0 +1,164 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+)
+
+// chatAppSuffixes are the trailing title parts each chat app appends to its window title
+var chatAppSuffixes = map[string]string{
+	"Slack":           "Slack",
+	"Discord":         "Discord",
+	"Microsoft Teams": "Microsoft Teams",
+}
+
+// IsChatApp reports whether RESPAWN can capture workspace/channel context for an app
+func IsChatApp(appName string) bool {
+	_, ok := chatAppSuffixes[appName]
+	return ok
+}
+
+// ParseChatTitle extracts the workspace and channel from a chat app's focused window title.
+// Titles look like:
+//
+//	Slack:   "general (Channel) - Acme - Slack"
+//	Discord: "#general | Acme Server - Discord"
+//	Teams:   "Chat | Jane Doe | Microsoft Teams"
+func ParseChatTitle(appName, title string) *types.ChatContext {
+	suffix, ok := chatAppSuffixes[appName]
+	if !ok || title == "" {
+		return nil
+	}
+
+	ctx := &types.ChatContext{WindowTitle: title}
+
+	switch appName {
+	case "Slack":
+		parts := splitTitle(title, " - ", suffix)
+		if len(parts) >= 2 {
+			ctx.Channel = strings.TrimSpace(strings.Split(parts[0], " (")[0])
+			ctx.Workspace = parts[len(parts)-1]
+		}
+		ctx.DeepLink = slackDeepLink(ctx.Workspace)
+	case "Discord":
+		parts := splitTitle(title, " - ", suffix)
+		if len(parts) >= 1 {
+			fields := strings.SplitN(parts[0], " | ", 2)
+			ctx.Channel = strings.TrimPrefix(strings.TrimSpace(fields[0]), "#")
+			if len(fields) == 2 {
+				ctx.Workspace = strings.TrimSpace(fields[1])
+			}
+		}
+	case "Microsoft Teams":
+		parts := splitTitle(title, " | ", suffix)
+		if len(parts) >= 2 {
+			ctx.Channel = parts[len(parts)-1]
+			ctx.Workspace = parts[0]
+		} else if len(parts) == 1 {
+			ctx.Channel = parts[0]
+		}
+	}
+
+	if ctx.Channel == "" && ctx.Workspace == "" {
+		return nil
+	}
+	return ctx
+}
+
+// RestoreChatContext reopens the recorded workspace and channel in a launched chat app.
+// The workspace is opened via deep link when known, the channel through the app's quick switcher.
+func RestoreChatContext(appName string, ctx *types.ChatContext, canScript bool) error {
+	if ctx == nil {
+		return nil
+	}
+
+	if ctx.DeepLink != "" {
+		if err := exec.Command("open", ctx.DeepLink).Run(); err != nil {
+			system.Warn("Failed to open", appName, "deep link:", err)
+		}
+	}
+
+	// The quick switcher is driven by keystrokes, which need Accessibility
+	if ctx.Channel == "" || !canScript {
+		return nil
+	}
+
+	// Slack and Discord use Cmd+K, Teams uses Cmd+E for search
+	key := "k"
+	if appName == "Microsoft Teams" {
+		key = "e"
+	}
+
+	script := fmt.Sprintf(`
+        tell application %s to activate
+        delay 1
+        tell application "System Events"
+            keystroke %s using command down
+            delay 0.5
+            keystroke %s
+            delay 0.5
+            key code 36
+        end tell
+    `, system.AppleScriptString(appName), system.AppleScriptString(key), system.AppleScriptString(ctx.Channel))
+
+	if err := exec.Command("osascript", "-e", script).Run(); err != nil {
+		return fmt.Errorf("failed to switch %s to %s: %w", appName, ctx.Channel, err)
+	}
+
+	system.Info("Restored", appName, "context:", ctx.Workspace, "/", ctx.Channel)
+	return nil
+}
+
+// slackDeepLink looks up the workspace's team ID in Slack's local state and
+// builds a slack://open link for it, or returns "" when it can't be found
+func slackDeepLink(workspace string) string {
+	if workspace == "" {
+		return ""
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(homeDir, "Library", "Application Support", "Slack", "storage", "root-state.json"))
+	if err != nil {
+		return ""
+	}
+
+	var state struct {
+		Workspaces map[string]struct {
+			Name string `json:"name"`
+		} `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return ""
+	}
+
+	for teamID, ws := range state.Workspaces {
+		if ws.Name == workspace {
+			return "slack://open?team=" + teamID
+		}
+	}
+	return ""
+}
+
+// splitTitle splits a window title and drops the app-name suffix
+func splitTitle(title, sep, suffix string) []string {
+	var parts []string
+	for _, part := range strings.Split(title, sep) {
+		part = strings.TrimSpace(part)
+		if part != "" && part != suffix {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
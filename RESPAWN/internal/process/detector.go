@@ -6,28 +6,78 @@ import (
 	"RESPAWN/pkg/config"
 	"fmt"
 	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 type ProcessDetector struct {
 	enabledApps []config.AppConfig
+
+	// Basic mode skips window state when Accessibility is missing
+	basicMode bool
+
+	// Window titles need Screen Recording permission, checked once per detector
+	titlesChecked bool
+	canReadTitles bool
 }
 
 // NewProcessDetector creates a new process detector
 func NewProcessDetector() *ProcessDetector {
 	return &ProcessDetector{
-		enabledApps: config.GlobalConfig.GetEnabledApplications(),
+		enabledApps: config.Current().GetEnabledApplications(),
+		basicMode:   system.IsDegradedMode(),
 	}
 }
 
+// IsBasicMode reports whether the detector only captures the app list
+func (pd *ProcessDetector) IsBasicMode() bool {
+	return pd.basicMode
+}
+
 // DetectRunningProcesses finds all enabled applications that are currently running
 func (pd *ProcessDetector) DetectRunningProcesses() ([]types.ProcessInfo, error) {
+	return pd.DetectProcesses(pd.enabledApps)
+}
+
+// ResolveApps maps user-supplied app names to app configs. Names match configured
+// apps by name or process name, case-insensitively and by substring ("Chrome"
+// matches "Google Chrome"); unknown names are used as process names as-is.
+func ResolveApps(names []string) []config.AppConfig {
+	var apps []config.AppConfig
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		needle := strings.ToLower(name)
+		found := false
+		for _, app := range config.Current().Applications {
+			if strings.Contains(strings.ToLower(app.Name), needle) ||
+				strings.Contains(strings.ToLower(app.ProcessName), needle) {
+				apps = append(apps, app)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			apps = append(apps, config.AppConfig{Name: name, ProcessName: name, Enabled: true})
+		}
+	}
+
+	return apps
+}
+
+// DetectProcesses finds which of the given applications are currently running
+func (pd *ProcessDetector) DetectProcesses(apps []config.AppConfig) ([]types.ProcessInfo, error) {
 	system.Debug("Starting process detection")
 
 	var runningProcesses []types.ProcessInfo
 
-	for _, app := range pd.enabledApps {
+	for _, app := range apps {
 		processInfo, err := pd.getProcessInfo(app)
 		if err != nil {
 			system.Warn("Failed to get process info for", app.Name, ":", err)
@@ -39,6 +89,11 @@ func (pd *ProcessDetector) DetectRunningProcesses() ([]types.ProcessInfo, error)
 			system.Debug("Found running process:", app.Name, "PID:", processInfo.PID, "Memory:", processInfo.MemoryMB, "MB")
 		}
 	}
+	// Restores after a reboot leave login items to macOS
+	if !pd.basicMode {
+		MarkLoginItems(runningProcesses)
+	}
+
 	system.Info("Detected", len(runningProcesses), "running processes")
 	return runningProcesses, nil
 }
@@ -121,13 +176,52 @@ func (pd *ProcessDetector) getProcessInfo(app config.AppConfig) (types.ProcessIn
 			ProcessInfo.MemoryMB = memoryMB
 			ProcessInfo.IsRunning = true
 
-			// get window state (simplified for now)
-			windowState, err := pd.getWindowState(pid)
+			// Record where the bundle lives so restore doesn't assume /Applications
+			bundleID, err := pd.getBundleID(pid)
 			if err != nil {
-				system.Debug("Could not get window state for", app.Name, ":", err)
-				windowState = "normal" // default
+				system.Debug("Could not get bundle ID for", app.Name, ":", err)
+			}
+			ProcessInfo.BundleID = bundleID
+			ProcessInfo.BundlePath = ResolveBundlePath(bundleID, app.Name)
+			ProcessInfo.BundleVersion = BundleVersion(ProcessInfo.BundlePath)
+
+			// get window state (simplified for now), skipped in basic mode
+			if !pd.basicMode && config.Current().CaptureAllowed(config.PrivacyGeometry) {
+				windowState, err := pd.getWindowState(pid)
+				if err != nil {
+					system.Debug("Could not get window state for", app.Name, ":", err)
+					windowState = "normal" // default
+				}
+				ProcessInfo.WindowState = windowState
+			}
+
+			// Record browser profiles so windows reopen in the right one
+			if config.Current().CaptureBrowserProfiles && IsProfileBrowser(app.Name) {
+				profiles, err := DetectBrowserProfiles(app.Name, pid)
+				if err != nil {
+					system.Debug("Could not get browser profiles for", app.Name, ":", err)
+				}
+				ProcessInfo.Profiles = profiles
+			}
+
+			// Record the focused workspace/channel of chat apps from the window title
+			if config.Current().CaptureChatContext && config.Current().CaptureAllowed(config.PrivacyTitles) && IsChatApp(app.Name) && !pd.basicMode {
+				windows, err := pd.getWindowInfo(app.ProcessName)
+				if err != nil {
+					system.Debug("Could not get windows for", app.Name, ":", err)
+				} else if len(windows) > 0 {
+					ProcessInfo.Chat = ParseChatTitle(app.Name, windows[0].Title)
+				}
+			}
+
+			// Record listening ports so restore can remind about dev servers
+			if config.Current().CaptureListeningPorts {
+				ports, err := pd.getListeningPorts(pid)
+				if err != nil {
+					system.Debug("Could not get listening ports for", app.Name, ":", err)
+				}
+				ProcessInfo.ListeningPorts = ports
 			}
-			ProcessInfo.WindowState = windowState
 
 			break
 		}
@@ -171,6 +265,43 @@ func (pd *ProcessDetector) getWindowState(pid int) (string, error) {
 	return "normal", nil
 }
 
+// getListeningPorts returns the TCP ports a process is listening on
+func (pd *ProcessDetector) getListeningPorts(pid int) ([]int, error) {
+	// -F n prints one "n<address>:<port>" line per socket
+	cmd := exec.Command("lsof", "-nP", "-a", "-iTCP", "-sTCP:LISTEN", "-p", strconv.Itoa(pid), "-Fn")
+	output, err := cmd.Output()
+	if err != nil {
+		// lsof exits 1 when the process has no matching sockets
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to execute lsof command: %w", err)
+	}
+
+	seen := make(map[int]bool)
+	var ports []int
+	for _, line := range strings.Split(string(output), "\n") {
+		if !strings.HasPrefix(line, "n") {
+			continue
+		}
+
+		idx := strings.LastIndex(line, ":")
+		if idx == -1 {
+			continue
+		}
+
+		port, err := strconv.Atoi(line[idx+1:])
+		if err != nil || seen[port] {
+			continue
+		}
+		seen[port] = true
+		ports = append(ports, port)
+	}
+
+	sort.Ints(ports)
+	return ports, nil
+}
+
 // getApplicationInfo gets detailed info for an application
 func (pd *ProcessDetector) getApplicationInfo(appName string) (types.ApplicationInfo, error) {
 	var info types.ApplicationInfo
@@ -192,31 +323,59 @@ func (pd *ProcessDetector) getApplicationInfo(appName string) (types.Application
 
 	info.Name = appName
 	info.BundleID = strings.TrimSpace(string(output))
-	info.ExecutablePath = fmt.Sprintf("/Applications/%s.app", appName)
+	info.ExecutablePath = ResolveBundlePath(info.BundleID, appName)
+	if info.ExecutablePath == "" {
+		info.ExecutablePath = fmt.Sprintf("/Applications/%s.app", appName)
+	}
 
 	// Get window information
-	windows, err := pd.getWindowInfo(appName)
-	if err == nil {
-		info.Windows = windows
+	if !pd.basicMode {
+		windows, err := pd.getWindowInfo(appName)
+		if err == nil {
+			info.Windows = windows
+		}
 	}
 
 	return info, nil
 }
 
+// getBundleID returns the bundle identifier of a running process
+func (pd *ProcessDetector) getBundleID(pid int) (string, error) {
+	script := fmt.Sprintf(`
+        tell application "System Events"
+            return bundle identifier of first application process whose unix id is %d
+        end tell
+    `, pid)
+
+	output, err := exec.Command("osascript", "-e", script).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // getWindowInfo gets window positions for an application
 func (pd *ProcessDetector) getWindowInfo(appName string) ([]types.WindowInfo, error) {
+	// Without Screen Recording permission titles come back empty - keep geometry only
+	titleExpr := `""`
+	if pd.canCaptureTitles() {
+		titleExpr = "name of w"
+	}
+
+	// One "title|x|y|width|height" line per window
 	script := fmt.Sprintf(`
         tell application "System Events"
             tell process "%s"
-                set windowList to {}
+                set output to ""
                 repeat with w in windows
-                    set windowInfo to {name of w, position of w, size of w}
-                    set end of windowList to windowInfo
+                    set {x, y} to position of w
+                    set {width, height} to size of w
+                    set output to output & (%s) & "|" & x & "|" & y & "|" & width & "|" & height & linefeed
                 end repeat
-                return windowList
+                return output
             end tell
         end tell
-    `, appName)
+    `, appName, titleExpr)
 
 	cmd := exec.Command("osascript", "-e", script)
 	output, err := cmd.Output()
@@ -224,21 +383,51 @@ func (pd *ProcessDetector) getWindowInfo(appName string) ([]types.WindowInfo, er
 		return nil, err
 	}
 
-	// Parse window data (simplified)
-	// TODO: Proper parsing of AppleScript output
-	outputStr := strings.TrimSpace(string(output))
 	var windows []types.WindowInfo
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		// Split from the right so titles containing "|" survive
+		fields := strings.Split(line, "|")
+		if len(fields) < 5 {
+			continue
+		}
+		n := len(fields)
 
-	// Example simple parsing: Split by app-specific delimiters (e.g., assume output like "window1:{x,y},size{w,h}; ...")
-	// For now, return empty if not parsable-expand as needed
-	if !strings.Contains(outputStr, "no windows") { // Basic check like getWindowState
-		// Placeholder: Add real split/logic here, e.g., strings.Split(outputStr, ";")
-		// windows = append(windows, types.WindowInfo{Title: "Example", ...})  // Stub for testing
+		var geometry [4]int
+		valid := true
+		for i := 0; i < 4; i++ {
+			value, err := strconv.Atoi(strings.TrimSpace(fields[n-4+i]))
+			if err != nil {
+				valid = false
+				break
+			}
+			geometry[i] = value
+		}
+		if !valid {
+			continue
+		}
+
+		windows = append(windows, types.WindowInfo{
+			Title:    strings.Join(fields[:n-4], "|"),
+			Position: types.Position{X: geometry[0], Y: geometry[1]},
+			Size:     types.Size{Width: geometry[2], Height: geometry[3]},
+		})
 	}
 
 	return windows, nil
 }
 
+// canCaptureTitles reports whether window titles can be read, logging once when they can't
+func (pd *ProcessDetector) canCaptureTitles() bool {
+	if !pd.titlesChecked {
+		pd.canReadTitles = system.HasScreenRecordingPermission()
+		pd.titlesChecked = true
+		if !pd.canReadTitles {
+			system.Warn("Screen Recording permission not granted - window titles will not be captured")
+		}
+	}
+	return pd.canReadTitles
+}
+
 // isSystemApp checks if app should be excluded
 func isSystemApp(appName string) bool {
 	systemApps := []string{
@@ -0,0 +1,153 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+)
+
+// simctlDevices is the part of `xcrun simctl list devices -j` we need
+type simctlDevices struct {
+	Devices map[string][]struct {
+		UDID  string `json:"udid"`
+		Name  string `json:"name"`
+		State string `json:"state"`
+	} `json:"devices"`
+}
+
+// DetectDeveloperState records open Xcode projects/workspaces and booted simulators
+func DetectDeveloperState() *types.DeveloperState {
+	state := &types.DeveloperState{}
+
+	if isAppRunning("Xcode") {
+		documents, err := detectXcodeDocuments()
+		if err != nil {
+			system.Debug("Could not read open Xcode documents:", err)
+		}
+		state.XcodeDocuments = documents
+	}
+
+	simulators, err := detectBootedSimulators()
+	if err != nil {
+		system.Debug("Could not list booted simulators:", err)
+	}
+	state.Simulators = simulators
+
+	projects, containers, err := DetectDockerState()
+	if err != nil {
+		system.Debug("Could not read Docker state:", err)
+	}
+	state.ComposeProjects = projects
+	state.Containers = containers
+
+	if len(state.XcodeDocuments) == 0 && len(state.Simulators) == 0 &&
+		len(state.ComposeProjects) == 0 && len(state.Containers) == 0 {
+		return nil
+	}
+	return state
+}
+
+// detectXcodeDocuments returns paths of the .xcodeproj/.xcworkspace documents open in Xcode
+func detectXcodeDocuments() ([]string, error) {
+	script := `
+        tell application "Xcode"
+            set output to ""
+            repeat with doc in workspace documents
+                set output to output & (path of doc) & linefeed
+            end repeat
+            return output
+        end tell
+    `
+
+	output, err := exec.Command("osascript", "-e", script).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			documents = append(documents, line)
+		}
+	}
+	return documents, nil
+}
+
+// detectBootedSimulators lists booted iOS/watchOS/tvOS simulators
+func detectBootedSimulators() ([]types.SimulatorDevice, error) {
+	if _, err := exec.LookPath("xcrun"); err != nil {
+		return nil, nil // No developer tools installed
+	}
+
+	output, err := exec.Command("xcrun", "simctl", "list", "devices", "booted", "-j").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run simctl: %w", err)
+	}
+
+	var list simctlDevices
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse simctl output: %w", err)
+	}
+
+	var simulators []types.SimulatorDevice
+	for runtime, devices := range list.Devices {
+		for _, device := range devices {
+			if device.State != "Booted" {
+				continue
+			}
+			simulators = append(simulators, types.SimulatorDevice{
+				UDID:    device.UDID,
+				Name:    device.Name,
+				Runtime: runtime,
+			})
+		}
+	}
+	return simulators, nil
+}
+
+// RestoreDeveloperState reopens Xcode documents and boots the recorded simulators.
+// Docker projects are restored separately by RestoreComposeProjects since they are opt-in.
+func RestoreDeveloperState(state *types.DeveloperState) error {
+	if state == nil {
+		return nil
+	}
+
+	var failed []string
+
+	for _, document := range state.XcodeDocuments {
+		if _, err := os.Stat(document); err != nil {
+			system.Warn("Xcode document no longer exists:", document)
+			continue
+		}
+		if err := exec.Command("open", "-a", "Xcode", document).Run(); err != nil {
+			system.Warn("Failed to open", document, ":", err)
+			failed = append(failed, document)
+			continue
+		}
+		system.Info("Reopened Xcode document:", document)
+	}
+
+	for _, simulator := range state.Simulators {
+		// Booting an already booted device fails harmlessly
+		if err := exec.Command("xcrun", "simctl", "boot", simulator.UDID).Run(); err != nil {
+			system.Debug("simctl boot", simulator.Name, ":", err)
+		}
+		system.Info("Booted simulator:", simulator.Name)
+	}
+
+	if len(state.Simulators) > 0 {
+		if err := exec.Command("open", "-a", "Simulator").Run(); err != nil {
+			system.Warn("Failed to open Simulator:", err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to reopen %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"RESPAWN/internal/types"
+)
+
+// LayoutStrategy controls how much window geometry is restored
+type LayoutStrategy int
+
+const (
+	// LayoutExact restores recorded window state as captured
+	LayoutExact LayoutStrategy = iota
+	// LayoutDegraded skips geometry-dependent restoration when the displays changed
+	LayoutDegraded
+)
+
+// displayScript lists NSScreen frames via JavaScript for Automation
+const displayScript = `
+ObjC.import('AppKit');
+var screens = $.NSScreen.screens;
+var result = [];
+for (var i = 0; i < screens.count; i++) {
+    var screen = screens.objectAtIndex(i);
+    var frame = screen.frame;
+    result.push({
+        name: ObjC.unwrap(screen.localizedName),
+        position: {x: frame.origin.x, y: frame.origin.y},
+        size: {width: frame.size.width, height: frame.size.height},
+        is_main: i == 0
+    });
+}
+JSON.stringify(result);
+`
+
+// DetectDisplays returns the connected displays and their arrangement
+func DetectDisplays() ([]types.DisplayInfo, error) {
+	cmd := exec.Command("osascript", "-l", "JavaScript", "-e", displayScript)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query displays: %w", err)
+	}
+
+	var displays []types.DisplayInfo
+	if err := json.Unmarshal(output, &displays); err != nil {
+		return nil, fmt.Errorf("failed to parse display info: %w", err)
+	}
+
+	return displays, nil
+}
+
+// CompareDisplays checks whether the current displays match those recorded in a checkpoint.
+// It returns the layout strategy to use and a user-facing warning when they differ.
+func CompareDisplays(recorded, current []types.DisplayInfo) (LayoutStrategy, string) {
+	if len(recorded) == 0 || len(current) == 0 {
+		return LayoutExact, "" // Nothing to compare against
+	}
+
+	if len(recorded) != len(current) {
+		return LayoutDegraded, fmt.Sprintf(
+			"This checkpoint was taken with %d display(s), %d connected now; window placement may differ",
+			len(recorded), len(current),
+		)
+	}
+
+	for i := range recorded {
+		if recorded[i].Size != current[i].Size || recorded[i].Position != current[i].Position {
+			return LayoutDegraded, fmt.Sprintf(
+				"Display arrangement changed since this checkpoint (%s was %dx%d); window placement may differ",
+				recorded[i].Name, recorded[i].Size.Width, recorded[i].Size.Height,
+			)
+		}
+	}
+
+	return LayoutExact, ""
+}
@@ -0,0 +1,117 @@
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+)
+
+// dockerReadyTimeout is how long restore waits for the Docker daemon after login
+const dockerReadyTimeout = 90 * time.Second
+
+// dockerPsFormat extracts the compose labels docker sets on every project container
+const dockerPsFormat = `{{.Label "com.docker.compose.project"}}|{{.Label "com.docker.compose.project.working_dir"}}|{{.Label "com.docker.compose.project.config_files"}}|{{.Names}}`
+
+// DetectDockerState records running compose projects and standalone containers
+func DetectDockerState() ([]types.ComposeProject, []string, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, nil, nil // Docker not installed
+	}
+
+	output, err := exec.Command("docker", "ps", "--format", dockerPsFormat).Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	projects := make(map[string]types.ComposeProject)
+	var containers []string
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "|")
+		if len(fields) != 4 {
+			continue
+		}
+
+		if fields[0] == "" {
+			containers = append(containers, fields[3])
+			continue
+		}
+
+		if _, seen := projects[fields[0]]; !seen {
+			project := types.ComposeProject{
+				Name:       fields[0],
+				WorkingDir: fields[1],
+			}
+			if fields[2] != "" {
+				project.ConfigFiles = strings.Split(fields[2], ",")
+			}
+			projects[fields[0]] = project
+		}
+	}
+
+	var result []types.ComposeProject
+	for _, project := range projects {
+		result = append(result, project)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, containers, nil
+}
+
+// RestoreComposeProjects runs `docker compose up -d` for each recorded project.
+// It waits for the Docker daemon first, since Docker Desktop starts slowly after login.
+func RestoreComposeProjects(projects []types.ComposeProject) (started, failed []string) {
+	if len(projects) == 0 {
+		return nil, nil
+	}
+
+	if !waitForDocker(dockerReadyTimeout) {
+		system.Warn("Docker daemon not ready - skipping compose projects")
+		for _, project := range projects {
+			failed = append(failed, project.Name)
+		}
+		return nil, failed
+	}
+
+	for _, project := range projects {
+		args := []string{"compose", "-p", project.Name}
+		for _, file := range project.ConfigFiles {
+			args = append(args, "-f", file)
+		}
+		args = append(args, "up", "-d")
+
+		cmd := exec.Command("docker", args...)
+		cmd.Dir = project.WorkingDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			system.Warn("Failed to start compose project", project.Name, ":", err, strings.TrimSpace(string(output)))
+			failed = append(failed, project.Name)
+			continue
+		}
+
+		system.Info("Started compose project", project.Name)
+		started = append(started, project.Name)
+	}
+
+	return started, failed
+}
+
+// waitForDocker polls `docker info` until the daemon answers or the timeout expires
+func waitForDocker(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if exec.Command("docker", "info").Run() == nil {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(3 * time.Second)
+	}
+}
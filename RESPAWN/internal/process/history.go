@@ -0,0 +1,217 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"RESPAWN/internal/types"
+	"RESPAWN/pkg/config"
+)
+
+// restoreHistoryFileName holds per-app restore outcomes in the data directory
+const restoreHistoryFileName = "restore_history.json"
+
+// blacklistAfterTimeouts is how many restores in a row an app may time out in
+// before it is blacklisted for config.BlacklistDuration
+const blacklistAfterTimeouts = 2
+
+// AppRestoreRecord is the restore track record of one app
+type AppRestoreRecord struct {
+	Successes           int       `json:"successes"`
+	Failures            int       `json:"failures"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastFailure         time.Time `json:"last_failure,omitempty"`
+	ManualOnly          bool      `json:"manual_only,omitempty"` // Skipped by restores until it launches again
+	ManualSince         time.Time `json:"manual_since,omitempty"`
+	ConsecutiveTimeouts int       `json:"consecutive_timeouts,omitempty"`
+	BlacklistedUntil    time.Time `json:"blacklisted_until,omitempty"` // Skipped by restores until then
+}
+
+// IsBlacklisted reports whether the app is on the blacklist at now
+func (r AppRestoreRecord) IsBlacklisted(now time.Time) bool {
+	return now.Before(r.BlacklistedUntil)
+}
+
+// SuccessRate is the share of restore attempts that launched the app
+func (r AppRestoreRecord) SuccessRate() float64 {
+	attempts := r.Successes + r.Failures
+	if attempts == 0 {
+		return 1
+	}
+	return float64(r.Successes) / float64(attempts)
+}
+
+// RestoreHistory tracks how often each app launched during restores. Apps that
+// fail config.ManualRestoreAfter restores in a row are marked manual restore only.
+type RestoreHistory struct {
+	Apps map[string]*AppRestoreRecord `json:"apps"`
+	path string
+}
+
+// LoadRestoreHistory reads the restore history, returning an empty one if none was saved yet
+func LoadRestoreHistory() (*RestoreHistory, error) {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+
+	history := &RestoreHistory{
+		Apps: make(map[string]*AppRestoreRecord),
+		path: filepath.Join(cfg.DataDir, restoreHistoryFileName),
+	}
+	data, err := os.ReadFile(history.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, err
+	}
+	if history.Apps == nil {
+		history.Apps = make(map[string]*AppRestoreRecord)
+	}
+	return history, nil
+}
+
+// Save writes the restore history
+func (h *RestoreHistory) Save() error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, data, 0644)
+}
+
+// Record adds the outcome of each launched app and returns the apps that just
+// became manual restore only and those just blacklisted for timing out. Skipped
+// apps don't count either way.
+func (h *RestoreHistory) Record(results []types.LaunchResult) (marked, blacklisted []string) {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+
+	for _, result := range results {
+		if !result.Success && !launchFailed(result) {
+			continue
+		}
+
+		record := h.record(result.AppName)
+		if result.Success {
+			record.Successes++
+			record.ConsecutiveFailures = 0
+			record.ConsecutiveTimeouts = 0
+			record.ManualOnly = false
+			record.ManualSince = time.Time{}
+			continue
+		}
+
+		record.Failures++
+		record.ConsecutiveFailures++
+		record.LastError = result.ErrorMsg
+		record.LastFailure = result.LaunchTime
+
+		// A hanging app stalls every restore it is in, take it out for a while
+		if result.TimedOut {
+			record.ConsecutiveTimeouts++
+			if cfg.BlacklistDuration > 0 && record.ConsecutiveTimeouts >= blacklistAfterTimeouts {
+				record.ConsecutiveTimeouts = 0
+				record.BlacklistedUntil = time.Now().Add(cfg.BlacklistDuration)
+				blacklisted = append(blacklisted, result.AppName)
+			}
+		} else {
+			record.ConsecutiveTimeouts = 0
+		}
+		if !record.ManualOnly && cfg.ManualRestoreAfter > 0 && record.ConsecutiveFailures >= cfg.ManualRestoreAfter {
+			record.ManualOnly = true
+			record.ManualSince = time.Now()
+			marked = append(marked, result.AppName)
+		}
+	}
+	return marked, blacklisted
+}
+
+// record returns the record for appName, creating it if needed
+func (h *RestoreHistory) record(appName string) *AppRestoreRecord {
+	record, ok := h.Apps[appName]
+	if !ok {
+		record = &AppRestoreRecord{}
+		h.Apps[appName] = record
+	}
+	return record
+}
+
+// IsManualOnly reports whether restores should leave appName to the user
+func (h *RestoreHistory) IsManualOnly(appName string) bool {
+	record, ok := h.Apps[appName]
+	return ok && record.ManualOnly
+}
+
+// ManualOnlyApps returns the apps marked manual restore only, sorted by name
+func (h *RestoreHistory) ManualOnlyApps() []string {
+	var apps []string
+	for name, record := range h.Apps {
+		if record.ManualOnly {
+			apps = append(apps, name)
+		}
+	}
+	sort.Strings(apps)
+	return apps
+}
+
+// IsBlacklisted reports whether restores should skip appName for now
+func (h *RestoreHistory) IsBlacklisted(appName string) bool {
+	record, ok := h.Apps[appName]
+	return ok && record.IsBlacklisted(time.Now())
+}
+
+// Blacklist returns the apps on the blacklist and when each comes off it
+func (h *RestoreHistory) Blacklist() map[string]time.Time {
+	now := time.Now()
+	blacklist := make(map[string]time.Time)
+	for name, record := range h.Apps {
+		if record.IsBlacklisted(now) {
+			blacklist[name] = record.BlacklistedUntil
+		}
+	}
+	return blacklist
+}
+
+// ClearBlacklist takes appName, or every app when appName is empty, off the
+// blacklist and returns how many apps were cleared
+func (h *RestoreHistory) ClearBlacklist(appName string) int {
+	now := time.Now()
+	cleared := 0
+	for name, record := range h.Apps {
+		if appName != "" && !strings.EqualFold(name, appName) {
+			continue
+		}
+		if record.IsBlacklisted(now) {
+			cleared++
+		}
+		record.BlacklistedUntil = time.Time{}
+		record.ConsecutiveTimeouts = 0
+	}
+	return cleared
+}
+
+// SuccessRate is the share of all recorded restore attempts that launched their app
+func (h *RestoreHistory) SuccessRate() float64 {
+	var total AppRestoreRecord
+	for _, record := range h.Apps {
+		total.Successes += record.Successes
+		total.Failures += record.Failures
+	}
+	return total.SuccessRate()
+}
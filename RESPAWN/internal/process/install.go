@@ -0,0 +1,126 @@
+package process
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"RESPAWN/internal/system"
+)
+
+// ErrAppNotInstalled is set on launch results for apps whose bundle can no longer be found
+var ErrAppNotInstalled = errors.New("application not installed")
+
+// installHints suggests where to get apps RESPAWN tracks by default or commonly sees
+var installHints = map[string]string{
+	"Google Chrome":      "brew install --cask google-chrome",
+	"Brave Browser":      "brew install --cask brave-browser",
+	"Firefox":            "brew install --cask firefox",
+	"Claude":             "brew install --cask claude",
+	"Slack":              "brew install --cask slack",
+	"Discord":            "brew install --cask discord",
+	"Microsoft Teams":    "brew install --cask microsoft-teams",
+	"Visual Studio Code": "brew install --cask visual-studio-code",
+	"Docker":             "brew install --cask docker",
+	"Spotify":            "brew install --cask spotify",
+	"Xcode":              "App Store: https://apps.apple.com/app/xcode/id497799835",
+}
+
+// applicationDirs are checked before falling back to a Launch Services/Spotlight lookup
+var applicationDirs = []string{
+	"/Applications",
+	"/System/Applications",
+	"/System/Applications/Utilities",
+	"/Applications/Utilities",
+}
+
+// IsApplicationInstalled reports whether an application bundle can be found.
+// When the lookup itself fails the app is assumed installed so restore still tries it.
+func IsApplicationInstalled(appName string) bool {
+	if path := findBundleInDirs(appName); path != "" {
+		return true
+	}
+
+	query := "kMDItemContentType == 'com.apple.application-bundle' && kMDItemFSName == '" +
+		escapeQuery(appName+".app") + "'"
+	path, err := spotlightFirst(query)
+	if err != nil {
+		system.Debug("Launch Services lookup failed for", appName, ":", err)
+		return true
+	}
+
+	return path != ""
+}
+
+// IsBundleInstalled reports whether an application with a bundle identifier can be
+// found. Like IsApplicationInstalled, a failed lookup counts as installed.
+func IsBundleInstalled(bundleID string) bool {
+	path, err := spotlightFirst("kMDItemCFBundleIdentifier == '" + escapeQuery(bundleID) + "'")
+	if err != nil {
+		system.Debug("Bundle lookup failed for", bundleID, ":", err)
+		return true
+	}
+	return path != ""
+}
+
+// ResolveBundlePath finds where an application bundle actually lives, so apps in
+// ~/Applications, /Applications/Utilities or Homebrew cask locations restore correctly.
+// It returns "" when the bundle can't be found.
+func ResolveBundlePath(bundleID, appName string) string {
+	if bundleID != "" {
+		path, err := spotlightFirst("kMDItemCFBundleIdentifier == '" + escapeQuery(bundleID) + "'")
+		if err != nil {
+			system.Debug("Bundle lookup failed for", bundleID, ":", err)
+		}
+		if path != "" {
+			return path
+		}
+	}
+
+	return findBundleInDirs(appName)
+}
+
+// findBundleInDirs looks for <appName>.app in the usual application folders
+func findBundleInDirs(appName string) string {
+	bundle := appName + ".app"
+
+	dirs := applicationDirs
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(homeDir, "Applications"))
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, bundle)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// spotlightFirst runs an mdfind query and returns the first .app result
+func spotlightFirst(query string) (string, error) {
+	output, err := exec.Command("mdfind", query).Output()
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasSuffix(line, ".app") {
+			return line, nil
+		}
+	}
+	return "", nil
+}
+
+// escapeQuery escapes single quotes for an mdfind query string
+func escapeQuery(value string) string {
+	return strings.ReplaceAll(value, "'", "\\'")
+}
+
+// InstallHint returns a suggestion for reinstalling an app, or "" when none is known
+func InstallHint(appName string) string {
+	return installHints[appName]
+}
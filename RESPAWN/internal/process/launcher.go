@@ -1,7 +1,10 @@
 package process
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"time"
 
@@ -16,6 +19,11 @@ import (
 type ApplicationLauncher struct {
 	detector *ProcessDetector
 	results  []types.LaunchResult
+	layout   LayoutStrategy
+	progress ProgressFunc
+
+	includeManualOnly bool // Also launch apps marked manual restore only
+	queue             *RestoreQueue // Persisted as the restore proceeds, when set
 }
 
 // NewApplicationLauncher creates a new application launcher
@@ -23,6 +31,59 @@ func NewApplicationLauncher()  *ApplicationLauncher {
 	return &ApplicationLauncher{
 		detector: NewProcessDetector(),
 		results: make([]types.LaunchResult, 0),
+		layout:   LayoutExact,
+	}
+}
+
+// SetLayoutStrategy sets how much window geometry is restored
+func (al *ApplicationLauncher) SetLayoutStrategy(strategy LayoutStrategy) {
+	al.layout = strategy
+}
+
+// SetProgressHandler sets the function told about each app as the restore proceeds
+func (al *ApplicationLauncher) SetProgressHandler(handler ProgressFunc) {
+	al.progress = handler
+}
+
+// SetIncludeManualOnly makes the restore launch apps marked manual restore only
+// instead of skipping them. One that launches loses the mark.
+func (al *ApplicationLauncher) SetIncludeManualOnly(include bool) {
+	al.includeManualOnly = include
+}
+
+// SetQueueSource persists the apps still to launch under source, a checkpoint ID,
+// so a restore interrupted by sleep or a kill can be continued
+func (al *ApplicationLauncher) SetQueueSource(source string) {
+	al.queue = &RestoreQueue{Source: source, StartedAt: time.Now(), PID: os.Getpid()}
+}
+
+// ResumeQueue continues persisting an interrupted restore's queue, now owned by this process
+func (al *ApplicationLauncher) ResumeQueue(queue *RestoreQueue) {
+	queue.PID = os.Getpid()
+	al.queue = queue
+}
+
+// saveQueue records that the apps from remaining on are still to be launched
+func (al *ApplicationLauncher) saveQueue(remaining []types.ProcessInfo) {
+	if al.queue == nil {
+		return
+	}
+	al.queue.Remaining = remaining
+	if err := al.queue.save(); err != nil {
+		system.Warn("Failed to save restore queue:", err)
+	}
+}
+
+// report publishes a progress step to the event stream and the progress handler
+func (al *ApplicationLauncher) report(progress RestoreProgress) {
+	system.Publish(system.EventRestoreProgress, map[string]interface{}{
+		"app":    progress.App,
+		"index":  progress.Index,
+		"total":  progress.Total,
+		"status": progress.Status,
+	})
+	if al.progress != nil {
+		al.progress(progress)
 	}
 }
 
@@ -33,26 +94,161 @@ func (al *ApplicationLauncher) RestoreApplications(processes []types.ProcessInfo
 	// Sort by memory usage (highest first)
 	sortedProcesses := SortByMemoryUsage(processes)
 
-	for _, proc := range sortedProcesses {
-		// Check if app is already running
-		if al.isApplicationRunning(proc.ProcessName) {
-			system.Debug("Skipping", proc.Name, "- already running")
+	history, err := LoadRestoreHistory()
+	if err != nil {
+		system.Warn("Failed to load restore history:", err)
+	}
+	afterBoot := loginItemsPending()
+
+	for i, proc := range sortedProcesses {
+		progress := RestoreProgress{App: proc.Name, Index: i + 1, Total: len(sortedProcesses)}
+		if al.queue != nil && i > 0 {
+			al.queue.Launched = append(al.queue.Launched, sortedProcesses[i-1].Name)
+		}
+		al.saveQueue(sortedProcesses[i:])
+
+		// Check if app is already running. Right after boot that's macOS reopening
+		// windows from the last session, or opening a login item.
+		if pid, running := al.runningPID(proc); running {
+			result := types.LaunchResult{
+				AppName:        proc.Name,
+				PID:            pid,
+				LaunchTime:     time.Now(),
+				AlreadyRunning: true,
+				ListeningPorts: proc.ListeningPorts,
+			}
+			progress.Status = ProgressAlreadyRunning
+			switch {
+			case afterBoot && proc.LoginItem:
+				result.LoginItem = true
+				progress.Status = ProgressLoginItem
+			case afterBoot:
+				result.ReopenedByMacOS = true
+				progress.Status = ProgressReopenedByMacOS
+			}
+			system.Debug("Skipping", proc.Name, "-", progress.Status)
+			al.results = append(al.results, result)
+			progress.Result = &result
+			al.report(progress)
 			continue
 		}
 
+		// Right after boot macOS is about to open login items itself; launching
+		// them too races it into a second instance
+		if proc.LoginItem && afterBoot {
+			system.Debug("Waiting for login item", proc.Name)
+			if pid, ok := al.waitForProcess(proc.ProcessName, loginItemWait); ok {
+				result := types.LaunchResult{
+					AppName:        proc.Name,
+					PID:            pid,
+					LaunchTime:     time.Now(),
+					AlreadyRunning: true,
+					LoginItem:      true,
+					ListeningPorts: proc.ListeningPorts,
+				}
+				al.results = append(al.results, result)
+				progress.Status = ProgressLoginItem
+				progress.Result = &result
+				al.report(progress)
+				continue
+			}
+			system.Info("Login item", proc.Name, "didn't open by itself - launching it")
+		}
+
+		// Retrying an app that is gone only slows the restore down
+		if !al.isInstalled(proc) {
+			system.Warn("Skipping", proc.Name, "- not installed")
+			result := types.LaunchResult{
+				AppName:      proc.Name,
+				LaunchTime:   time.Now(),
+				ErrorMsg:     "not installed",
+				NotInstalled: true,
+				InstallHint:  InstallHint(proc.Name),
+				Err:          fmt.Errorf("%w: %s", ErrAppNotInstalled, proc.Name),
+			}
+			al.results = append(al.results, result)
+			progress.Status = ProgressNotInstalled
+			progress.Result = &result
+			al.report(progress)
+			continue
+		}
+
+		// Apps that kept hanging sit out until their blacklisting expires
+		if history != nil && history.IsBlacklisted(proc.Name) {
+			system.Info("Skipping", proc.Name, "- blacklisted")
+			result := types.LaunchResult{
+				AppName:     proc.Name,
+				LaunchTime:  time.Now(),
+				ErrorMsg:    "blacklisted after repeated launch timeouts",
+				Blacklisted: true,
+			}
+			al.results = append(al.results, result)
+			progress.Status = ProgressBlacklisted
+			progress.Result = &result
+			al.report(progress)
+			continue
+		}
+
+		// Apps that kept failing are left for the user to open
+		if history != nil && !al.includeManualOnly && history.IsManualOnly(proc.Name) {
+			system.Info("Skipping", proc.Name, "- manual restore only")
+			result := types.LaunchResult{
+				AppName:    proc.Name,
+				LaunchTime: time.Now(),
+				ErrorMsg:   "manual restore only",
+				ManualOnly: true,
+			}
+			al.results = append(al.results, result)
+			progress.Status = ProgressManualOnly
+			progress.Result = &result
+			al.report(progress)
+			continue
+		}
+
+		progress.Status = ProgressLaunching
+		al.report(progress)
+
 		// Launch application with retry logic
+		started := time.Now()
 		result := al.launchWithRetry(proc)
+		result.Duration = time.Since(started)
+		result.ListeningPorts = proc.ListeningPorts
 		al.results = append(al.results, result)
 
+		progress.Status = ProgressFailed
+		if result.Success {
+			progress.Status = ProgressLaunched
+		}
+		progress.Result = &result
+		al.report(progress)
+
 		if result.Success {
+			// Reopen windows for the remaining browser profiles
+			al.restoreBrowserProfiles(proc)
+
 			// Restore window state immediately after successful launch
 			al.restoreWindowState(proc, result.PID)
 
-			// Show success notification
-			al.showSuccessNotification(proc.Name)
+			// Bring chat apps back to the workspace/channel they had focused
+			if proc.Chat != nil {
+				if err := RestoreChatContext(proc.Name, proc.Chat, !system.IsDegradedMode()); err != nil {
+					system.Warn(err)
+				}
+			}
+
+			system.Info("Application restored:", proc.Name)
 
 			// Wait a bit before launching the next app to avoid overload
-			time.Sleep(time.Duration(config.GlobalConfig.LaunchDelayMs) * time.Millisecond)
+			time.Sleep(time.Duration(config.Current().LaunchDelayMs) * time.Millisecond)
+		}
+	}
+
+	if history != nil {
+		al.recordHistory(history)
+	}
+	if al.queue != nil {
+		if err := clearRestoreQueue(); err != nil {
+			system.Warn("Failed to remove restore queue:", err)
 		}
 	}
 
@@ -60,9 +256,34 @@ func (al *ApplicationLauncher) RestoreApplications(processes []types.ProcessInfo
 	return al.results, nil
 }
 
+// recordHistory adds this restore's outcomes to the restore history and flags the
+// results of apps that just became manual restore only or were blacklisted
+func (al *ApplicationLauncher) recordHistory(history *RestoreHistory) {
+	marked, blacklisted := history.Record(al.results)
+	for _, appName := range marked {
+		system.Warn(appName, "failed to restore repeatedly - now manual restore only")
+		for i := range al.results {
+			if al.results[i].AppName == appName {
+				al.results[i].MarkedManualOnly = true
+			}
+		}
+	}
+	for _, appName := range blacklisted {
+		system.Warn(appName, "timed out repeatedly - blacklisted from restores")
+		for i := range al.results {
+			if al.results[i].AppName == appName {
+				al.results[i].MarkedBlacklisted = true
+			}
+		}
+	}
+	if err := history.Save(); err != nil {
+		system.Warn("Failed to save restore history:", err)
+	}
+}
+
 // launchWithRetry attempts to launch an application with retry logic
 func (al *ApplicationLauncher) launchWithRetry(proc types.ProcessInfo) types.LaunchResult {
-	maxRetries := config.GlobalConfig.MaxRetryAttempts
+	maxRetries := config.Current().MaxRetryAttempts
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		system.Debug("Launching", proc.Name, "- attempt", attempt)
@@ -77,6 +298,11 @@ func (al *ApplicationLauncher) launchWithRetry(proc types.ProcessInfo) types.Lau
 
 		system.Warn("Failed to launch", proc.Name, "on attempt", attempt, ":", result.ErrorMsg)
 
+		// Retrying a hang only stalls the rest of the restore longer
+		if result.TimedOut {
+			return result
+		}
+
 		if attempt < maxRetries {
 			time.Sleep(1 * time.Second) // Wait before retrying
 		} 
@@ -97,8 +323,16 @@ func (al *ApplicationLauncher) launchWithRetry(proc types.ProcessInfo) types.Lau
 func (al *ApplicationLauncher) launchApplication(proc types.ProcessInfo) types.LaunchResult {
 	startTime  := time.Now()
 
-	// Use 'open -a' command for fast, reliable launching
-	cmd := exec.Command("open", "-a", proc.ProcessName)
+	// Use 'open' for fast, reliable launching, preferring the recorded bundle location.
+	// Browsers start in their first recorded profile, the rest open after launch.
+	args := al.openArgs(proc)
+	if len(proc.Profiles) > 0 {
+		args = append(args, "--args")
+		args = append(args, profileArgs(proc.Name, proc.Profiles[0])...)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), launchTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "open", args...)
 
 	err := cmd.Start()
 	if err != nil {
@@ -111,6 +345,15 @@ func (al *ApplicationLauncher) launchApplication(proc types.ProcessInfo) types.L
 	}
 	// Wait for the command to complete
 	err = cmd.Wait()
+	if ctx.Err() == context.DeadlineExceeded {
+		return types.LaunchResult{
+			AppName: proc.Name,
+			Success: false,
+			LaunchTime: startTime,
+			ErrorMsg: fmt.Sprintf("Launch timed out after %v", launchTimeout),
+			TimedOut: true,
+		}
+	}
 	if err != nil {
 		return types.LaunchResult{
 			AppName: proc.Name,
@@ -119,17 +362,14 @@ func (al *ApplicationLauncher) launchApplication(proc types.ProcessInfo) types.L
 			ErrorMsg: fmt.Sprintf("Process execution failed: %v", err),
 		}
 	}
-	// Wait a moment for the process to fully initialize
-	time.Sleep(500 * time.Millisecond)
-
 	// Verify the application actually started
-	pid, isRunning := al.verifyApplicationLaunched(proc.ProcessName)
+	pid, isRunning := al.waitForLaunch(proc.ProcessName)
 	if !isRunning {
 		return types.LaunchResult{
 			AppName: proc.Name,
 			Success: false,
 			LaunchTime: startTime,
-			ErrorMsg: "Process Not Found After Launch",
+			ErrorMsg: "Process Not Found After Launch", // Not a hang, a slow start deserves its retries
 		}
 	}
 
@@ -142,6 +382,52 @@ func (al *ApplicationLauncher) launchApplication(proc types.ProcessInfo) types.L
 	}
 }
 
+// restoreBrowserProfiles opens the profiles after the first, which launch already used
+func (al *ApplicationLauncher) restoreBrowserProfiles(proc types.ProcessInfo) {
+	if len(proc.Profiles) < 2 {
+		return
+	}
+
+	for _, profile := range proc.Profiles[1:] {
+		if err := openProfile(al.openArgs(proc), proc.Name, profile); err != nil {
+			system.Warn(err)
+		}
+	}
+}
+
+// openArgs picks the most precise way to tell 'open' which app to launch
+func (al *ApplicationLauncher) openArgs(proc types.ProcessInfo) []string {
+	if proc.BundlePath != "" {
+		if _, err := os.Stat(proc.BundlePath); err == nil {
+			return []string{proc.BundlePath}
+		}
+	}
+	if proc.BundleID != "" {
+		if path := ResolveBundlePath(proc.BundleID, proc.Name); path != "" {
+			return []string{path}
+		}
+	}
+	return []string{"-a", proc.ProcessName}
+}
+
+// isInstalled checks the bundle recorded in the checkpoint, searching by name only
+// when none was recorded. Process names often differ from bundle names ("Code" for
+// Visual Studio Code), so the app name is tried first.
+func (al *ApplicationLauncher) isInstalled(proc types.ProcessInfo) bool {
+	if proc.BundlePath != "" {
+		if _, err := os.Stat(proc.BundlePath); err == nil {
+			return true
+		}
+	}
+	if proc.BundleID != "" {
+		return IsBundleInstalled(proc.BundleID)
+	}
+	if proc.Name != "" && IsApplicationInstalled(proc.Name) {
+		return true
+	}
+	return proc.ProcessName != proc.Name && IsApplicationInstalled(proc.ProcessName)
+}
+
 // verifyApplicationLaunched checks if the application is actuallyy running
 func (al *ApplicationLauncher) verifyApplicationLaunched(processName string) (int, bool) {
 	cmd := exec.Command("pgrep", "-f", processName)
@@ -163,6 +449,28 @@ func (al *ApplicationLauncher) verifyApplicationLaunched(processName string) (in
 	return 0, false
 }
 
+// launchTimeout is how long 'open' may take before the launch counts as hung
+const launchTimeout = 30 * time.Second
+
+// launchVerifyTimeout is how long a launched app gets to show up in the process list
+const launchVerifyTimeout = 5 * time.Second
+
+// waitForLaunch polls until the process appears or launchVerifyTimeout passes
+func (al *ApplicationLauncher) waitForLaunch(processName string) (int, bool) {
+	return al.waitForProcess(processName, launchVerifyTimeout)
+}
+
+// waitForProcess polls until the process appears or timeout passes
+func (al *ApplicationLauncher) waitForProcess(processName string, timeout time.Duration) (int, bool) {
+	deadline := time.Now().Add(timeout)
+	for {
+		if pid, ok := al.verifyApplicationLaunched(processName); ok || time.Now().After(deadline) {
+			return pid, ok
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
 // isApplicationRunning checks if an application is currently running
 func (al *ApplicationLauncher) isApplicationRunning(processName string) bool {
 	_, isRunning := al.verifyApplicationLaunched(processName)
@@ -180,24 +488,29 @@ func (al *ApplicationLauncher) restoreWindowState(proc types.ProcessInfo, pid in
 	case "minimized":
 		script = fmt.Sprintf(`
             tell application "System Events"
-                tell application process "%s"
+                tell application process %s
                     if exists window 1 then
                         set minimized of window 1 to true
                     end if
                 end tell
             end tell
-        `, proc.ProcessName)
+        `, system.AppleScriptString(proc.ProcessName))
 
 	case "maximized":
+		// Zoom fills whatever display the window lands on, which may be the wrong one
+		if al.layout == LayoutDegraded {
+			system.Debug("Degraded layout - leaving", proc.Name, "at its default size")
+			return
+		}
 		script = fmt.Sprintf(`
             tell application "System Events"
-                tell application process "%s"
+                tell application process %s
                     if exists window 1 then
                         set zoomed of window 1 to true
                     end if
                 end tell
             end tell
-        `, proc.ProcessName)
+        `, system.AppleScriptString(proc.ProcessName))
 
 	case "normal":
 		// For normal windows, we do not need to do anything special
@@ -207,9 +520,10 @@ func (al *ApplicationLauncher) restoreWindowState(proc types.ProcessInfo, pid in
 	}
 
 	if script != "" {
-		cmd := exec.Command("osascript", "-e", script)
-		err := cmd.Run()
-		if err != nil {
+		output, err := exec.Command("osascript", "-e", script).CombinedOutput()
+		if err = system.AppleScriptError(output, err); errors.Is(err, system.ErrPermissionMissing) {
+			system.Debug("Not restoring window state for", proc.Name, ":", err)
+		} else if err != nil {
 			system.Warn("Failed to restore window state for", proc.Name, ":", err)
 		} else {
 			system.Debug("Successfully restored window state for", proc.Name)
@@ -217,23 +531,11 @@ func (al *ApplicationLauncher) restoreWindowState(proc types.ProcessInfo, pid in
 	}
 }
 
-// showSuccessNotification displays the success indicator
-func (al *ApplicationLauncher) showSuccessNotification(appName string) {
-	// Print to stdout so user sees it immediately
-	fmt.Printf("%s ✅\n", appName)
-
-	// Log the success
-	system.Info("Application resrored:", appName)
-
-	// Waits for 2 seconds 
-	time.Sleep(2 * time.Second)
-}
-
 // GetFailedApplications returns applications that failed to launch
 func (al *ApplicationLauncher) GetFailedApplications() []types.LaunchResult {
 	var failed []types.LaunchResult
 	for _, result := range al.results {
-		if !result.Success {
+		if launchFailed(result) {
 			failed = append(failed, result)
 		}
 	}
@@ -251,6 +553,17 @@ func (al *ApplicationLauncher) GetSuccessfulApplications() []types.LaunchResult
 	return successful
 }
 
+// GetNotInstalledApplications returns applications skipped because they are no longer installed
+func (al *ApplicationLauncher) GetNotInstalledApplications() []types.LaunchResult {
+	var missing []types.LaunchResult
+	for _, result := range al.results {
+		if result.NotInstalled {
+			missing = append(missing, result)
+		}
+	}
+	return missing
+}
+
 // GetLaunchSummary returns a summary of the launch operation
 func (al *ApplicationLauncher) GetLaunchSummary() (int, int, []string) {
 	successful := 0
@@ -260,7 +573,7 @@ func (al *ApplicationLauncher) GetLaunchSummary() (int, int, []string) {
 	for _, result := range al.results {
 		if result.Success {
 			successful++
-		} else {
+		} else if launchFailed(result) {
 			failed++
 			failedApps = append(failedApps, result.AppName)
 		}
@@ -268,3 +581,60 @@ func (al *ApplicationLauncher) GetLaunchSummary() (int, int, []string) {
 
 	return successful, failed, failedApps
 }
+
+// launchFailed reports whether a result is a real failure rather than a skip
+func launchFailed(result types.LaunchResult) bool {
+	return !result.Success && !result.NotInstalled && !result.AlreadyRunning && !result.ManualOnly && !result.Blacklisted
+}
+
+// SummarizeRestore builds the restore summary for results of a restore that ran from start to end
+func SummarizeRestore(results []types.LaunchResult, start, end time.Time) types.RestoreSummary {
+	summary := types.RestoreSummary{
+		TotalApps:       len(results),
+		TotalDuration:   end.Sub(start),
+		InstallHints:    make(map[string]string),
+		ListeningPorts:  make(map[string][]int),
+		LaunchDurations: make(map[string]time.Duration),
+		StartTime:       start,
+		EndTime:         end,
+	}
+
+	for _, result := range results {
+		switch {
+		case result.Success:
+			summary.SuccessfulApps++
+		case result.ReopenedByMacOS:
+			summary.ReopenedByMacOSApps = append(summary.ReopenedByMacOSApps, result.AppName)
+		case result.AlreadyRunning:
+			summary.AlreadyRunningApps = append(summary.AlreadyRunningApps, result.AppName)
+		case result.NotInstalled:
+			summary.NotInstalledApps = append(summary.NotInstalledApps, result.AppName)
+			if result.InstallHint != "" {
+				summary.InstallHints[result.AppName] = result.InstallHint
+			}
+		case result.ManualOnly:
+			summary.ManualOnlyApps = append(summary.ManualOnlyApps, result.AppName)
+		case result.Blacklisted:
+			summary.BlacklistedApps = append(summary.BlacklistedApps, result.AppName)
+		default:
+			summary.FailedApps++
+			summary.FailedAppNames = append(summary.FailedAppNames, result.AppName)
+		}
+
+		if result.MarkedManualOnly {
+			summary.NewlyManualOnlyApps = append(summary.NewlyManualOnlyApps, result.AppName)
+		}
+		if result.MarkedBlacklisted {
+			summary.NewlyBlacklistedApps = append(summary.NewlyBlacklistedApps, result.AppName)
+		}
+		if result.Duration > 0 {
+			summary.LaunchDurations[result.AppName] = result.Duration
+		}
+		if len(result.ListeningPorts) > 0 {
+			summary.ListeningPorts[result.AppName] = result.ListeningPorts
+		}
+	}
+
+	summary.SkippedApps = len(summary.AlreadyRunningApps) + len(summary.ReopenedByMacOSApps) + len(summary.NotInstalledApps) + len(summary.ManualOnlyApps) + len(summary.BlacklistedApps)
+	return summary
+}
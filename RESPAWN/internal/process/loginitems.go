@@ -0,0 +1,74 @@
+package process
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+)
+
+// Login items launch on their own after a reboot. A restore soon after boot waits
+// for them instead of racing macOS and opening them twice.
+const (
+	loginItemWindow = 10 * time.Minute // After boot, login items may still be on their way
+	loginItemWait   = 30 * time.Second // How long a restore waits for one to appear
+)
+
+// loginItems returns the names and bundle paths of the user's macOS Login Items
+func loginItems() (map[string]bool, error) {
+	script := `
+        tell application "System Events"
+            set output to ""
+            repeat with itemRef in login items
+                set output to output & (name of itemRef) & tab & (path of itemRef) & linefeed
+            end repeat
+            return output
+        end tell
+    `
+	output, err := exec.Command("osascript", "-e", script).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		name, path, _ := strings.Cut(line, "\t")
+		if name = strings.TrimSpace(name); name != "" {
+			items[strings.ToLower(name)] = true
+		}
+		if path = strings.TrimSuffix(strings.TrimSpace(path), "/"); path != "" {
+			items[path] = true
+		}
+	}
+	return items, nil
+}
+
+// MarkLoginItems sets LoginItem on the processes macOS opens at login
+func MarkLoginItems(processes []types.ProcessInfo) {
+	items, err := loginItems()
+	if err != nil {
+		system.Debug("Could not read login items:", err)
+		return
+	}
+
+	for i, proc := range processes {
+		bundleName := strings.TrimSuffix(filepath.Base(proc.BundlePath), ".app")
+		if items[strings.ToLower(proc.Name)] || items[proc.BundlePath] || (proc.BundlePath != "" && items[strings.ToLower(bundleName)]) {
+			processes[i].LoginItem = true
+		}
+	}
+}
+
+// loginItemsPending reports whether the machine booted recently enough that login
+// items may not have launched yet
+func loginItemsPending() bool {
+	bootTime, err := system.BootTime()
+	if err != nil {
+		system.Debug("Could not get boot time:", err)
+		return false
+	}
+	return time.Since(bootTime) < loginItemWindow
+}
@@ -0,0 +1,125 @@
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+)
+
+// musicPlayers are the apps whose playback RESPAWN can record
+var musicPlayers = []string{"Music", "Spotify"}
+
+// DetectPlaybackState records what each running music player is playing
+func DetectPlaybackState() []types.PlaybackState {
+	var states []types.PlaybackState
+
+	for _, player := range musicPlayers {
+		// Talking to a player that isn't running would launch it
+		if !isAppRunning(player) {
+			continue
+		}
+
+		state, err := detectPlayer(player)
+		if err != nil {
+			system.Debug("Could not read playback state from", player, ":", err)
+			continue
+		}
+		if state != nil {
+			states = append(states, *state)
+		}
+	}
+
+	return states
+}
+
+// detectPlayer reads track, position and state from one player.
+// It returns nil when the player is stopped.
+func detectPlayer(player string) (*types.PlaybackState, error) {
+	// Spotify exposes a track URI, Music only name and artist
+	trackID := `""`
+	if player == "Spotify" {
+		trackID = "id of current track"
+	}
+
+	script := fmt.Sprintf(`
+        tell application "%s"
+            if player state is stopped then return "stopped"
+            return (player state as string) & linefeed & (name of current track) & linefeed & (artist of current track) & linefeed & (%s) & linefeed & (player position as string)
+        end tell
+    `, player, trackID)
+
+	output, err := exec.Command("osascript", "-e", script).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	if len(lines) < 5 {
+		return nil, nil // Stopped
+	}
+
+	position, _ := strconv.ParseFloat(strings.Replace(strings.TrimSpace(lines[4]), ",", ".", 1), 64)
+
+	return &types.PlaybackState{
+		Player:   player,
+		Track:    lines[1],
+		Artist:   lines[2],
+		TrackID:  lines[3],
+		Position: position,
+		Playing:  lines[0] == "playing",
+	}, nil
+}
+
+// RestorePlaybackState queues up each recorded track at its position,
+// resuming playback only if it was playing at checkpoint time
+func RestorePlaybackState(states []types.PlaybackState) error {
+	var failed []string
+
+	for _, state := range states {
+		var playTrack string
+		if state.Player == "Spotify" && state.TrackID != "" {
+			playTrack = fmt.Sprintf(`play track %s`, system.AppleScriptString(state.TrackID))
+		} else {
+			playTrack = fmt.Sprintf(`play (first track of library playlist 1 whose name is %s and artist is %s)`,
+				system.AppleScriptString(state.Track), system.AppleScriptString(state.Artist))
+		}
+
+		pause := ""
+		if !state.Playing {
+			pause = "pause"
+		}
+
+		script := fmt.Sprintf(`
+            tell application "%s"
+                %s
+                delay 1
+                set player position to %s
+                %s
+            end tell
+        `, state.Player, playTrack, strconv.FormatFloat(state.Position, 'f', 1, 64), pause)
+
+		if err := exec.Command("osascript", "-e", script).Run(); err != nil {
+			system.Warn("Failed to restore playback in", state.Player, ":", err)
+			failed = append(failed, state.Player)
+			continue
+		}
+
+		system.Info("Restored playback in", state.Player, "-", state.Track, "by", state.Artist)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to restore playback in %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// isAppRunning checks whether a GUI app is running without launching it
+func isAppRunning(appName string) bool {
+	script := fmt.Sprintf(`application "%s" is running`, appName)
+	output, err := exec.Command("osascript", "-e", script).Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
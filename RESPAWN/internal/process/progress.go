@@ -0,0 +1,35 @@
+package process
+
+import "RESPAWN/internal/types"
+
+// Restore progress statuses, reported once an app reaches them
+const (
+	ProgressLaunching       = "launching"
+	ProgressLaunched        = "launched"
+	ProgressFailed          = "failed"
+	ProgressAlreadyRunning  = "already_running"
+	ProgressLoginItem       = "login_item"
+	ProgressReopenedByMacOS = "reopened_by_macos"
+	ProgressNotInstalled    = "not_installed"
+	ProgressManualOnly      = "manual_only"
+	ProgressBlacklisted     = "blacklisted"
+)
+
+// RestoreProgress describes one step of a restore. Every app is reported when its
+// launch starts and again with its outcome.
+type RestoreProgress struct {
+	App    string
+	Index  int // 1-based position in the restore order
+	Total  int
+	Status string
+	Result *types.LaunchResult // Set for every final status
+}
+
+// Done reports whether the app has reached its final status
+func (p RestoreProgress) Done() bool {
+	return p.Status != ProgressLaunching
+}
+
+// ProgressFunc receives restore progress. It is called synchronously from the
+// restore, so it should return quickly.
+type ProgressFunc func(progress RestoreProgress)
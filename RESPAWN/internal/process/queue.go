@@ -0,0 +1,107 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"RESPAWN/internal/types"
+	"RESPAWN/pkg/config"
+)
+
+// restoreQueueFileName holds the apps an unfinished restore has yet to launch
+const restoreQueueFileName = "restore_queue.json"
+
+// ErrNoRestoreQueue means there is no interrupted restore to continue
+var ErrNoRestoreQueue = errors.New("no interrupted restore to continue")
+
+// ErrRestoreRunning means the queued restore is still being run by another process
+var ErrRestoreRunning = errors.New("restore is still running")
+
+// RestoreQueue is the part of a restore still to be launched. It is rewritten
+// before every app and removed once the restore finishes, so one left on disk
+// means the restore was interrupted by sleep, a crash or a kill - unless the
+// process that wrote it is still running.
+type RestoreQueue struct {
+	Source    string              `json:"source"`        // Checkpoint ID or template name
+	PID       int                 `json:"pid,omitempty"` // Process running the restore
+	StartedAt time.Time           `json:"started_at"`
+	Launched  []string            `json:"launched,omitempty"` // Apps already handled
+	Remaining []types.ProcessInfo `json:"remaining"`
+}
+
+// restoreQueuePath is where the queue lives in the data directory
+func restoreQueuePath() string {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return filepath.Join(cfg.DataDir, restoreQueueFileName)
+}
+
+// LoadRestoreQueue reads the queue of an interrupted restore, returning
+// ErrNoRestoreQueue when there is none
+func LoadRestoreQueue() (*RestoreQueue, error) {
+	data, err := os.ReadFile(restoreQueuePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoRestoreQueue
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var queue RestoreQueue
+	if err := json.Unmarshal(data, &queue); err != nil {
+		return nil, fmt.Errorf("restore queue is unreadable: %w", err)
+	}
+	if len(queue.Remaining) == 0 {
+		return nil, ErrNoRestoreQueue
+	}
+	return &queue, nil
+}
+
+// Running reports whether another process that is still alive owns the queue,
+// so the restore is in progress rather than interrupted
+func (q *RestoreQueue) Running() bool {
+	if q.PID <= 0 || q.PID == os.Getpid() {
+		return false
+	}
+	owner, err := os.FindProcess(q.PID)
+	if err != nil {
+		return false
+	}
+	// EPERM means the process exists but belongs to another user
+	err = owner.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// save writes the queue, replacing it atomically so an interruption mid-write
+// leaves the previous state
+func (q *RestoreQueue) save() error {
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := restoreQueuePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// clearRestoreQueue removes the queue once a restore has finished
+func clearRestoreQueue() error {
+	if err := os.Remove(restoreQueuePath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package process
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"RESPAWN/internal/types"
+)
+
+// lsappinfo prints one block per app; these pick its bundle ID and PID
+var (
+	lsappinfoBundlePattern = regexp.MustCompile(`bundleID="([^"]+)"`)
+	lsappinfoPIDPattern    = regexp.MustCompile(`\bpid = (\d+)`)
+)
+
+// runningBundleIDs maps the bundle ID of every running app to its PID. lsappinfo
+// talks to the window server directly, so it needs no automation permission.
+func runningBundleIDs() (map[string]int, error) {
+	output, err := exec.Command("lsappinfo", "list").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	running := make(map[string]int)
+	bundleID := ""
+	for _, line := range strings.Split(string(output), "\n") {
+		if match := lsappinfoBundlePattern.FindStringSubmatch(line); match != nil {
+			bundleID = match[1]
+		}
+		if match := lsappinfoPIDPattern.FindStringSubmatch(line); match != nil && bundleID != "" {
+			if pid, err := strconv.Atoi(match[1]); err == nil {
+				running[bundleID] = pid
+			}
+			bundleID = ""
+		}
+	}
+	return running, nil
+}
+
+// runningPID finds a checkpointed app among the running ones. The bundle ID is
+// exact; without one it falls back to matching the process name.
+func (al *ApplicationLauncher) runningPID(proc types.ProcessInfo) (int, bool) {
+	if proc.BundleID != "" {
+		if running, err := runningBundleIDs(); err == nil {
+			pid, ok := running[proc.BundleID]
+			return pid, ok
+		}
+	}
+	return al.verifyApplicationLaunched(proc.ProcessName)
+}
@@ -0,0 +1,202 @@
+package process
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+)
+
+// firefoxSessionFiles are copied from a Firefox profile, newest state first
+var firefoxSessionFiles = []string{
+	"sessionstore-backups/recovery.jsonlz4",
+	"sessionstore-backups/recovery.baklz4",
+	"sessionstore.jsonlz4",
+}
+
+// IsSessionBrowser reports whether RESPAWN can snapshot an app's own session files
+func IsSessionBrowser(appName string) bool {
+	_, chromium := chromiumProfileDirs[appName]
+	return chromium || appName == "Firefox"
+}
+
+// CaptureSessionFiles copies a browser's session files into destDir.
+// Profiles are the ones recorded for the process, empty meaning the default profile.
+func CaptureSessionFiles(appName string, profiles []string, destDir string) ([]types.SessionSnapshot, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	supportDir := filepath.Join(homeDir, "Library", "Application Support")
+
+	var sources []types.SessionSnapshot
+	if dir, ok := chromiumProfileDirs[appName]; ok {
+		if len(profiles) == 0 {
+			profiles = []string{"Default"}
+		}
+		for _, profile := range profiles {
+			sources = append(sources, types.SessionSnapshot{
+				AppName:   appName,
+				Profile:   profile,
+				SourceDir: filepath.Join(supportDir, dir, profile, "Sessions"),
+			})
+		}
+	} else if appName == "Firefox" {
+		profileDir, err := latestFirefoxProfile(filepath.Join(supportDir, "Firefox", "Profiles"))
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, types.SessionSnapshot{
+			AppName:   appName,
+			Profile:   filepath.Base(profileDir),
+			SourceDir: profileDir,
+		})
+	}
+
+	var snapshots []types.SessionSnapshot
+	for _, snapshot := range sources {
+		snapshot.StoredDir = filepath.Join(destDir, safeName(appName), safeName(snapshot.Profile))
+
+		files, err := sessionFiles(appName, snapshot.SourceDir)
+		if err != nil {
+			system.Debug("No session files for", appName, snapshot.Profile, ":", err)
+			continue
+		}
+
+		for _, rel := range files {
+			if err := copyFile(filepath.Join(snapshot.SourceDir, rel), filepath.Join(snapshot.StoredDir, rel)); err != nil {
+				return snapshots, fmt.Errorf("failed to copy %s session file %s: %w", appName, rel, err)
+			}
+			snapshot.Files = append(snapshot.Files, rel)
+		}
+
+		if len(snapshot.Files) > 0 {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	return snapshots, nil
+}
+
+// RestoreSessionFiles copies snapshotted session files back for browsers that aren't running.
+// A running browser would overwrite them on exit, so those are left alone.
+func RestoreSessionFiles(snapshots []types.SessionSnapshot) (restored []string) {
+	launcher := &ApplicationLauncher{}
+
+	for _, snapshot := range snapshots {
+		if launcher.isApplicationRunning(snapshot.AppName) {
+			system.Info("Skipping session restore for", snapshot.AppName, "- already running")
+			continue
+		}
+
+		ok := true
+		for _, rel := range snapshot.Files {
+			if err := copyFile(filepath.Join(snapshot.StoredDir, rel), filepath.Join(snapshot.SourceDir, rel)); err != nil {
+				system.Warn("Failed to restore session file", rel, "for", snapshot.AppName, ":", err)
+				ok = false
+			}
+		}
+
+		if ok {
+			restored = append(restored, snapshot.AppName)
+			system.Info("Restored session files for", snapshot.AppName, "profile", snapshot.Profile)
+		}
+	}
+
+	return restored
+}
+
+// sessionFiles lists the session files to copy, relative to the source directory
+func sessionFiles(appName, sourceDir string) ([]string, error) {
+	if appName == "Firefox" {
+		var files []string
+		for _, rel := range firefoxSessionFiles {
+			if _, err := os.Stat(filepath.Join(sourceDir, rel)); err == nil {
+				files = append(files, rel)
+			}
+		}
+		return files, nil
+	}
+
+	// Chromium keeps Session_* and Tabs_* files in the Sessions directory
+	entries, err := os.ReadDir(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, entry.Name())
+		}
+	}
+	return files, nil
+}
+
+// latestFirefoxProfile returns the profile whose session was written most recently
+func latestFirefoxProfile(profilesDir string) (string, error) {
+	entries, err := os.ReadDir(profilesDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Firefox profiles: %w", err)
+	}
+
+	var latest string
+	var latestMod int64
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(profilesDir, entry.Name())
+		info, err := os.Stat(filepath.Join(dir, "sessionstore-backups"))
+		if err != nil {
+			continue
+		}
+		if info.ModTime().Unix() > latestMod {
+			latest = dir
+			latestMod = info.ModTime().Unix()
+		}
+	}
+
+	if latest == "" {
+		return "", fmt.Errorf("no Firefox profile with session data found")
+	}
+	return latest, nil
+}
+
+// copyFile copies src to dst, creating dst's directory
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// safeName turns an app or profile name into a directory name
+func safeName(name string) string {
+	safe := []rune(name)
+	for i, r := range safe {
+		if r == '/' || r == ' ' {
+			safe[i] = '_'
+		}
+	}
+	return string(safe)
+}
@@ -0,0 +1,117 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+	"RESPAWN/pkg/config"
+)
+
+// knownWindowStateFiles lists where common Electron apps persist their window bounds,
+// relative to ~/Library/Application Support. Config entries are used in addition.
+// These are whole settings files, so capturing them is opt-in (capture_window_state_files).
+var knownWindowStateFiles = map[string][]string{
+	"Visual Studio Code": {"Code/User/globalStorage/storage.json"},
+	"Slack":              {"Slack/storage/root-state.json"},
+	"Discord":            {"discord/settings.json"},
+	"Obsidian":           {"obsidian/obsidian.json"},
+}
+
+// WindowStateFilesFor returns the window-state files to snapshot for an app
+func WindowStateFilesFor(appName string) []string {
+	files := append([]string{}, knownWindowStateFiles[appName]...)
+	for _, app := range config.Current().Applications {
+		if app.Name == appName {
+			files = append(files, app.WindowStateFiles...)
+		}
+	}
+	return files
+}
+
+// CaptureWindowStateFiles copies an app's window-state files into destDir.
+// This restores geometry for apps that remember it themselves, without Accessibility scripting.
+func CaptureWindowStateFiles(appName string, files []string, destDir string) []types.StateFileSnapshot {
+	var snapshots []types.StateFileSnapshot
+
+	for _, file := range files {
+		source := expandStatePath(file)
+		info, err := os.Stat(source)
+		if err != nil {
+			system.Debug("No window-state file for", appName, "at", source)
+			continue
+		}
+
+		// Keyed by the full path, files sharing a base name don't overwrite each other
+		stored := filepath.Join(destDir, safeName(appName), storedStatePath(source))
+		if err := copyFile(source, stored); err != nil {
+			system.Warn("Failed to snapshot window-state file", source, ":", err)
+			continue
+		}
+
+		snapshots = append(snapshots, types.StateFileSnapshot{
+			AppName:    appName,
+			SourcePath: source,
+			StoredPath: stored,
+			ModTime:    info.ModTime(),
+		})
+	}
+
+	return snapshots
+}
+
+// RestoreWindowStateFiles copies window-state files back for apps that aren't running,
+// so they read the recorded geometry when launched. A file changed since it was
+// captured holds newer settings and is never overwritten.
+func RestoreWindowStateFiles(snapshots []types.StateFileSnapshot) (restored []string) {
+	launcher := &ApplicationLauncher{}
+	seen := make(map[string]bool)
+
+	for _, snapshot := range snapshots {
+		if launcher.isApplicationRunning(snapshot.AppName) {
+			system.Debug("Skipping window-state restore for", snapshot.AppName, "- already running")
+			continue
+		}
+		if info, err := os.Stat(snapshot.SourcePath); err == nil && !info.ModTime().Equal(snapshot.ModTime) {
+			system.Info("Keeping", snapshot.SourcePath, "- it changed after the checkpoint was taken")
+			continue
+		}
+
+		if err := copyFile(snapshot.StoredPath, snapshot.SourcePath); err != nil {
+			system.Warn("Failed to restore window-state file for", snapshot.AppName, ":", err)
+			continue
+		}
+
+		if !seen[snapshot.AppName] {
+			seen[snapshot.AppName] = true
+			restored = append(restored, snapshot.AppName)
+		}
+	}
+
+	return restored
+}
+
+// storedStatePath is where a state file goes inside an app's snapshot directory: its
+// path relative to the home directory, or its absolute path for files outside it
+func storedStatePath(source string) string {
+	homeDir, _ := os.UserHomeDir()
+	if rel, err := filepath.Rel(homeDir, source); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return strings.TrimPrefix(source, string(filepath.Separator))
+}
+
+// expandStatePath resolves ~ and paths relative to ~/Library/Application Support
+func expandStatePath(path string) string {
+	homeDir, _ := os.UserHomeDir()
+
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(homeDir, path[2:])
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(homeDir, "Library", "Application Support", path)
+}
@@ -0,0 +1,67 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"RESPAWN/internal/types"
+)
+
+// VersionChange is an app whose installed version differs from the checkpointed one
+type VersionChange struct {
+	App   string `json:"app"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Major bool   `json:"major"` // The major version changed; session data may not carry over
+}
+
+// BundleVersion reads CFBundleShortVersionString from an app bundle, "" if unavailable
+func BundleVersion(bundlePath string) string {
+	if bundlePath == "" {
+		return ""
+	}
+	plist := filepath.Join(bundlePath, "Contents", "Info.plist")
+	output, err := exec.Command("/usr/libexec/PlistBuddy", "-c", "Print :CFBundleShortVersionString", plist).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// CompareVersions finds checkpointed apps that were upgraded or downgraded since the
+// checkpoint. Apps captured without a version, or no longer installed, are skipped.
+func CompareVersions(processes []types.ProcessInfo) []VersionChange {
+	var changes []VersionChange
+	seen := make(map[string]bool)
+	for _, proc := range processes {
+		if proc.BundleVersion == "" || seen[proc.Name] {
+			continue
+		}
+		seen[proc.Name] = true
+
+		bundlePath := proc.BundlePath
+		if _, err := os.Stat(bundlePath); bundlePath == "" || err != nil {
+			bundlePath = ResolveBundlePath(proc.BundleID, proc.Name)
+		}
+		installed := BundleVersion(bundlePath)
+		if installed == "" || installed == proc.BundleVersion {
+			continue
+		}
+
+		changes = append(changes, VersionChange{
+			App:   proc.Name,
+			From:  proc.BundleVersion,
+			To:    installed,
+			Major: majorVersion(installed) != majorVersion(proc.BundleVersion),
+		})
+	}
+	return changes
+}
+
+// majorVersion returns the first component of a version, e.g. "16" for "16.0.1"
+func majorVersion(version string) string {
+	major, _, _ := strings.Cut(version, ".")
+	return strings.TrimSpace(major)
+}
@@ -0,0 +1,165 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+)
+
+// Virtual machine providers RESPAWN knows how to query
+const (
+	VMProviderUTM       = "UTM"
+	VMProviderParallels = "Parallels"
+	VMProviderFusion    = "VMware Fusion"
+)
+
+// DetectVirtualMachines lists running VMs across UTM, Parallels and VMware Fusion
+func DetectVirtualMachines() []types.VirtualMachine {
+	var vms []types.VirtualMachine
+
+	for provider, detect := range map[string]func() ([]types.VirtualMachine, error){
+		VMProviderUTM:       detectUTM,
+		VMProviderParallels: detectParallels,
+		VMProviderFusion:    detectFusion,
+	} {
+		found, err := detect()
+		if err != nil {
+			system.Debug("Could not list", provider, "VMs:", err)
+			continue
+		}
+		vms = append(vms, found...)
+	}
+
+	return vms
+}
+
+// detectUTM parses `utmctl list`: "UUID  Status  Name"
+func detectUTM() ([]types.VirtualMachine, error) {
+	path, err := exec.LookPath("utmctl")
+	if err != nil {
+		path = "/Applications/UTM.app/Contents/MacOS/utmctl"
+	}
+
+	output, err := exec.Command(path, "list").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var vms []types.VirtualMachine
+	for _, line := range strings.Split(string(output), "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[1] != "started" {
+			continue
+		}
+		vms = append(vms, types.VirtualMachine{
+			Provider: VMProviderUTM,
+			ID:       fields[0],
+			Name:     strings.Join(fields[2:], " "),
+		})
+	}
+	return vms, nil
+}
+
+// detectParallels reads running VMs from `prlctl list -j`
+func detectParallels() ([]types.VirtualMachine, error) {
+	if _, err := exec.LookPath("prlctl"); err != nil {
+		return nil, nil
+	}
+
+	output, err := exec.Command("prlctl", "list", "-j").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []struct {
+		UUID   string `json:"uuid"`
+		Status string `json:"status"`
+		Name   string `json:"name"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse prlctl output: %w", err)
+	}
+
+	var vms []types.VirtualMachine
+	for _, vm := range list {
+		if vm.Status != "running" {
+			continue
+		}
+		vms = append(vms, types.VirtualMachine{Provider: VMProviderParallels, ID: vm.UUID, Name: vm.Name})
+	}
+	return vms, nil
+}
+
+// detectFusion parses `vmrun list`, one .vmx path per running VM
+func detectFusion() ([]types.VirtualMachine, error) {
+	path, err := exec.LookPath("vmrun")
+	if err != nil {
+		path = "/Applications/VMware Fusion.app/Contents/Library/vmrun"
+	}
+
+	output, err := exec.Command(path, "list").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var vms []types.VirtualMachine
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasSuffix(line, ".vmx") {
+			continue // Skips the "Total running VMs" header
+		}
+		vms = append(vms, types.VirtualMachine{
+			Provider: VMProviderFusion,
+			ID:       line,
+			Name:     strings.TrimSuffix(filepath.Base(line), ".vmx"),
+		})
+	}
+	return vms, nil
+}
+
+// RestoreVirtualMachines starts each recorded VM unless its name is in exclude
+func RestoreVirtualMachines(vms []types.VirtualMachine, exclude []string) (started, failed []string) {
+	skip := make(map[string]bool)
+	for _, name := range exclude {
+		skip[name] = true
+	}
+
+	for _, vm := range vms {
+		if skip[vm.Name] {
+			system.Info("Skipping VM", vm.Name, "- excluded in config")
+			continue
+		}
+
+		var cmd *exec.Cmd
+		switch vm.Provider {
+		case VMProviderUTM:
+			// utmctl needs the UTM app running
+			if err := exec.Command("open", "-a", "UTM").Run(); err != nil {
+				system.Debug("Failed to open UTM:", err)
+			}
+			cmd = exec.Command("/Applications/UTM.app/Contents/MacOS/utmctl", "start", vm.ID)
+		case VMProviderParallels:
+			cmd = exec.Command("prlctl", "start", vm.ID)
+		case VMProviderFusion:
+			cmd = exec.Command("/Applications/VMware Fusion.app/Contents/Library/vmrun", "start", vm.ID, "gui")
+		default:
+			continue
+		}
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			system.Warn("Failed to start VM", vm.Name, ":", err, strings.TrimSpace(string(output)))
+			failed = append(failed, vm.Name)
+			continue
+		}
+
+		system.Info("Started", vm.Provider, "VM", vm.Name)
+		started = append(started, vm.Name)
+	}
+
+	return started, failed
+}
@@ -0,0 +1,127 @@
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+)
+
+// networkFSTypes lists the filesystem types treated as network shares
+var networkFSTypes = map[string]string{
+	"smbfs":  "smb",
+	"afpfs":  "afp",
+	"nfs":    "nfs",
+	"webdav": "",
+}
+
+// DetectNetworkVolumes returns the currently mounted SMB/AFP/NFS/WebDAV volumes
+func DetectNetworkVolumes() ([]types.MountedVolume, error) {
+	cmd := exec.Command("mount")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute mount command: %w", err)
+	}
+
+	var volumes []types.MountedVolume
+	for _, line := range strings.Split(string(output), "\n") {
+		volume, ok := parseMountLine(line)
+		if !ok {
+			continue
+		}
+		volumes = append(volumes, volume)
+	}
+
+	system.Debug("Detected", len(volumes), "network volumes")
+	return volumes, nil
+}
+
+// parseMountLine parses "<source> on <mountpoint> (<fstype>, <options>...)"
+func parseMountLine(line string) (types.MountedVolume, bool) {
+	var volume types.MountedVolume
+
+	onIdx := strings.Index(line, " on ")
+	optIdx := strings.LastIndex(line, " (")
+	if onIdx == -1 || optIdx == -1 || optIdx < onIdx {
+		return volume, false
+	}
+
+	options := strings.TrimSuffix(line[optIdx+2:], ")")
+	fsType := strings.TrimSpace(strings.Split(options, ",")[0])
+	if _, ok := networkFSTypes[fsType]; !ok {
+		return volume, false
+	}
+
+	volume.Source = line[:onIdx]
+	volume.MountPoint = line[onIdx+4 : optIdx]
+	volume.FSType = fsType
+	return volume, true
+}
+
+// RemountVolumes remounts recorded volumes that are no longer mounted
+func RemountVolumes(volumes []types.MountedVolume) (remounted []string, failed []string) {
+	if len(volumes) == 0 {
+		return nil, nil
+	}
+
+	current, err := DetectNetworkVolumes()
+	if err != nil {
+		system.Warn("Failed to detect current volumes:", err)
+	}
+
+	mounted := make(map[string]bool)
+	for _, volume := range current {
+		mounted[volume.MountPoint] = true
+	}
+
+	for _, volume := range volumes {
+		if mounted[volume.MountPoint] {
+			system.Debug("Volume already mounted:", volume.MountPoint)
+			continue
+		}
+
+		url := volumeURL(volume)
+		if url == "" {
+			system.Warn("Don't know how to remount", volume.Source, "(", volume.FSType, ")")
+			failed = append(failed, volume.MountPoint)
+			continue
+		}
+
+		// 'open' lets Finder handle credentials from the keychain
+		system.Info("Remounting volume:", url)
+		if err := exec.Command("open", url).Run(); err != nil {
+			system.Warn("Failed to remount", url, ":", err)
+			failed = append(failed, volume.MountPoint)
+			continue
+		}
+		remounted = append(remounted, volume.MountPoint)
+	}
+
+	return remounted, failed
+}
+
+// volumeURL converts a mount source into a URL that Finder can open
+func volumeURL(volume types.MountedVolume) string {
+	scheme := networkFSTypes[volume.FSType]
+
+	switch volume.FSType {
+	case "smbfs", "afpfs":
+		// "//user@server/share" -> "smb://user@server/share"
+		return scheme + ":" + volume.Source
+	case "nfs":
+		// "server:/export/path" -> "nfs://server/export/path"
+		parts := strings.SplitN(volume.Source, ":", 2)
+		if len(parts) != 2 {
+			return ""
+		}
+		return fmt.Sprintf("%s://%s%s", scheme, parts[0], parts[1])
+	case "webdav":
+		// WebDAV sources are already URLs
+		if strings.HasPrefix(volume.Source, "http") {
+			return volume.Source
+		}
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// streamKeepAlive is how often an idle event stream sends a comment so proxies and clients don't time out
+const streamKeepAlive = 30 * time.Second
+
+// APIServer is the daemon's local HTTP API
+type APIServer struct {
+	server *http.Server
+}
+
+// StartAPIServer serves the local API on addr, a unix socket or a loopback address.
+//
+//	GET /health  - liveness check
+//	GET /events  - server-sent events stream; ?types=checkpoint.created,restore.progress filters
+func StartAPIServer(addr string) (*APIServer, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", handleHealth)
+	mux.HandleFunc("/events", handleEventStream)
+
+	return startServer("Local API", addr, mux)
+}
+
+// startServer serves handler on addr in the background. addr is host:port, or
+// "unix:" followed by a socket path only the current user can connect to.
+func startServer(name, addr string, handler http.Handler) (*APIServer, error) {
+	listener, err := listen(addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	api := &APIServer{server: &http.Server{Handler: handler}}
+	go func() {
+		defer RecordPanics()
+		if err := api.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			Error(name, "server stopped:", err)
+		}
+	}()
+
+	Info(name, "listening on", listener.Addr())
+	return api, nil
+}
+
+// listen opens a TCP listener, or a unix socket for "unix:" addresses. The API has
+// no authentication, so TCP is only offered on loopback.
+func listen(addr string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, "unix:")
+	if !ok {
+		if err := loopbackOnly(addr); err != nil {
+			return nil, err
+		}
+		return net.Listen("tcp", addr)
+	}
+
+	// A socket left behind by a daemon that didn't shut down cleanly blocks the listen
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("socket is in use by another process")
+	}
+	os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// loopbackOnly rejects TCP addresses that other machines could connect to
+func loopbackOnly(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("address %q is invalid: %w", addr, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("address %q must be on localhost", addr)
+	}
+	return nil
+}
+
+// Stop closes the server and disconnects stream clients
+func (api *APIServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// Shutdown doesn't wait for hijacked or streaming connections, Close ends them
+	if err := api.server.Shutdown(ctx); err != nil {
+		api.server.Close()
+	}
+}
+
+// handleHealth reports that the daemon is up
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "ok",
+		"pid":    os.Getpid(),
+	})
+}
+
+// handleEventStream streams events to the client as server-sent events
+func handleEventStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	wanted := make(map[EventType]bool)
+	if types := r.URL.Query().Get("types"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			wanted[EventType(strings.TrimSpace(t))] = true
+		}
+	}
+
+	events, unsubscribe := Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	Debug("Event stream client connected:", r.RemoteAddr)
+	defer Debug("Event stream client disconnected:", r.RemoteAddr)
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if len(wanted) > 0 && !wanted[event.Type] {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package system
+
+import "strings"
+
+// appleScriptEscaper escapes what would end or break an AppleScript string literal
+var appleScriptEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+)
+
+// AppleScriptString quotes s as an AppleScript string literal, for anything put
+// into a script that didn't come from RESPAWN itself: app names, window titles,
+// notification text
+func AppleScriptString(s string) string {
+	return `"` + appleScriptEscaper.Replace(s) + `"`
+}
@@ -0,0 +1,18 @@
+package system
+
+import "testing"
+
+// TestAppleScriptString verifies quotes, backslashes and line breaks can't end the literal
+func TestAppleScriptString(t *testing.T) {
+	tests := map[string]string{
+		"Safari":                          `"Safari"`,
+		"Disk \"Data\" is full\nC:\\tmp":  `"Disk \"Data\" is full\nC:\\tmp"`,
+		"a\r\tb":                          `"a\r\tb"`,
+		`x" & (do shell script "id") & "`: `"x\" & (do shell script \"id\") & \""`,
+	}
+	for input, want := range tests {
+		if got := AppleScriptString(input); got != want {
+			t.Errorf("AppleScriptString(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
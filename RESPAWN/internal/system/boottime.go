@@ -0,0 +1,61 @@
+package system
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bootTimePattern pulls the seconds out of `sysctl -n kern.boottime`, which prints
+// e.g. "{ sec = 1712034000, usec = 123456 } Tue Apr  2 07:00:00 2024"
+var bootTimePattern = regexp.MustCompile(`sec = (\d+)`)
+
+// BootTime returns when the machine last booted
+func BootTime() (time.Time, error) {
+	output, err := exec.Command("sysctl", "-n", "kern.boottime").Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read boot time: %w", err)
+	}
+
+	match := bootTimePattern.FindSubmatch(output)
+	if match == nil {
+		return time.Time{}, fmt.Errorf("unexpected kern.boottime output %q", output)
+	}
+	seconds, err := strconv.ParseInt(string(match[1]), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+var (
+	bootSessionOnce sync.Once
+	bootSessionID   string
+)
+
+// BootSessionID identifies the current boot: it stays the same across sleep and
+// changes with every restart. It is the kernel's kern.bootsessionuuid rather than
+// the boot time, which macOS shifts when the clock is stepped, e.g. by NTP after
+// wake. Empty if it can't be read.
+func BootSessionID() string {
+	bootSessionOnce.Do(func() {
+		output, err := exec.Command("sysctl", "-n", "kern.bootsessionuuid").Output()
+		if err != nil {
+			Debug("Failed to read boot session:", err)
+			return
+		}
+		bootSessionID = strings.TrimSpace(string(output))
+	})
+	return bootSessionID
+}
+
+// isBootTimeSession reports whether id is a boot session recorded by older versions,
+// which used the boot time in seconds and can't be compared with the UUID
+func isBootTimeSession(id string) bool {
+	_, err := strconv.ParseInt(id, 10, 64)
+	return err == nil
+}
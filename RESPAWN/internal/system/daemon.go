@@ -0,0 +1,72 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+)
+
+// daemonEnv marks a process started by Daemonize so it doesn't detach again
+const daemonEnv = "RESPAWN_DAEMON"
+
+// IsDaemon reports whether this process already runs detached, either started by
+// Daemonize or by launchd
+func IsDaemon() bool {
+	return os.Getenv(daemonEnv) == "1" || os.Getppid() == 1
+}
+
+// Daemonize starts this binary with args as a detached daemon and returns its PID.
+// The child gets its own session (setsid), so it has no controlling terminal and
+// survives the shell closing; its working directory is / and its output goes to the
+// same log files launchd uses. Go can't fork without exec, so setsid on a fresh
+// exec stands in for the classic double fork.
+func Daemonize(args ...string) (int, error) {
+	executablePath, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to locate respawn binary: %w", err)
+	}
+
+	logDir, err := LogDir()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	stdin, err := os.Open(os.DevNull)
+	if err != nil {
+		return 0, err
+	}
+	defer stdin.Close()
+
+	stdout, err := os.OpenFile(filepath.Join(logDir, "respawn_stdout.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open stdout log: %w", err)
+	}
+	defer stdout.Close()
+
+	stderr, err := os.OpenFile(filepath.Join(logDir, "respawn_stderr.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open stderr log: %w", err)
+	}
+	defer stderr.Close()
+
+	cmd := exec.Command(executablePath, args...)
+	cmd.Env = append(os.Environ(), daemonEnv+"=1")
+	cmd.Dir = "/"
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start daemon: %w", err)
+	}
+
+	pid := cmd.Process.Pid
+	cmd.Process.Release() // Never waited on - the child outlives us
+	return pid, nil
+}
@@ -0,0 +1,189 @@
+package system
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"RESPAWN/pkg/config"
+)
+
+// EventType names an event sent to webhooks and the live event stream
+type EventType string
+
+// Lifecycle events, sent to webhooks and the event stream
+const (
+	EventCheckpointCreated EventType = "checkpoint.created"
+	EventCheckpointFailed  EventType = "checkpoint.failed"
+	EventRestoreCompleted  EventType = "restore.completed"
+	EventDaemonCrashed     EventType = "daemon.crashed"
+)
+
+// Progress events, only sent to the event stream
+const (
+	EventCheckpointStarted EventType = "checkpoint.started"
+	EventAppDetected       EventType = "app.detected"
+	EventRestoreProgress   EventType = "restore.progress"
+)
+
+// subscriberBuffer is how many events a slow stream client may lag behind before events are dropped for it
+const subscriberBuffer = 64
+
+// Event is the JSON payload POSTed to webhooks and streamed to subscribers
+type Event struct {
+	Type      EventType              `json:"event"`
+	Timestamp time.Time              `json:"timestamp"`
+	Host      string                 `json:"host"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+const (
+	webhookTimeout  = 5 * time.Second
+	webhookAttempts = 3
+)
+
+// webhookSignatureHeader carries the hex HMAC-SHA256 of the body when a secret is configured
+const webhookSignatureHeader = "X-Respawn-Signature"
+
+var (
+	webhookClient   = &http.Client{Timeout: webhookTimeout}
+	pendingWebhooks sync.WaitGroup
+
+	subscribersMu sync.Mutex
+	subscribers   = make(map[chan Event]struct{})
+)
+
+// Emit sends a lifecycle event to the event stream and every webhook subscribed
+// to it. Delivery happens in the background; call FlushEvents before exiting so
+// it isn't cut short.
+func Emit(eventType EventType, data map[string]interface{}) {
+	event := newEvent(eventType, data)
+	broadcast(event)
+
+	if config.Current() == nil || len(config.Current().Webhooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		Warn("Failed to encode event", eventType, ":", err)
+		return
+	}
+
+	for _, hook := range config.Current().Webhooks {
+		if !hook.Wants(string(eventType)) {
+			continue
+		}
+		pendingWebhooks.Add(1)
+		go func(hook config.WebhookConfig) {
+			defer pendingWebhooks.Done()
+			deliverWebhook(hook, eventType, body)
+		}(hook)
+	}
+}
+
+// Publish sends a progress event to the event stream only
+func Publish(eventType EventType, data map[string]interface{}) {
+	broadcast(newEvent(eventType, data))
+}
+
+// Subscribe returns a channel receiving every event from now on, and a
+// function that unsubscribes and closes it
+func Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	subscribersMu.Lock()
+	subscribers[ch] = struct{}{}
+	subscribersMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			subscribersMu.Lock()
+			delete(subscribers, ch)
+			subscribersMu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// broadcast hands an event to every subscriber without blocking on slow ones
+func broadcast(event Event) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
+	for ch := range subscribers {
+		select {
+		case ch <- event:
+		default:
+			Debug("Event stream subscriber is behind, dropped", event.Type)
+		}
+	}
+}
+
+// newEvent stamps an event with the time and host
+func newEvent(eventType EventType, data map[string]interface{}) Event {
+	host, _ := os.Hostname()
+	return Event{Type: eventType, Timestamp: time.Now(), Host: host, Data: data}
+}
+
+// FlushEvents waits up to timeout for in-flight webhook deliveries
+func FlushEvents(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		pendingWebhooks.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		Warn("Gave up waiting for webhook deliveries after", timeout)
+	}
+}
+
+// deliverWebhook POSTs body to a webhook, retrying with a short backoff
+func deliverWebhook(hook config.WebhookConfig, eventType EventType, body []byte) {
+	var lastErr error
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		if lastErr = postWebhook(hook, eventType, body); lastErr == nil {
+			Debug("Delivered", eventType, "to", hook.URL)
+			return
+		}
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+	Warn("Webhook", hook.URL, "failed for", eventType, ":", lastErr)
+}
+
+// postWebhook makes a single delivery attempt
+func postWebhook(hook config.WebhookConfig, eventType EventType, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Respawn-Event", string(eventType))
+	if hook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(body)
+		req.Header.Set(webhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package system
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+)
+
+// heartbeatFileName is the file the daemon rewrites every heartbeat interval
+const heartbeatFileName = "heartbeat"
+
+// Heartbeat is what the daemon records each interval so the next start can tell
+// a restart, a sleep and a crash apart
+type Heartbeat struct {
+    Time        time.Time     `json:"time"`
+    Uptime      time.Duration `json:"uptime,omitempty"`       // Time since boot when written; wall-clock changes don't affect it
+    BootSession string        `json:"boot_session,omitempty"` // Identifies the boot the heartbeat was written in
+    PID         int           `json:"pid,omitempty"`
+}
+
+// writeHeartbeat saves a heartbeat into dir, replacing the previous one atomically
+// so a crash mid-write leaves the old heartbeat rather than a torn file
+func writeHeartbeat(dir string, heartbeat Heartbeat) error {
+    data, err := json.Marshal(heartbeat)
+    if err != nil {
+        return err
+    }
+    return writeFileAtomic(filepath.Join(dir, heartbeatFileName), data, 0644)
+}
+
+// readHeartbeat loads the heartbeat in dir. Files from older versions held a bare
+// RFC 3339 timestamp; if the content can't be parsed at all the file's modification
+// time stands in, since it was written at the last heartbeat either way.
+func readHeartbeat(dir string) (*Heartbeat, error) {
+    path := filepath.Join(dir, heartbeatFileName)
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+
+    var heartbeat Heartbeat
+    if err := json.Unmarshal(data, &heartbeat); err == nil && !heartbeat.Time.IsZero() {
+        return &heartbeat, nil
+    }
+
+    if t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data))); err == nil {
+        return &Heartbeat{Time: t}, nil
+    }
+
+    info, err := os.Stat(path)
+    if err != nil {
+        return nil, err
+    }
+    Warn("Heartbeat file is corrupt, using its modification time")
+    return &Heartbeat{Time: info.ModTime()}, nil
+}
+
+// currentHeartbeat describes this process and boot as of now
+func currentHeartbeat(pid int) Heartbeat {
+    heartbeat := Heartbeat{
+        Time:        time.Now(),
+        BootSession: BootSessionID(),
+        PID:         pid,
+    }
+    if boot, err := BootTime(); err == nil {
+        heartbeat.Uptime = time.Since(boot)
+    }
+    return heartbeat
+}
+
+// writeFileAtomic writes data to a temporary file, flushes it to disk and renames it
+// over path, so readers see either the old or the new content
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+    tmp := path + ".tmp"
+    file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+    if err != nil {
+        return err
+    }
+    if _, err := file.Write(data); err != nil {
+        file.Close()
+        os.Remove(tmp)
+        return err
+    }
+    if err := file.Sync(); err != nil {
+        file.Close()
+        os.Remove(tmp)
+        return err
+    }
+    if err := file.Close(); err != nil {
+        os.Remove(tmp)
+        return err
+    }
+    return os.Rename(tmp, path)
+}
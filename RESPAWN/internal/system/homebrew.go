@@ -0,0 +1,40 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HomebrewServiceLabel is the launchd label `brew services` uses for the respawn formula
+const HomebrewServiceLabel = "homebrew.mxcl.respawn"
+
+// HomebrewBinary returns the version-independent path of a Homebrew-installed
+// respawn, <prefix>/opt/respawn/bin/respawn, so a service keeps working across
+// `brew upgrade`. It reports false when respawn wasn't installed by Homebrew.
+func HomebrewBinary() (string, bool) {
+	executablePath, err := os.Executable()
+	if err != nil {
+		return "", false
+	}
+	if resolved, err := filepath.EvalSymlinks(executablePath); err == nil {
+		executablePath = resolved
+	}
+
+	// Running from the Cellar, e.g. /opt/homebrew/Cellar/respawn/1.2.0/bin/respawn
+	if prefix, _, ok := strings.Cut(executablePath, "/Cellar/respawn/"); ok {
+		return filepath.Join(prefix, "opt", "respawn", "bin", "respawn"), true
+	}
+
+	// Installed but run through another path, e.g. a copy in ~/bin
+	for _, prefix := range []string{os.Getenv("HOMEBREW_PREFIX"), "/opt/homebrew", "/usr/local"} {
+		if prefix == "" {
+			continue
+		}
+		path := filepath.Join(prefix, "opt", "respawn", "bin", "respawn")
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
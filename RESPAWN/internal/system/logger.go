@@ -2,6 +2,7 @@ package system
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -29,14 +30,29 @@ type Logger struct {
 
 var GlobalLogger *Logger
 
+// logMirror also receives every log line when set, e.g. stderr for --verbose
+var logMirror io.Writer
 
-// Initialize creates and initializes the global logger 
+// SetLogMirror copies log lines to w as they are written, starting the logger if needed
+func SetLogMirror(w io.Writer) error {
+	logMirror = w
+	if GlobalLogger == nil {
+		return InitLogger()
+	}
+	GlobalLogger.setOutput(GlobalLogger.logFile)
+	return nil
+}
+
+// Initialize creates and initializes the global logger; later calls keep the existing one
 func InitLogger() error {
-	homeDir, err := os.UserHomeDir()
+	if GlobalLogger != nil {
+		return nil
+	}
+
+	logDir, err := LogDir()
 	if err != nil {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
-	logDir := filepath.Join(homeDir, ".respawn", "logs")
 	if err := os.MkdirAll(logDir, 0755); err != nil {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
@@ -84,14 +100,24 @@ func (l *Logger) rotateLogFile(logDir string) error {
 		l.lastLogDate = currentDate
 
 		// Initialize loggers
-		l.debugLogger = log.New(file, "DEBUG: ", log.Ldate|log.Ltime|log.Lshortfile)
-        l.infoLogger = log.New(file, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
-        l.warnLogger = log.New(file, "WARN: ", log.Ldate|log.Ltime|log.Lshortfile)
-        l.errorLogger = log.New(file, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
+		l.setOutput(file)
 	}
 	return nil 
 }
 
+// setOutput points the loggers at the log file and the mirror, if any
+func (l *Logger) setOutput(file *os.File) {
+	var out io.Writer = file
+	if logMirror != nil {
+		out = io.MultiWriter(file, logMirror)
+	}
+
+	l.debugLogger = log.New(out, "DEBUG: ", log.Ldate|log.Ltime|log.Lshortfile)
+	l.infoLogger = log.New(out, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
+	l.warnLogger = log.New(out, "WARN: ", log.Ldate|log.Ltime|log.Lshortfile)
+	l.errorLogger = log.New(out, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
+}
+
 
 // Debug logs debug messages
 func Debug(v ...interface{}) {
@@ -0,0 +1,48 @@
+package system
+
+import (
+	"os"
+	"os/exec"
+	"regexp"
+	"sync"
+)
+
+// Machine identifies the Mac checkpoints are taken on, so a checkpoint directory
+// synced between Macs can tell whose checkpoint is whose
+type Machine struct {
+	Hostname string
+	UUID     string // Hardware UUID, stable across renames and reinstalls; empty when unknown
+}
+
+// platformUUIDPattern matches the hardware UUID in ioreg output
+var platformUUIDPattern = regexp.MustCompile(`"IOPlatformUUID" = "([0-9A-Fa-f-]+)"`)
+
+var (
+	machineOnce sync.Once
+	machine     Machine
+)
+
+// ThisMachine returns the identity of this Mac, looked up once per process
+func ThisMachine() Machine {
+	machineOnce.Do(func() {
+		machine.Hostname, _ = os.Hostname()
+
+		output, err := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+		if err != nil {
+			Warn("Failed to read the hardware UUID:", err)
+			return
+		}
+		if match := platformUUIDPattern.FindSubmatch(output); match != nil {
+			machine.UUID = string(match[1])
+		}
+	})
+	return machine
+}
+
+// IsThisMachine reports whether uuid belongs to this Mac. Checkpoints from before
+// machines were recorded have no UUID and count as local, as does everything when
+// this Mac's UUID is unknown.
+func IsThisMachine(uuid string) bool {
+	local := ThisMachine().UUID
+	return uuid == "" || local == "" || uuid == local
+}
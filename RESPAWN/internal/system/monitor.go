@@ -8,6 +8,7 @@ import (
     "path/filepath"
     "strconv"
     "strings"
+    "sync"
     "time"
 
     "RESPAWN/pkg/config"
@@ -50,35 +51,124 @@ type WorkPattern struct {
 
 
 type OptimizationMetrics struct {
-    CheckpointDurations []time.Duration `json:"checkpoint_durations"`
     RestoreSuccessRate  float64         `json:"restore_success_rate"`
     DiskGrowthRate      float64         `json:"disk_growth_rate_mb_per_week"`
     LastOptimization    time.Time       `json:"last_optimization"`
+    StartupTimings      []StartupTiming `json:"startup_timings,omitempty"` // Most recent daemon startups
+    CheckpointSamples   []CheckpointSample `json:"checkpoint_samples,omitempty"` // Most recent checkpoints, for growth and change rates
+    LastAppSet          string          `json:"last_app_set,omitempty"`  // Apps in the last checkpoint, to spot unchanged ones
+
+    // The user's own settings before an optimization changed them, zero while unchanged.
+    // Optimizations stay within a step of these and go back to them.
+    BaseCheckpointInterval time.Duration `json:"base_checkpoint_interval,omitempty"`
+    BaseCompressionLevel   int           `json:"base_compression_level,omitempty"`
+    LastMaintenance     time.Time       `json:"last_maintenance,omitempty"`
+}
+
+// CheckpointSample records the size of one checkpoint and whether its apps differed from the one before
+type CheckpointSample struct {
+    At      time.Time `json:"at"`
+    Size    int64     `json:"size"`
+    Changed bool      `json:"changed"`
+    Removed bool      `json:"removed,omitempty"` // Checkpoints were deleted, Size is the space freed
 }
 
 type SystemMonitor struct {
+    // mu guards every mutable field below, the monitoring, heartbeat and
+    // learning loops all run in their own goroutines
+    mu                sync.Mutex
+    stopChan          chan struct{}
+    inFlight          sync.WaitGroup // Checkpoints, maintenance and launches still running; see beginWork
+    reloaded          chan struct{}  // Closed and replaced when the config is reloaded
+
     workPattern       *WorkPattern
     metrics           *OptimizationMetrics
     isRunning         bool
     lastHeartbeat     time.Time
     lastCheckpoint    time.Time
+    lastTriggered     time.Time // When the last event-triggered checkpoint started
+    triggerPending    bool      // A debounced checkpoint is scheduled
     processID         int
     baseDir           string
+
+    startTime              time.Time
+    lastCheckpointDuration time.Duration
+    checkpointGate         string // Why checkpoints are currently blocked, empty if they aren't
+    lastSleep              *SleepPeriod // The most recent sleep the daemon woke from
+    wakeHandler            func(period SleepPeriod)
+    restartHandler         func()            // Restores or asks to after a restart
+    snoozeHandler          func(due DueSnooze) // Re-delivers a snoozed notification
+    checkpointHold         string              // Why scheduled checkpoints are held, e.g. an unanswered restore prompt
+    checkpointHoldUntil    time.Time           // When the hold lapses by itself
+
+    // Pause state as last seen in the pause marker
+    paused       bool
+    pausedUntil  time.Time
+    pauseHandler func(paused bool, until time.Time)
+
+    // Battery-aware maintenance
+    lowBattery          bool // On battery below maintenance_min_battery at the last cycle
+    maintenanceDeferred bool // Maintenance came due while on low battery
+    metricsDirty        bool // Metrics changed while saving was held back
+
+    // Handlers wired in by main, system can't import the checkpoint package
+    checkpointHandler  func(trigger string) error
+    maintenanceHandler func() error
+
+    // Optimizations measure compression through the checkpoint package and
+    // announce the config changes they make
+    compressionEstimator func(level int) (float64, error)
+    configChangeHandler  func()
+
+    // Scheduled launches
+    scheduledLaunchHandler func(launch config.ScheduledLaunch) error
+    launchesRun            map[string]string // Launch -> minute it last ran
+    lastScheduleCheck      time.Time         // Launches scheduled after this haven't been looked at
+    quietHours             bool // Inside notification quiet hours at the last check
+    quietHoursChecked      bool
+    quietHoursEndHandler   func()
+
+    // Self-monitoring
+    usageSampler         selfUsageSampler
+    selfUsage            SelfUsage
+    limitStrikes         int
+    resourceLimitHandler func(reason string)
 }
 
-// NewSystemMonitor Creates a new system monitor
-func NewSystemMonitor() (*SystemMonitor, error) {
-    homeDir, err := os.UserHomeDir()
-    if err != nil {
-        return nil, fmt.Errorf("Failed to get home directory: %w", err)
-    }
+// resourceLimitStrikes is how many consecutive over-limit samples trigger the limit handler
+const resourceLimitStrikes = 3
+
+// DaemonState is the snapshot of the running daemon written next to the heartbeat
+type DaemonState struct {
+    PID                    int           `json:"pid"`
+    StartedAt              time.Time     `json:"started_at"`
+    LastHeartbeat          time.Time     `json:"last_heartbeat"`
+    LastCheckpoint         time.Time     `json:"last_checkpoint,omitempty"`
+    LastCheckpointDuration time.Duration `json:"last_checkpoint_duration"`
+    CheckpointGate         string        `json:"checkpoint_gate,omitempty"`
+    SelfUsage              SelfUsage     `json:"self_usage"`
+    LastSleep              *SleepPeriod  `json:"last_sleep,omitempty"`
+}
 
-    baseDir := filepath.Join(homeDir, ".respawn")
+// dataDir is where the monitor keeps its state, the configured data_dir
+func dataDir() string {
+    cfg := config.Current()
+    if cfg == nil {
+        cfg = config.DefaultConfig()
+    }
+    return cfg.DataDir
+}
 
+// NewSystemMonitor Creates a new system monitor
+func NewSystemMonitor() (*SystemMonitor, error) {
     monitor := &SystemMonitor{
 		processID:     os.Getpid(),
-		baseDir:       baseDir,
+		baseDir:       dataDir(),
 		lastHeartbeat: time.Now(),
+		startTime:     time.Now(),
+		stopChan:      make(chan struct{}),
+		reloaded:      make(chan struct{}),
+		launchesRun:   make(map[string]string),
 	}
 
     // Load or create work pattern
@@ -101,7 +191,6 @@ func NewSystemMonitor() (*SystemMonitor, error) {
     // Load optimization metrics
     if err := monitor.loadMetrics(); err != nil {
         monitor.metrics = &OptimizationMetrics{
-            CheckpointDurations: make([]time.Duration, 0),
             RestoreSuccessRate:  1.0,
             DiskGrowthRate:      0.0,
             LastOptimization:    time.Now(),
@@ -113,7 +202,9 @@ func NewSystemMonitor() (*SystemMonitor, error) {
 // Start begins the monitoring process
 func (sm *SystemMonitor) Start() error {
     Info("Starting RESPAWN system monitor")
+    sm.mu.Lock()
     sm.isRunning = true
+    sm.mu.Unlock()
 
     // Check system state on startup
     state := sm.DetectSystemState()
@@ -129,6 +220,7 @@ func (sm *SystemMonitor) Start() error {
     go sm.monitoringLoop()
     go sm.heartbeatLoop()
     go sm.learningLoop()
+    go sm.scheduleLoop()
 
     Info("System monitor started successfully")
     return nil 
@@ -143,34 +235,52 @@ func (sm *SystemMonitor) DetectSystemState() SystemState {
         return StateFirstRun
     }
 
-    // Get system uptime
-    uptime, err := sm.getSystemUptime()
+    // Get last heartbeat
+    heartbeat, err := readHeartbeat(sm.baseDir)
     if err != nil {
-        Warn("Failed to get system uptime:", err)
-        return StateUnknown
-    }
-
-    // Get last heartbeat time
-    lastHeartbeat := sm.getLastHeartbeatTime()
-    if lastHeartbeat.IsZero() {
         Debug("No previous heartbeat found")
         return StateRestart
     }
 
     //Calculate time since last heartbeat
-    timeSinceHeartbeat := time.Since(lastHeartbeat)
+    timeSinceHeartbeat := time.Since(heartbeat.Time)
+
+    // A different boot session means the machine restarted, however long ago
+    currentBoot := BootSessionID()
+    if heartbeat.BootSession != "" && currentBoot != "" && !isBootTimeSession(heartbeat.BootSession) {
+        Debug("Boot session:", currentBoot, "Heartbeat boot session:", heartbeat.BootSession, "Time since last heartbeat:", timeSinceHeartbeat)
+        if heartbeat.BootSession != currentBoot {
+            Info("Restart detected - boot session changed from", heartbeat.BootSession, "to", currentBoot)
+            return StateRestart
+        }
+    } else {
+        // Heartbeats from older versions have no boot session, or a boot time one; compare uptime with the gap
+        uptime, err := sm.getSystemUptime()
+        if err != nil {
+            Warn("Failed to get system uptime:", err)
+            return StateUnknown
+        }
 
-    Debug("System uptime:", uptime, "Time since last heartbeat:", timeSinceHeartbeat)
+        Debug("System uptime:", uptime, "Time since last heartbeat:", timeSinceHeartbeat)
 
-    // Hybrid detection logic
-    if uptime < timeSinceHeartbeat {
-        // System uptime is less than time since last heartbeat = RESTART
-        Info("Restart detected - uptime:", uptime, "<heartbeat gap:", timeSinceHeartbeat)
-        return StateRestart
+        if uptime < timeSinceHeartbeat {
+            // System uptime is less than time since last heartbeat = RESTART
+            Info("Restart detected - uptime:", uptime, "<heartbeat gap:", timeSinceHeartbeat)
+            return StateRestart
+        }
     }
 
-    if timeSinceHeartbeat > 2*time.Hour && uptime > timeSinceHeartbeat {
-        // Long gap but uptime matches = SLEEP
+    // The power management log says whether the gap was a sleep; without it, guess from its length
+    if period, ok := sleepAfter(heartbeat.Time); ok {
+        if period != nil {
+            Info("Sleep cycle detected -", period)
+            sm.mu.Lock()
+            sm.lastSleep = period
+            sm.mu.Unlock()
+            return StateSleep
+        }
+    } else if timeSinceHeartbeat > 2*time.Hour {
+        // Long gap within the same boot = SLEEP
         Info("Sleep cycle detected - long heartbeat gap but matching uptime")
         return StateSleep
     }
@@ -215,15 +325,20 @@ func (sm *SystemMonitor) handleSystemState(state SystemState) error {
 
 // monitoringLoop runs the main monitoring cycle 
 func (sm *SystemMonitor) monitoringLoop() {
+    defer RecordPanics()
     Debug("Starting monitoring loop")
 
-    ticker := time.NewTicker(10 * time.Minute) // check every 10 minutes
+    ticker := time.NewTicker(config.Current().MonitorInterval)
     defer ticker.Stop()
 
-    for sm.isRunning {
+    for {
         select {
         case <-ticker.C: 
             sm.performMonitoringCycle()
+        case <-sm.reloadSignal():
+            ticker.Reset(config.Current().MonitorInterval)
+        case <-sm.stopChan:
+            return
         }
     }
 }
@@ -235,13 +350,15 @@ func (sm *SystemMonitor) performMonitoringCycle() {
     // Update learning patterns
     sm.updateLearningData()
 
+    lowBattery := sm.updatePowerState()
+    paused := sm.CheckPause()
+    sm.deliverSnoozes()
+
     // Check if checkpoint is needed 
-    if sm.shouldCreateCheckpoint() {
+    if !paused && sm.shouldCreateCheckpoint() {
         Debug("Checkpoint needed! - creating now")
-        // Note: This would call checkpoint manager from main.go
-        // For now, Just Log
         Info("Checkpoint creation triggered")
-
+        sm.runCheckpoint(config.TriggerScheduled)
     }
 
     // CHECK FOR OPTIMIZATIONS
@@ -249,20 +366,198 @@ func (sm *SystemMonitor) performMonitoringCycle() {
         Debug("Running optimization check")
         sm.checkAndApplyOptimizations()
     }
-    // Perform maintenance
-    if sm.shouldRunMaintenance() {
-        Debug("Running maintenance tasks")
+    // Perform maintenance, holding it back until power returns when the battery is low
+    sm.mu.Lock()
+    deferred := sm.maintenanceDeferred
+    sm.mu.Unlock()
+
+    if deferred || sm.shouldRunMaintenance() {
+        if lowBattery {
+            if !deferred {
+                Info("Low battery - deferring maintenance until power returns")
+            }
+            sm.mu.Lock()
+            sm.maintenanceDeferred = true
+            sm.mu.Unlock()
+        } else {
+            sm.runMaintenance()
+        }
+    }
+}
+
+// runMaintenance runs the maintenance handler and rotates the daemon's logs
+func (sm *SystemMonitor) runMaintenance() {
+    Debug("Running maintenance tasks")
+
+    Info("Maintenance tasks triggered")
+    sm.mu.Lock()
+    maintenance := sm.maintenanceHandler
+    sm.maintenanceDeferred = false
+    sm.mu.Unlock()
+
+    if maintenance == nil || !sm.beginWork() {
+        return
+    }
+    defer sm.inFlight.Done()
+
+    if err := maintenance(); err != nil {
+        Warn("Maintenance failed:", err)
+    }
+    if err := RotateStdLogs(); err != nil {
+        Warn("Log rotation failed:", err)
+    }
+
+    sm.mu.Lock()
+    sm.metrics.LastMaintenance = time.Now()
+    sm.persistMetrics()
+    sm.mu.Unlock()
+}
+
+// SetCheckpointHandler sets the function the monitor calls to create a checkpoint
+func (sm *SystemMonitor) SetCheckpointHandler(handler func(trigger string) error) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.checkpointHandler = handler
+}
+
+// SetMaintenanceHandler sets the function the monitor calls to run maintenance
+func (sm *SystemMonitor) SetMaintenanceHandler(handler func() error) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.maintenanceHandler = handler
+}
+
+// runCheckpoint creates a checkpoint through the handler and records how long it took
+func (sm *SystemMonitor) runCheckpoint(trigger string) {
+    sm.mu.Lock()
+    handler := sm.checkpointHandler
+    sm.mu.Unlock()
+
+    if handler == nil {
+        Warn("No checkpoint handler set - skipping checkpoint")
+        return
+    }
+    if sm.CheckPause() {
+        Info("Monitoring paused - skipping checkpoint")
+        return
+    }
+    if !sm.beginWork() {
+        return // Shutting down
+    }
+    defer sm.inFlight.Done()
+
+    start := time.Now()
+    if err := handler(trigger); err != nil {
+        Error("Checkpoint creation failed:", err)
+        return
+    }
+
+    duration := time.Since(start)
+    sm.mu.Lock()
+    sm.lastCheckpoint = time.Now()
+    sm.lastCheckpointDuration = duration
+    sm.mu.Unlock()
+
+    Info("Checkpoint created in", duration)
+    sm.writeDaemonState()
+}
+
+// TriggerCheckpoint creates a checkpoint outside the schedule and resource gates, for
+// events such as SIGUSR1. A trigger within trigger_debounce of the last checkpoint is
+// coalesced with any others into one checkpoint at the end of that window, so a burst
+// of events writes at most one extra snapshot, taken after the burst settles and
+// tagged with the first event's trigger. A pre-sleep trigger is never debounced: the
+// machine sleeps right after it, so a deferred checkpoint would be taken after wake.
+// Shutdown checkpoints don't come through here, they are created directly.
+func (sm *SystemMonitor) TriggerCheckpoint(trigger string) {
+    window := config.DefaultConfig().TriggerDebounce
+    if config.Current() != nil {
+        window = config.Current().TriggerDebounce
+    }
+
+    sm.mu.Lock()
+    if trigger == config.TriggerPreSleep {
+        sm.lastTriggered = time.Now()
+        sm.mu.Unlock()
+        sm.runCheckpoint(trigger)
+        return
+    }
+    if sm.triggerPending {
+        sm.mu.Unlock()
+        Debug("Checkpoint trigger coalesced with the pending one")
+        return
+    }
+
+    last := sm.lastCheckpoint
+    if sm.lastTriggered.After(last) {
+        last = sm.lastTriggered
+    }
+    wait := window - time.Since(last)
+    if wait <= 0 {
+        sm.lastTriggered = time.Now()
+        sm.mu.Unlock()
+        sm.runCheckpoint(trigger)
+        return
+    }
+    sm.triggerPending = true
+    debounced := sm.lastTriggered
+    sm.mu.Unlock()
+
+    Info("Checkpoint trigger debounced - checkpointing in", wait.Round(time.Second))
+    time.AfterFunc(wait, func() {
+        sm.mu.Lock()
+        sm.triggerPending = false
+        covered := sm.lastTriggered.After(debounced) // A pre-sleep checkpoint ran meanwhile
+        sm.lastTriggered = time.Now()
+        sm.mu.Unlock()
+        if covered {
+            Debug("Debounced checkpoint trigger covered by a pre-sleep checkpoint")
+            return
+        }
+        sm.runCheckpoint(trigger)
+    })
+}
 
-        // Note: This would call checkpoint manager from main.go
-        Info("Maintenance tasks triggered")
-        
+// HoldCheckpoints stops scheduled checkpoints until ReleaseCheckpoints, so they don't
+// crowd out a checkpoint the user is still being asked about. The hold lapses after
+// max, so a prompt nobody returns to can't stop checkpoints for good.
+func (sm *SystemMonitor) HoldCheckpoints(reason string, max time.Duration) {
+    sm.mu.Lock()
+    sm.checkpointHold = reason
+    sm.checkpointHoldUntil = time.Now().Add(max)
+    sm.mu.Unlock()
+    Info("Holding scheduled checkpoints for at most", max, "-", reason)
+}
+
+// ReleaseCheckpoints lets scheduled checkpoints run again after HoldCheckpoints
+func (sm *SystemMonitor) ReleaseCheckpoints() {
+    sm.mu.Lock()
+    held := sm.checkpointHold != ""
+    sm.checkpointHold = ""
+    sm.mu.Unlock()
+    if held {
+        Info("Scheduled checkpoints released")
     }
 }
 
 // shouldCreateCheckpoint determines if a checkpoint should be created
 func (sm *SystemMonitor) shouldCreateCheckpoint() bool {
     // This function checks if enough time has passed
+    sm.mu.Lock()
     timeSinceLastCheckpoint := time.Since(sm.lastCheckpoint)
+    hold := sm.checkpointHold
+    lapsed := hold != "" && time.Now().After(sm.checkpointHoldUntil)
+    sm.mu.Unlock()
+
+    if lapsed {
+        Info("Checkpoint hold lapsed:", hold)
+        sm.ReleaseCheckpoints()
+        hold = ""
+    }
+    if hold != "" {
+        sm.setCheckpointGate(hold)
+        return false
+    }
     // This method gets optimal interval based on learned patterns
     optimalInterval := sm.getOptimalCheckpointInterval()
 
@@ -279,24 +574,29 @@ func (sm *SystemMonitor) shouldCreateCheckpoint() bool {
     //This method checks User Activity
     if sm.isUserInIntensiveWork() {
         Debug("User in intensive work - delay checkpoint processing")
+        sm.setCheckpointGate("intensive user activity")
         return false
     }
 
+    sm.setCheckpointGate("")
     return true 
 }
 
 // This method called getOptimalCheckpointInterval calculates optimal checkpoint interval based on learned pattern
 func (sm *SystemMonitor) getOptimalCheckpointInterval() time.Duration {
-    baseInterval := config.GlobalConfig.CheckpointInterval
+    baseInterval := config.Current().CheckpointInterval
+
+    sm.mu.Lock()
+    learningComplete := sm.workPattern.IsLearningComplete
+    workHours := sm.isWorkHours(time.Now().Hour())
+    sm.mu.Unlock()
 
-    if !sm.workPattern.IsLearningComplete {
+    if !learningComplete {
         return baseInterval // Use default during learning
     }
 
-    currentHour := time.Now().Hour()
-
     // During work hours (learned pattern), use longer intervals
-    if sm.isWorkHours(currentHour) {
+    if workHours {
         userActivity := sm.getCurrentUserActivity()
         switch userActivity {
         case ActivityIntensive:
@@ -319,6 +619,7 @@ func (sm *SystemMonitor) isSystemResourcesSafe() bool {
         Warn("Failed to get CPU usage:", err)
     } else if cpuUsage > 70.0 {
         Debug("High CPU usage detected:", cpuUsage, "% -  skipping checkpoint")
+        sm.setCheckpointGate(fmt.Sprintf("high CPU (%.0f%%)", cpuUsage))
         return false
     }
 
@@ -328,22 +629,82 @@ func (sm *SystemMonitor) isSystemResourcesSafe() bool {
         Warn("Failed to get battery level:", err)
     } else if batteryLevel <= 15 && !sm.isPowerConnected() {
         Debug("Low battery detected:", batteryLevel, "% - skipping checkpoint")
+        sm.setCheckpointGate(fmt.Sprintf("low battery (%d%%)", batteryLevel))
         return false
     }
 
     return true
 }
 
+// SetPauseHandler sets the function told when monitoring is paused or resumed
+func (sm *SystemMonitor) SetPauseHandler(handler func(paused bool, until time.Time)) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.pauseHandler = handler
+}
+
+// CheckPause reads the pause marker and reports whether checkpoints are paused. A
+// change since the last check is logged, shown in the daemon state and passed to
+// the pause handler; the pause and resume commands signal the daemon to check at once.
+func (sm *SystemMonitor) CheckPause() bool {
+    until, paused := PausedUntil()
+
+    sm.mu.Lock()
+    changed := paused != sm.paused || !until.Equal(sm.pausedUntil)
+    previousGate := pauseGate(sm.pausedUntil)
+    sm.paused = paused
+    sm.pausedUntil = until
+    if paused {
+        sm.checkpointGate = pauseGate(until)
+    } else if changed && sm.checkpointGate == previousGate {
+        // Only the pause's own gate lifts; a hold stays, other gates are re-checked next cycle
+        sm.checkpointGate = sm.checkpointHold
+    }
+    handler := sm.pauseHandler
+    sm.mu.Unlock()
+
+    if !changed {
+        return paused
+    }
+
+    if paused {
+        Info("Monitoring", pauseGate(until))
+    } else {
+        Info("Monitoring resumed")
+    }
+    sm.writeDaemonState()
+    if handler != nil {
+        handler(paused, until)
+    }
+    return paused
+}
+
+// setCheckpointGate records why checkpoints are blocked, empty when they aren't
+func (sm *SystemMonitor) setCheckpointGate(gate string) {
+    sm.mu.Lock()
+    sm.checkpointGate = gate
+    sm.mu.Unlock()
+}
+
 //This updateLearningData updates work pattern learning data
 func (sm *SystemMonitor) updateLearningData() {
-    if sm.workPattern.IsLearningComplete {
+    sm.mu.Lock()
+    learningComplete := sm.workPattern.IsLearningComplete
+    sm.mu.Unlock()
+
+    if learningComplete {
         return // Learning complete, no need to update
     }
 
     currentHour := time.Now().Hour()
 
-    
-    if cpuUsage, err := sm.getCPUUsage(); err == nil {
+    // Sample outside the lock, top takes about a second
+    cpuUsage, cpuErr := sm.getCPUUsage()
+
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+
+    if cpuErr == nil {
         sm.workPattern.CPUPatterns[currentHour] = cpuUsage
     }
 
@@ -355,7 +716,8 @@ func (sm *SystemMonitor) updateLearningData() {
     sm.saveWorkPattern()
 }
 
-// completeLearning finalizes the learning process and determines top 3 apps
+// completeLearning finalizes the learning process and determines top 3 apps.
+// The caller must hold sm.mu.
 func (sm *SystemMonitor) completeLearning() {
     Info("Completing 1-month learning period")
 
@@ -386,7 +748,7 @@ func (sm *SystemMonitor) completeLearning() {
     }
 
     sm.workPattern.TopThreeApps = make ([]string, topCount)
-    for i := 0; 1 < topCount; i++ {
+    for i := 0; i < topCount; i++ {
         sm.workPattern.TopThreeApps[i] = usage[i].name
     }
 
@@ -396,139 +758,314 @@ func (sm *SystemMonitor) completeLearning() {
     Info("Top 3 apps:", strings.Join(sm.workPattern.TopThreeApps, ", "))
 }
 
-// checkAndApplyOptimizations method checks for and applies performance optimizations
+// RecordAppUsage counts the apps seen in a checkpoint towards the learned usage frequency
+func (sm *SystemMonitor) RecordAppUsage(apps []string) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+
+    if sm.workPattern.AppUsageFrequency == nil {
+        sm.workPattern.AppUsageFrequency = make(map[string]int)
+    }
+    for _, app := range apps {
+        sm.workPattern.AppUsageFrequency[app]++
+    }
+    sm.saveWorkPattern()
+}
+
+// checkAndApplyOptimizations method checks for performance optimizations, applying
+// them only when auto_optimize is on
 func (sm *SystemMonitor) checkAndApplyOptimizations() {
     optimizations := sm.generateOptimizations()
+    apply := config.Current() != nil && config.Current().AutoOptimize
 
     for _, opt := range optimizations {
-        if opt.ImprovementPercent > 20.0 {
+        switch {
+        case !apply:
+            Info("Optimization available (set auto_optimize to apply):", opt.Description, "Improvement:", opt.ImprovementPercent, "%")
+        case opt.Reverts || opt.ImprovementPercent > 20.0:
             Info("Auto-applying optimizations:", opt.Description)
             if err := opt.Apply(); err != nil {
                 Error("Failed to apply optimization:", err)
-            } else {
-                sm.metrics.LastOptimization = time.Now()
-                sm.saveMetrics()
             }
-        } else {
+        default:
             Info("Optimization available:", opt.Description, "Improvement:", opt.ImprovementPercent, "%")
         }
     }
+
+    // Checked, whether or not anything changed, so the estimates don't run every cycle
+    sm.mu.Lock()
+    sm.metrics.LastOptimization = time.Now()
+    sm.persistMetrics()
+    sm.mu.Unlock()
 }
 // Helper functions for system information
 
 // getSystemUptime returns system uptime duration
 func (sm *SystemMonitor) getSystemUptime() (time.Duration, error) {
-    cmd := exec.Command("sysctl", "-n", "kern.boottime")
-    output, err := cmd.Output()
+    boot, err := BootTime()
     if err != nil {
-        return 2 * time.Hour, err
+        return 0, err
     }
+    return time.Since(boot), nil
+}
 
-    outputStr := string(output)
-    Debug("Boot time output:", outputStr)
-
-    // Parse uptime output(simplified - real implementation would be more robust)
-    return 2 * time.Hour, nil 
-}   
-
-// getCPUUsage returns current CPU usage percentage
+// getCPUUsage returns the system-wide CPU usage percentage. top's first sample
+// covers too short a span to mean much, so it takes two a second apart.
 func (sm *SystemMonitor) getCPUUsage() (float64, error) {
-    // TODO: Real implementation needed
-    cmd := exec.Command("top", "-l", "1", "-n", "0")
-    output, err := cmd.Output()
+    output, err := exec.Command("top", "-l", "2", "-s", "1", "-n", "0").Output()
     if err != nil {
-        return 25.5, err
+        return 0, err
     }
+    return parseCPUUsage(string(output))
+}
 
-    lines := strings.Split(string(output), "\n")
-    for _, line := range lines {
-        if strings.Contains(line, "CPU usage:") {
-            // Parse CPU usage from top output
-            Debug("CPU line:", line)
-            // Simplified parsing - real implementation would be more robust
-            return 25.5, nil // Placeholder
+// parseCPUUsage reads the busy percentage from the last "CPU usage:" line of top
+// output, e.g. "CPU usage: 7.45% user, 9.87% sys, 82.66% idle"
+func parseCPUUsage(output string) (float64, error) {
+    lines := strings.Split(output, "\n")
+    for i := len(lines) - 1; i >= 0; i-- {
+        line := lines[i]
+        if !strings.HasPrefix(line, "CPU usage:") {
+            continue
         }
+        for _, field := range strings.Split(strings.TrimPrefix(line, "CPU usage:"), ",") {
+            field = strings.TrimSpace(field)
+            if !strings.HasSuffix(field, "% idle") {
+                continue
+            }
+            idle, err := strconv.ParseFloat(strings.TrimSuffix(field, "% idle"), 64)
+            if err != nil {
+                return 0, fmt.Errorf("unexpected CPU usage line %q", line)
+            }
+            return 100 - idle, nil
+        }
+        return 0, fmt.Errorf("unexpected CPU usage line %q", line)
     }
-
-    return 25.5, nil
+    return 0, fmt.Errorf("no CPU usage in top output")
 }
 
 // getBatteryLevel returns current battery percentage
 func (sm *SystemMonitor) getBatteryLevel() (int, error) {
-    // TODO: Real implementation needed    
-    cmd := exec.Command("pmset", "-g", "batt")
-    output, err := cmd.Output()
-    if err != nil {
-        return 75, err
+    return BatteryLevel()
+}
+
+// isPowerConnected checks if power adapter is connected
+func (sm *SystemMonitor) isPowerConnected() bool {
+    return PowerConnected()
+}
+
+// Background loops
+func (sm *SystemMonitor) heartbeatLoop() {
+    defer RecordPanics()
+    ticker := time.NewTicker(config.Current().HeartbeatInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ticker.C:
+            sm.checkForWake()
+            sm.checkSelfUsage()
+            sm.updateHeartbeat()
+        case <-sm.reloadSignal():
+            ticker.Reset(config.Current().HeartbeatInterval)
+        case <-sm.stopChan:
+            return
+        }
+    }   
+}
+
+// SetWakeHandler sets the function told about each sleep the daemon wakes from
+func (sm *SystemMonitor) SetWakeHandler(handler func(period SleepPeriod)) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.wakeHandler = handler
+}
+
+// SetRestartHandler sets the function run when the daemon starts after a restart
+func (sm *SystemMonitor) SetRestartHandler(handler func()) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.restartHandler = handler
+}
+
+// SetSnoozeHandler sets the function that re-delivers a snoozed notification once
+// its snooze runs out
+func (sm *SystemMonitor) SetSnoozeHandler(handler func(due DueSnooze)) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.snoozeHandler = handler
+}
+
+// deliverSnoozes hands the notifications whose snooze ran out to the snooze handler.
+// Prompts may wait on the user, so they run outside the monitoring cycle.
+func (sm *SystemMonitor) deliverSnoozes() {
+    sm.mu.Lock()
+    handler := sm.snoozeHandler
+    sm.mu.Unlock()
+    if handler == nil {
+        return
     }
 
-    lines := strings.Split(string(output), "\n")
-    for _, line := range lines {
-        if strings.Contains(line, "%") {
-            Debug("Battery line:", line)
-            // Extract percentage (simplified)
-            return 75, nil // placeholder
+    for _, due := range dueSnoozes(time.Now()) {
+        if !sm.beginWork() {
+            return
         }
+        Info("Snooze over, re-delivering", due.Kind)
+        go func(due DueSnooze) {
+            defer RecordPanics()
+            defer sm.inFlight.Done()
+            handler(due)
+        }(due)
+    }
+}
+
+// checkForWake looks for a sleep in the power management log when heartbeats stopped
+// for a while. The gap is measured on the wall clock: Go's monotonic clock stands still
+// while a Mac sleeps.
+func (sm *SystemMonitor) checkForWake() {
+    sm.mu.Lock()
+    last := sm.lastHeartbeat.Round(0)
+    sm.mu.Unlock()
+
+    if time.Now().Round(0).Sub(last) < 2*config.Current().HeartbeatInterval {
+        return
     }
 
-    return 75, nil
+    period, _ := sleepAfter(last)
+    if period == nil {
+        return
+    }
+    Info("Woke from sleep -", period)
+    sm.mu.Lock()
+    sm.lastSleep = period
+    sm.mu.Unlock()
+    sm.notifyWake()
 }
 
-// isPowerConnected checks if power adapter is connected
-func (sm *SystemMonitor) isPowerConnected() bool {
-    cmd := exec.Command("pmset", "-g", "ps")
-    output, err := cmd.Output()
-    if err != nil {
-        return false
+// notifyWake passes the last sleep to the wake handler
+func (sm *SystemMonitor) notifyWake() {
+    sm.mu.Lock()
+    period := sm.lastSleep
+    handler := sm.wakeHandler
+    sm.mu.Unlock()
+
+    if period != nil && handler != nil {
+        handler(*period)
     }
+}
 
-    return strings.Contains(string(output), "AC Power")
+// SetResourceLimitHandler sets the function called when RESPAWN keeps exceeding its own limits
+func (sm *SystemMonitor) SetResourceLimitHandler(handler func(reason string)) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.resourceLimitHandler = handler
 }
 
-// Background loops
-func (sm *SystemMonitor) heartbeatLoop() {
-    ticker := time.NewTicker(1 * time.Minute)
-    defer ticker.Stop()
+// checkSelfUsage samples RESPAWN's own RSS and CPU and enforces the configured limits
+func (sm *SystemMonitor) checkSelfUsage() {
+    usage, err := sm.usageSampler.Sample()
+    if err != nil {
+        Debug("Failed to sample own resource usage:", err)
+        return
+    }
+    Debug("Self usage - RSS:", usage.RSSMB, "MB CPU:", fmt.Sprintf("%.1f%%", usage.CPUPercent))
 
-    for sm.isRunning {
-        <-ticker.C
-        sm.updateHeartbeat()
-    }   
+    var reason string
+    if usage.RSSMB > int64(config.Current().MaxMemoryMB) {
+        reason = fmt.Sprintf("memory %d MB exceeds limit of %d MB", usage.RSSMB, config.Current().MaxMemoryMB)
+    } else if usage.CPUPercent > config.Current().MaxCPUPercent {
+        reason = fmt.Sprintf("CPU %.1f%% exceeds limit of %.1f%%", usage.CPUPercent, config.Current().MaxCPUPercent)
+    }
+
+    sm.mu.Lock()
+    sm.selfUsage = usage
+    if reason == "" {
+        sm.limitStrikes = 0
+    } else {
+        sm.limitStrikes++
+    }
+    strikes := sm.limitStrikes
+    handler := sm.resourceLimitHandler
+    sm.mu.Unlock()
+
+    if reason == "" {
+        return
+    }
+    Warn("RESPAWN resource usage high:", reason)
+
+    if strikes >= resourceLimitStrikes && config.Current().RestartOnResourceLimit && handler != nil {
+        Error("Resource limit exceeded", strikes, "times in a row - restarting")
+        handler(reason)
+    }
 }
 
 func (sm *SystemMonitor) learningLoop() {
-    ticker := time.NewTicker(1 * time.Hour)
+    defer RecordPanics()
+    ticker := time.NewTicker(config.Current().LearningInterval)
     defer ticker.Stop()
 
-    for sm.isRunning {
-        <-ticker.C
-        sm.updateLearningData()
+    for {
+        select {
+        case <-ticker.C:
+            sm.updateLearningData()
+        case <-sm.reloadSignal():
+            ticker.Reset(config.Current().LearningInterval)
+        case <-sm.stopChan:
+            return
+        }
     }
 }
 
 func (sm *SystemMonitor) updateHeartbeat() {
-    sm.lastHeartbeat = time.Now()
-    heartbeatFile := filepath.Join(sm.baseDir, "heartbeat")
-    os.WriteFile(heartbeatFile, []byte(sm.lastHeartbeat.Format(time.RFC3339)), 0644)
+    heartbeat := currentHeartbeat(sm.processID)
+    sm.mu.Lock()
+    sm.lastHeartbeat = heartbeat.Time
+    sm.mu.Unlock()
+
+    if err := writeHeartbeat(sm.baseDir, heartbeat); err != nil {
+        Warn("Failed to write heartbeat:", err)
+    }
+    sm.writeDaemonState()
 }
 
-func (sm *SystemMonitor) getLastHeartbeatTime() time.Time {
-    heartbeatFile := filepath.Join(sm.baseDir, "heartbeat")
-    data, err := os.ReadFile(heartbeatFile)
+// writeDaemonState saves the daemon snapshot read by 'respawn status'
+func (sm *SystemMonitor) writeDaemonState() {
+    sm.mu.Lock()
+    state := DaemonState{
+        PID:                    sm.processID,
+        StartedAt:              sm.startTime,
+        LastHeartbeat:          sm.lastHeartbeat,
+        LastCheckpoint:         sm.lastCheckpoint,
+        LastCheckpointDuration: sm.lastCheckpointDuration,
+        CheckpointGate:         sm.checkpointGate,
+        SelfUsage:              sm.selfUsage,
+        LastSleep:              sm.lastSleep,
+    }
+    sm.mu.Unlock()
+
+    data, err := json.MarshalIndent(state, "", "  ")
     if err != nil {
-        return time.Time{}    
+        return
     }
+    writeFileAtomic(filepath.Join(sm.baseDir, "daemon-state.json"), data, 0644)
+}
 
-    t, err := time.Parse(time.RFC3339, string(data))
+// LoadDaemonState reads the snapshot written by the running daemon
+func LoadDaemonState() (*DaemonState, error) {
+    data, err := os.ReadFile(filepath.Join(dataDir(), "daemon-state.json"))
     if err != nil {
-        return time.Time{}
+        return nil, err
     }
 
-    return t
+    var state DaemonState
+    if err := json.Unmarshal(data, &state); err != nil {
+        return nil, err
+    }
+    return &state, nil
 }
 
 func (sm *SystemMonitor) isFirstRun() bool {
-    heartbeatFile := filepath.Join(sm.baseDir, "heartbeat")
+    heartbeatFile := filepath.Join(sm.baseDir, heartbeatFileName)
     _, err := os.Stat(heartbeatFile)
     return os.IsNotExist(err)
 }
@@ -550,6 +1087,7 @@ func (sm *SystemMonitor) wasProcessRunning() bool {
     return err == nil
 }
 
+// isWorkHours reports whether hour falls in the learned work hours. The caller must hold sm.mu.
 func (sm *SystemMonitor) isWorkHours(hour int) bool {
     if sm.workPattern.StartHour <= sm.workPattern.EndHour {
         return hour >= sm.workPattern.StartHour && hour <= sm.workPattern.EndHour
@@ -566,11 +1104,44 @@ func (sm *SystemMonitor) isUserInIntensiveWork() bool {
 }
 
 func (sm *SystemMonitor) shouldRunOptimizations() bool {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
     return time.Since(sm.metrics.LastOptimization) > 24*time.Hour
 }
 
+// Maintenance scheduling without a window, and the catch-up for Macs that are
+// never awake during it
+const (
+    maintenanceInterval = 6 * time.Hour
+    maintenanceOverdue  = 72 * time.Hour
+)
+
+// shouldRunMaintenance reports whether maintenance is due: once per maintenance
+// window, or every maintenanceInterval when no window is set
 func (sm *SystemMonitor) shouldRunMaintenance() bool {
-    return time.Since(sm.lastCheckpoint) > 6*time.Hour
+    sm.mu.Lock()
+    if sm.metrics.LastMaintenance.IsZero() {
+        sm.metrics.LastMaintenance = time.Now() // Start the clock instead of running on first launch
+    }
+    sinceLast := time.Since(sm.metrics.LastMaintenance)
+    sm.mu.Unlock()
+
+    window := config.Current().MaintenanceWindow
+    if window == "" {
+        return sinceLast > maintenanceInterval
+    }
+
+    if window.Contains(time.Now()) {
+        // Already ran during this occurrence of the window
+        return sinceLast > window.Length()
+    }
+
+    // The window keeps being missed, e.g. the Mac sleeps overnight
+    if sinceLast > maintenanceOverdue {
+        Info("Maintenance overdue - running outside the maintenance window")
+        return true
+    }
+    return false
 }
 // State handlers
 
@@ -581,20 +1152,33 @@ func (sm *SystemMonitor) createInitialCheckpoint() error {
 }
 
 func (sm *SystemMonitor) handleSystemRestart() error {
-    // Placeholder for system restart handling logic
     Info("Handling system restart...")
+    sm.mu.Lock()
+    handler := sm.restartHandler
+    sm.mu.Unlock()
+
+    // The handler may wait on the user, so it mustn't hold up the monitor starting
+    if handler != nil && sm.beginWork() {
+        go func() {
+            defer RecordPanics()
+            defer sm.inFlight.Done()
+            handler()
+        }()
+    }
     return nil
 }
 
 func (sm *SystemMonitor) updateAfterSleep() error {
-    // Placeholder for updating after sleep logic
     Info("Updating after sleep...")
+    sm.notifyWake()
     sm.updateHeartbeat()
     return nil 
 }
 
 func (sm *SystemMonitor) handleCrashRecovery() error {
     Warn("Resuming normal operation")
+    // Capture the workspace now rather than waiting out the interval missed while down
+    sm.runCheckpoint(config.TriggerCrashRecovery)
     return nil
 }
 
@@ -622,17 +1206,13 @@ func (sm *SystemMonitor) stateToString(state SystemState) string {
 type Optimization struct {
     Description         string
     ImprovementPercent  float64
+    Reverts             bool // Goes back to the user's own setting, whatever the improvement
     Apply           func() error                                   
 }
 
-func (sm *SystemMonitor) generateOptimizations() []Optimization {
-    // Implementation for optimization generation
-    return []Optimization{}
-}
-
 // Persistence functions
 
-// saveWorkPattern saves work pattern to file
+// saveWorkPattern saves work pattern to file. The caller must hold sm.mu once the monitor is running.
 func (sm *SystemMonitor) saveWorkPattern() error {
     filePath := filepath.Join(sm.baseDir, "work-pattern.json")
     data, err := json.MarshalIndent(sm.workPattern, "", " ")
@@ -642,6 +1222,15 @@ func (sm *SystemMonitor) saveWorkPattern() error {
     return os.WriteFile(filePath, data, 0644)
 }
 
+// LoadWorkPattern reads the learned work pattern without starting a monitor
+func LoadWorkPattern() (*WorkPattern, error) {
+    sm := &SystemMonitor{baseDir: dataDir()}
+    if err := sm.loadWorkPattern(); err != nil {
+        return nil, err
+    }
+    return sm.workPattern, nil
+}
+
 // loadWorkPattern loads work pattern from file
 func (sm *SystemMonitor) loadWorkPattern() error {
     filePath := filepath.Join(sm.baseDir, "work-pattern.json")
@@ -674,10 +1263,80 @@ func (sm *SystemMonitor) loadMetrics() error {
 	return json.Unmarshal(data, sm.metrics)
 }
 
+// IsRunning reports whether the monitor loops are active
+func (sm *SystemMonitor) IsRunning() bool {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    return sm.isRunning
+}
+
 // Stop stops the monitoring process
 func (sm *SystemMonitor) Stop() {
     Info("Stopping system monitor")
-    sm.isRunning = false
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+
+    if sm.isRunning {
+        sm.isRunning = false
+        close(sm.stopChan)
+    }
+}
+
+// ApplyConfig makes the loops pick up intervals from a reloaded config
+func (sm *SystemMonitor) ApplyConfig() {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+
+    close(sm.reloaded)
+    sm.reloaded = make(chan struct{})
+}
+
+// reloadSignal returns the channel closed on the next config reload
+func (sm *SystemMonitor) reloadSignal() <-chan struct{} {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    return sm.reloaded
+}
+
+// beginWork registers an operation that shutdown must wait for. It returns false
+// once the monitor is stopping; otherwise the caller must call sm.inFlight.Done().
+func (sm *SystemMonitor) beginWork() bool {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+
+    if !sm.isRunning {
+        return false
+    }
+    sm.inFlight.Add(1)
+    return true
+}
+
+// Shutdown stops the monitor, waits up to timeout for in-flight checkpoints,
+// maintenance and launches, then writes metrics and a final heartbeat. It reports
+// whether everything finished in time.
+func (sm *SystemMonitor) Shutdown(timeout time.Duration) bool {
+    sm.Stop()
+
+    done := make(chan struct{})
+    go func() {
+        sm.inFlight.Wait()
+        close(done)
+    }()
+
+    finished := true
+    select {
+    case <-done:
+    case <-time.After(timeout):
+        Warn("Shutdown timed out after", timeout, "with work still in flight")
+        finished = false
+    }
+
+    sm.mu.Lock()
+    sm.saveMetrics() // Even on battery, nothing runs later to write them
+    sm.mu.Unlock()
+    sm.updateHeartbeat()
+
+    return finished
 }
 
 
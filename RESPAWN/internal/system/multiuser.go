@@ -0,0 +1,65 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Several people can use RESPAWN on one Mac. Each gets their own LaunchAgent
+// label, data directory, PID file and API socket; these helpers keep one user's
+// daemon from touching another's state.
+
+// legacyLaunchAgentLabel is the label used before labels were made per-user
+const legacyLaunchAgentLabel = "com.respawn.agent"
+
+// ErrSharedDataDir means the data directory belongs to another user, e.g. a shared
+// install location both users' daemons would fight over
+var ErrSharedDataDir = errors.New("data directory belongs to another user")
+
+// LaunchAgentLabel returns the launchd label for the current user's agent,
+// e.g. com.respawn.agent.alice
+func LaunchAgentLabel() string {
+	name := strconv.Itoa(os.Getuid())
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+
+	// launchd labels are reverse-DNS, keep the suffix to safe characters
+	suffix := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		}
+		return '-'
+	}, name)
+	return legacyLaunchAgentLabel + "." + suffix
+}
+
+// CheckDataDirOwner returns ErrSharedDataDir when dir is owned by a different user
+// than the one running RESPAWN. A missing directory passes, it will be created.
+func CheckDataDirOwner(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+
+	uid := int(stat.Uid)
+	if uid == os.Getuid() {
+		return nil
+	}
+
+	owner := strconv.Itoa(uid)
+	if u, err := user.LookupId(owner); err == nil {
+		owner = u.Username
+	}
+	return fmt.Errorf("%w: %s is owned by %s - set data_dir to a directory in your home folder", ErrSharedDataDir, dir, owner)
+}
@@ -0,0 +1,60 @@
+package system
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// unmeteredPorts are the hardware ports treated as unmetered. Tethering shows up as
+// "iPhone USB" or "Bluetooth PAN" and is left out.
+var unmeteredPorts = []string{"Wi-Fi", "Ethernet", "Thunderbolt"}
+
+// OnUnmeteredNetwork reports whether the default route goes through Wi-Fi or a wired
+// port. It is false without a network, so work waiting for it waits for a connection.
+func OnUnmeteredNetwork() bool {
+	output, err := exec.Command("route", "-n", "get", "default").Output()
+	if err != nil {
+		return false
+	}
+	device := routeInterface(string(output))
+	if device == "" {
+		return false
+	}
+
+	ports, err := exec.Command("networksetup", "-listallhardwareports").Output()
+	if err != nil {
+		return false
+	}
+	port := hardwarePort(string(ports), device)
+	for _, unmetered := range unmeteredPorts {
+		if strings.Contains(port, unmetered) {
+			return true
+		}
+	}
+	return false
+}
+
+// routeInterface picks the interface out of `route -n get default` output
+func routeInterface(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "interface:"); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// hardwarePort finds the port name of device in `networksetup -listallhardwareports`
+// output, which lists a "Hardware Port:" line followed by its "Device:" line
+func hardwarePort(output, device string) string {
+	var port string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if value, ok := strings.CutPrefix(line, "Hardware Port:"); ok {
+			port = strings.TrimSpace(value)
+		} else if value, ok := strings.CutPrefix(line, "Device:"); ok && strings.TrimSpace(value) == device {
+			return port
+		}
+	}
+	return ""
+}
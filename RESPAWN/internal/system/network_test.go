@@ -0,0 +1,57 @@
+package system
+
+import "testing"
+
+// routeOutput is `route -n get default` output on a Mac on Wi-Fi
+const routeOutput = `   route to: default
+destination: default
+       mask: default
+    gateway: 192.168.1.1
+  interface: en0
+      flags: <UP,GATEWAY,DONE,STATIC,PRCLONING,GLOBAL>
+ recvpipe  sendpipe  ssthresh  rtt,msec    rttvar  hopcount      mtu     expire
+       0         0         0         0         0         0      1500         0
+`
+
+// hardwarePortsOutput is `networksetup -listallhardwareports` output
+const hardwarePortsOutput = `
+Hardware Port: Ethernet
+Device: en5
+Ethernet Address: 00:11:22:33:44:55
+
+Hardware Port: Wi-Fi
+Device: en0
+Ethernet Address: 66:77:88:99:aa:bb
+
+Hardware Port: iPhone USB
+Device: en10
+Ethernet Address: cc:dd:ee:ff:00:11
+
+VLAN Configurations
+===================
+`
+
+// TestRouteInterface verifies the default route's interface is picked out
+func TestRouteInterface(t *testing.T) {
+	if device := routeInterface(routeOutput); device != "en0" {
+		t.Errorf("Expected interface en0, got %q", device)
+	}
+	if device := routeInterface("route: writing to routing socket: not in table\n"); device != "" {
+		t.Errorf("Expected no interface without a default route, got %q", device)
+	}
+}
+
+// TestHardwarePort verifies devices are matched to their hardware port
+func TestHardwarePort(t *testing.T) {
+	tests := map[string]string{
+		"en0":  "Wi-Fi",
+		"en5":  "Ethernet",
+		"en10": "iPhone USB",
+		"en1":  "",
+	}
+	for device, want := range tests {
+		if port := hardwarePort(hardwarePortsOutput, device); port != want {
+			t.Errorf("hardwarePort(%q) = %q, want %q", device, port, want)
+		}
+	}
+}
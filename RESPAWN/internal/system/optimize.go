@@ -0,0 +1,226 @@
+package system
+
+import (
+    "fmt"
+    "time"
+
+    "RESPAWN/pkg/config"
+)
+
+// Thresholds for the automatic optimizations
+const (
+    highDiskGrowthMB        = 500.0 // MB per week before a higher compression level is considered
+    lowDiskGrowthMB         = 250.0 // MB per week below which a raised level goes back down
+    compressionStep         = 3
+    maxAutoCompressionLevel = 12 // Higher levels cost more CPU than a background tool should spend
+
+    minSamplesForInterval   = 10  // Checkpoints needed before judging how often they change
+    unchangedRatioThreshold = 0.8 // Share of unchanged checkpoints that makes the interval too short
+    maxAutoInterval         = 60 * time.Minute
+
+    crashLookback   = 7 * 24 * time.Hour
+    frequentCrashes = 3 // Crashes within crashLookback that make the interval too long
+    minAutoInterval = 5 * time.Minute
+)
+
+// SetCompressionEstimator sets the function that measures how much smaller
+// checkpoints would be at a compression level, in percent
+func (sm *SystemMonitor) SetCompressionEstimator(estimator func(level int) (float64, error)) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.compressionEstimator = estimator
+}
+
+// SetConfigChangeHandler sets the function called after an optimization saved a
+// config change, so running components can pick it up
+func (sm *SystemMonitor) SetConfigChangeHandler(handler func()) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.configChangeHandler = handler
+}
+
+// generateOptimizations proposes config changes backed by the collected metrics.
+// Each setting moves at most one step from the user's own value, and goes back to it
+// once the metrics that moved it no longer hold.
+func (sm *SystemMonitor) generateOptimizations() []Optimization {
+    cfg := config.Current()
+    if cfg == nil {
+        return nil
+    }
+
+    sm.mu.Lock()
+    growth := sm.metrics.DiskGrowthRate
+    samples := append([]CheckpointSample(nil), sm.metrics.CheckpointSamples...)
+    baseLevel := sm.metrics.BaseCompressionLevel
+    baseInterval := sm.metrics.BaseCheckpointInterval
+    estimator := sm.compressionEstimator
+    sm.mu.Unlock()
+
+    if baseLevel == 0 {
+        baseLevel = cfg.CompressionLevel
+    }
+    if baseInterval == 0 {
+        baseInterval = cfg.CheckpointInterval
+    }
+
+    var optimizations []Optimization
+
+    // Compression, between the user's level and one step above it
+    maxLevel := baseLevel + compressionStep
+    if maxLevel > maxAutoCompressionLevel {
+        maxLevel = maxAutoCompressionLevel
+    }
+    switch {
+    // Disk fills up quickly: see what a higher compression level would actually save
+    case growth > highDiskGrowthMB && cfg.CompressionLevel < maxLevel && estimator != nil:
+        level := maxLevel
+        if saving, err := estimator(level); err != nil {
+            Debug("Could not estimate compression savings:", err)
+        } else if saving > 0 {
+            optimizations = append(optimizations, Optimization{
+                Description:        fmt.Sprintf("Raise compression level from %d to %d (disk growth %.0f MB/week)", cfg.CompressionLevel, level, growth),
+                ImprovementPercent: saving,
+                Apply:              sm.compressionChange(level, baseLevel),
+            })
+        }
+
+    // Growth has settled, stop paying for the higher level
+    case growth < lowDiskGrowthMB && cfg.CompressionLevel > baseLevel:
+        optimizations = append(optimizations, Optimization{
+            Description: fmt.Sprintf("Lower compression level from %d back to %d (disk growth %.0f MB/week)", cfg.CompressionLevel, baseLevel, growth),
+            Reverts:     true,
+            Apply:       sm.compressionChange(baseLevel, baseLevel),
+        })
+    }
+
+    // Checkpoint interval, between half and twice the user's interval
+    interval := cfg.CheckpointInterval
+    minInterval := baseInterval / 2
+    if minInterval < minAutoInterval {
+        minInterval = minAutoInterval
+    }
+    maxInterval := baseInterval * 2
+    if maxInterval > maxAutoInterval {
+        maxInterval = maxAutoInterval
+    }
+    crashes := recentCrashes(crashLookback)
+    ratio, judged := unchangedRatio(samples)
+
+    switch {
+    // Frequent crashes lose more work, checkpoint more often
+    case crashes >= frequentCrashes && interval > minInterval:
+        shorter := interval / 2
+        if shorter < minInterval {
+            shorter = minInterval
+        }
+        optimizations = append(optimizations, Optimization{
+            Description:        fmt.Sprintf("Shorten checkpoint interval from %s to %s (%d crashes in the last week)", interval, shorter, crashes),
+            ImprovementPercent: (1 - float64(shorter)/float64(interval)) * 100, // Less work lost per crash
+            Apply:              sm.intervalChange(shorter, baseInterval),
+        })
+
+    case crashes >= frequentCrashes:
+        // Already as short as it goes
+
+    // Crashes have stopped, go back to the user's interval
+    case interval < baseInterval:
+        optimizations = append(optimizations, Optimization{
+            Description: fmt.Sprintf("Lengthen checkpoint interval from %s back to %s (%d crashes in the last week)", interval, baseInterval, crashes),
+            Reverts:     true,
+            Apply:       sm.intervalChange(baseInterval, baseInterval),
+        })
+
+    // Checkpoints keep capturing the same apps, take fewer of them
+    case judged && ratio >= unchangedRatioThreshold && interval < maxInterval:
+        longer := interval * 2
+        if longer > maxInterval {
+            longer = maxInterval
+        }
+        optimizations = append(optimizations, Optimization{
+            Description:        fmt.Sprintf("Lengthen checkpoint interval from %s to %s (%.0f%% of recent checkpoints unchanged)", interval, longer, ratio*100),
+            ImprovementPercent: (1 - float64(interval)/float64(longer)) * 100, // Fewer checkpoints written
+            Apply:              sm.intervalChange(longer, baseInterval),
+        })
+
+    // The workspace is changing again, go back to the user's interval
+    case judged && ratio < unchangedRatioThreshold && interval > baseInterval:
+        optimizations = append(optimizations, Optimization{
+            Description: fmt.Sprintf("Shorten checkpoint interval from %s back to %s (%.0f%% of recent checkpoints unchanged)", interval, baseInterval, ratio*100),
+            Reverts:     true,
+            Apply:       sm.intervalChange(baseInterval, baseInterval),
+        })
+    }
+
+    return optimizations
+}
+
+// unchangedRatio is the share of recent checkpoints whose apps matched the one
+// before; judged is false until there are enough checkpoints to tell
+func unchangedRatio(samples []CheckpointSample) (ratio float64, judged bool) {
+    checkpoints, unchanged := 0, 0
+    for _, sample := range samples {
+        if sample.Removed {
+            continue
+        }
+        checkpoints++
+        if !sample.Changed {
+            unchanged++
+        }
+    }
+    if checkpoints < minSamplesForInterval {
+        return 0, false
+    }
+    return float64(unchanged) / float64(checkpoints), true
+}
+
+// compressionChange returns an Apply function that sets the compression level,
+// remembering the user's own level while it differs
+func (sm *SystemMonitor) compressionChange(level, base int) func() error {
+    return sm.configChange(func(c *config.Config) {
+        c.CompressionLevel = level
+    }, func(m *OptimizationMetrics) {
+        m.BaseCompressionLevel = 0
+        if level != base {
+            m.BaseCompressionLevel = base
+        }
+    })
+}
+
+// intervalChange returns an Apply function that sets the checkpoint interval,
+// remembering the user's own interval while it differs
+func (sm *SystemMonitor) intervalChange(interval, base time.Duration) func() error {
+    return sm.configChange(func(c *config.Config) {
+        c.CheckpointInterval = interval
+    }, func(m *OptimizationMetrics) {
+        m.BaseCheckpointInterval = 0
+        if interval != base {
+            m.BaseCheckpointInterval = base
+        }
+    })
+}
+
+// configChange returns an Apply function that saves change to the config file,
+// records it in the metrics with track and lets the running components know
+func (sm *SystemMonitor) configChange(change func(c *config.Config), track func(m *OptimizationMetrics)) func() error {
+    return func() error {
+        if err := config.Update(change); err != nil {
+            return err
+        }
+
+        sm.mu.Lock()
+        track(sm.metrics)
+        sm.persistMetrics()
+        handler := sm.configChangeHandler
+        sm.mu.Unlock()
+
+        if handler != nil {
+            handler()
+            return nil
+        }
+        if _, err := config.Reload(); err != nil {
+            return err
+        }
+        sm.ApplyConfig()
+        return nil
+    }
+}
@@ -0,0 +1,101 @@
+package system
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Stdout and Stderr are where CLI output goes. Commands write through these
+// rather than os.Stdout so output modes such as --plain apply everywhere.
+var (
+	Stdout io.Writer = os.Stdout
+	Stderr io.Writer = os.Stderr
+)
+
+// PlainOutputRequested reports whether NO_COLOR asks for unstyled output
+func PlainOutputRequested() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// SetPlainOutput strips emoji, box drawing and ANSI styling from CLI output,
+// for CI scripts, screen readers and minimal terminals
+func SetPlainOutput() {
+	Stdout = &plainWriter{w: Stdout}
+	Stderr = &plainWriter{w: Stderr}
+}
+
+// SetQuietOutput drops everything but errors from CLI output
+func SetQuietOutput() {
+	Stdout = io.Discard
+}
+
+// plainWriter rewrites each write to plain ASCII-friendly text
+type plainWriter struct {
+	w io.Writer
+}
+
+// ansiPattern matches ANSI escape sequences such as colors and cursor movement
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// boxReplacer maps box drawing and decorative characters to ASCII
+var boxReplacer = strings.NewReplacer(
+	"─", "-", "━", "-", "═", "=",
+	"│", "|", "┃", "|", "║", "|",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+", "├", "+", "┤", "+", "┬", "+", "┴", "+", "┼", "+",
+	"╔", "+", "╗", "+", "╚", "+", "╝", "+",
+	"•", "-", "→", "->", "…", "...", "█", "#", "░", ".",
+)
+
+// Write strips styling from p; it reports len(p) so callers see a complete write
+func (pw *plainWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(pw.w, PlainText(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// PlainText removes emoji and ANSI styling from s and replaces box drawing with ASCII.
+// Spaces that only separated an emoji from the text are dropped with it.
+func PlainText(s string) string {
+	s = boxReplacer.Replace(ansiPattern.ReplaceAllString(s, ""))
+
+	runes := []rune(s)
+	out := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		if !isEmoji(runes[i]) {
+			out = append(out, runes[i])
+			continue
+		}
+		// Skip the emoji, its modifiers, and the spaces after it
+		for i+1 < len(runes) && (isEmoji(runes[i+1]) || runes[i+1] == ' ') {
+			i++
+		}
+		// At the end of a line, also drop the space before it
+		if i+1 == len(runes) || runes[i+1] == '\n' {
+			for len(out) > 0 && out[len(out)-1] == ' ' {
+				out = out[:len(out)-1]
+			}
+		}
+	}
+	return string(out)
+}
+
+// isEmoji reports whether r is an emoji, pictograph, or emoji modifier
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Emoji and pictographs
+		return true
+	case r >= 0x2300 && r <= 0x23FF: // Misc technical, e.g. ⏸ ⏭
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Misc symbols and dingbats, e.g. ✅ ❌ ⚠
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // Arrows and stars, e.g. ⭐
+		return true
+	case r == 0xFE0F || r == 0x200D: // Variation selector, zero width joiner
+		return true
+	}
+	return unicode.Is(unicode.Variation_Selector, r)
+}
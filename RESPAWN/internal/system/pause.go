@@ -0,0 +1,73 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"RESPAWN/pkg/config"
+)
+
+// pauseFileName marks monitoring as paused; it holds the end of a timed pause
+const pauseFileName = "paused"
+
+// PauseFile returns the path of the pause marker
+func PauseFile() string {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return filepath.Join(cfg.DataDir, pauseFileName)
+}
+
+// Pause stops checkpoints until Resume or, when until is set, until then
+func Pause(until time.Time) error {
+	// A timed pause stores when it ends, an open-ended one just when it started
+	marker := time.Now().String()
+	if !until.IsZero() {
+		marker = until.Format(time.RFC3339)
+	}
+
+	if err := os.WriteFile(PauseFile(), []byte(marker), 0644); err != nil {
+		return fmt.Errorf("Failed to create pause marker: %w", err)
+	}
+	return nil
+}
+
+// Resume lifts a pause
+func Resume() error {
+	if err := os.Remove(PauseFile()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to remove pause marker: %w", err)
+	}
+	return nil
+}
+
+// PausedUntil reports whether monitoring is paused and, for timed pauses, until when.
+// An expired timed pause is removed.
+func PausedUntil() (time.Time, bool) {
+	pauseFile := PauseFile()
+	data, err := os.ReadFile(pauseFile)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	until, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, true // Paused until resumed
+	}
+	if time.Now().After(until) {
+		os.Remove(pauseFile)
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// pauseGate describes a pause as a checkpoint gate, e.g. "paused until 15:04"
+func pauseGate(until time.Time) string {
+	if until.IsZero() {
+		return "paused"
+	}
+	return "paused until " + until.Format("15:04")
+}
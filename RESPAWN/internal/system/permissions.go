@@ -0,0 +1,256 @@
+package system
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"RESPAWN/pkg/config"
+)
+
+// Permission identifies a macOS privacy permission RESPAWN relies on
+type Permission string
+
+const (
+	PermissionAutomation      Permission = "Automation"
+	PermissionAccessibility   Permission = "Accessibility"
+	PermissionFullDiskAccess  Permission = "Full Disk Access"
+	PermissionScreenRecording Permission = "Screen Recording"
+)
+
+// permissionPanes maps each permission to its System Settings deep link
+var permissionPanes = map[Permission]string{
+	PermissionAutomation:      "x-apple.systempreferences:com.apple.preference.security?Privacy_Automation",
+	PermissionAccessibility:   "x-apple.systempreferences:com.apple.preference.security?Privacy_Accessibility",
+	PermissionFullDiskAccess:  "x-apple.systempreferences:com.apple.preference.security?Privacy_AllFiles",
+	PermissionScreenRecording: "x-apple.systempreferences:com.apple.preference.security?Privacy_ScreenCapture",
+}
+
+// requiredPermissions are checked in order - the Accessibility check itself needs Automation
+var requiredPermissions = []Permission{
+	PermissionAutomation,
+	PermissionAccessibility,
+}
+
+// ErrPermissionMissing means a macOS privacy permission RESPAWN needs has not been granted
+var ErrPermissionMissing = errors.New("permission not granted")
+
+// appleScriptPermissionCodes are osascript error numbers caused by missing permissions:
+// -1743 "Not authorized to send Apple events", -1719 "not allowed assistive access"
+var appleScriptPermissionCodes = []string{"-1743", "-1719"}
+
+// AppleScriptError wraps a failed osascript run, returning ErrPermissionMissing when
+// the output shows macOS refused it for lack of Automation or Accessibility
+func AppleScriptError(output []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, code := range appleScriptPermissionCodes {
+		if strings.Contains(string(output), code) {
+			return fmt.Errorf("%w (AppleScript error %s)", ErrPermissionMissing, code)
+		}
+	}
+	return err
+}
+
+// PermissionCache records the result of the last permission pre-flight so that
+// status checks don't fire Apple Events (and TCC prompts) at awkward times
+type PermissionCache struct {
+	CheckedAt time.Time           `json:"checked_at"`
+	States    map[Permission]bool `json:"states"`
+}
+
+// degradedMode is set when startup found Accessibility missing
+var degradedMode bool
+
+// permissionPollInterval is how often onboarding re-checks a permission
+const permissionPollInterval = 2 * time.Second
+
+// OpenPermissionPane opens System Settings at the pane for a permission
+func OpenPermissionPane(permission Permission) error {
+	url, ok := permissionPanes[permission]
+	if !ok {
+		return fmt.Errorf("no settings pane known for %s", permission)
+	}
+
+	if err := exec.Command("open", url).Run(); err != nil {
+		return fmt.Errorf("failed to open System Settings: %w", err)
+	}
+	return nil
+}
+
+// HasPermission checks whether a permission is currently granted
+func (sm *StartupManager) HasPermission(permission Permission) bool {
+	switch permission {
+	case PermissionAutomation:
+		return sm.hasAutomationPermission()
+	case PermissionAccessibility:
+		return sm.hasAccessibilityPermission()
+	case PermissionFullDiskAccess:
+		return sm.hasFullDiskAccess()
+	case PermissionScreenRecording:
+		return HasScreenRecordingPermission()
+	}
+	return false
+}
+
+// PermissionPaneURL returns the System Settings deep link for a permission
+func PermissionPaneURL(permission Permission) string {
+	return permissionPanes[permission]
+}
+
+// SetDegradedMode marks whether window state can be captured in this process
+func SetDegradedMode(degraded bool) {
+	degradedMode = degraded
+}
+
+// IsDegradedMode reports whether checkpoints should be basic (app list only).
+// Processes that never ran the startup permission check fall back to the pre-flight cache.
+func IsDegradedMode() bool {
+	if degradedMode {
+		return true
+	}
+
+	cache, err := LoadPermissionCache()
+	if err != nil {
+		return false
+	}
+	return !cache.States[PermissionAutomation] || !cache.States[PermissionAccessibility]
+}
+
+// GuidePermissionSetup walks the user through granting each missing required permission.
+// It opens the matching System Settings pane and polls until the permission is granted
+// or the timeout for that permission expires.
+func (sm *StartupManager) GuidePermissionSetup(timeout time.Duration) error {
+	for _, permission := range requiredPermissions {
+		if sm.HasPermission(permission) {
+			Info(permission, "permission granted")
+			continue
+		}
+
+		Warn(permission, "permission not granted - starting guided setup")
+		fmt.Fprintf(Stdout, "\n🔐 RESPAWN needs %s permission.\n", permission)
+		fmt.Fprintf(Stdout, "   Opening System Settings - enable RESPAWN (or your terminal) in the %s list.\n", permission)
+
+		if err := OpenPermissionPane(permission); err != nil {
+			Warn("Failed to open settings pane:", err)
+			fmt.Fprintf(Stdout, "   Please open it manually: %s\n", permissionPanes[permission])
+		}
+
+		fmt.Fprint(Stdout, "   Waiting for permission")
+		if !waitForPermission(func() bool { return sm.HasPermission(permission) }, timeout) {
+			fmt.Fprintln(Stdout, " ❌")
+			return fmt.Errorf("%w: %s not granted within %s", ErrPermissionMissing, permission, timeout)
+		}
+
+		fmt.Fprintln(Stdout, " ✅")
+		Info(permission, "permission granted during onboarding")
+	}
+
+	return nil
+}
+
+// waitForPermission polls check until it returns true or the timeout expires
+func waitForPermission(check func() bool, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if check() {
+			return true
+		}
+		fmt.Fprint(Stdout, ".")
+		time.Sleep(permissionPollInterval)
+	}
+	return check()
+}
+
+// hasAutomationPermission checks if RESPAWN may send Apple Events to System Events
+func (sm *StartupManager) hasAutomationPermission() bool {
+	script := `tell application "System Events" to return name of current user`
+
+	cmd := exec.Command("osascript", "-e", script)
+	output, err := cmd.CombinedOutput()
+	if err = AppleScriptError(output, err); err != nil {
+		if errors.Is(err, ErrPermissionMissing) {
+			Debug("Automation permission denied")
+		}
+		return false
+	}
+	return true
+}
+
+// HasScreenRecordingPermission checks if RESPAWN may read other apps' window titles.
+// On macOS 10.15+ window titles are only visible with Screen Recording permission.
+func HasScreenRecordingPermission() bool {
+	script := `ObjC.import('CoreGraphics'); $.CGPreflightScreenCaptureAccess();`
+
+	cmd := exec.Command("osascript", "-l", "JavaScript", "-e", script)
+	output, err := cmd.Output()
+	if err != nil {
+		Debug("Could not check Screen Recording permission:", err)
+		return false
+	}
+	return strings.TrimSpace(string(output)) == "true"
+}
+
+// RunPermissionPreflight exercises every Apple Event RESPAWN needs once, so any TCC
+// prompts appear now rather than mid-checkpoint, and caches the results
+func (sm *StartupManager) RunPermissionPreflight() (*PermissionCache, error) {
+	Info("Running permission pre-flight")
+
+	cache := &PermissionCache{
+		CheckedAt: time.Now(),
+		States:    make(map[Permission]bool),
+	}
+
+	for _, permission := range []Permission{
+		PermissionAutomation,
+		PermissionAccessibility,
+		PermissionScreenRecording,
+		PermissionFullDiskAccess,
+	} {
+		cache.States[permission] = sm.HasPermission(permission)
+		Debug("Pre-flight", permission, ":", cache.States[permission])
+	}
+
+	if err := cache.Save(); err != nil {
+		return cache, fmt.Errorf("failed to save permission cache: %w", err)
+	}
+	return cache, nil
+}
+
+// LoadPermissionCache loads the result of the last pre-flight
+func LoadPermissionCache() (*PermissionCache, error) {
+	data, err := os.ReadFile(permissionCachePath())
+	if err != nil {
+		return nil, err
+	}
+
+	var cache PermissionCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
+
+// Save writes the permission cache
+func (pc *PermissionCache) Save() error {
+	data, err := json.MarshalIndent(pc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(permissionCachePath(), data, 0644)
+}
+
+// permissionCachePath returns the location of the permission cache file in the data directory
+func permissionCachePath() string {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return filepath.Join(cfg.DataDir, "permissions.json")
+}
@@ -0,0 +1,87 @@
+package system
+
+import (
+    "fmt"
+    "os/exec"
+    "regexp"
+    "strconv"
+    "strings"
+
+    "RESPAWN/pkg/config"
+)
+
+// batteryPercentPattern matches the charge in `pmset -g batt` output, e.g. "85%;"
+var batteryPercentPattern = regexp.MustCompile(`(\d+)%`)
+
+// BatteryLevel returns the battery charge in percent. Macs without a battery report 100.
+func BatteryLevel() (int, error) {
+    output, err := exec.Command("pmset", "-g", "batt").Output()
+    if err != nil {
+        return 0, fmt.Errorf("pmset failed: %w", err)
+    }
+
+    for _, line := range strings.Split(string(output), "\n") {
+        if match := batteryPercentPattern.FindStringSubmatch(line); match != nil {
+            return strconv.Atoi(match[1])
+        }
+    }
+    return 100, nil
+}
+
+// PowerConnected reports whether the Mac runs on AC power
+func PowerConnected() bool {
+    output, err := exec.Command("pmset", "-g", "ps").Output()
+    if err != nil {
+        return false
+    }
+    return strings.Contains(string(output), "AC Power")
+}
+
+// OnLowBattery reports whether the Mac runs on battery below maintenance_min_battery,
+// when disk-heavy maintenance (compression, metrics writes) should wait for power
+func OnLowBattery() bool {
+    threshold := config.Current().MaintenanceMinBattery
+    if threshold <= 0 || PowerConnected() {
+        return false
+    }
+
+    level, err := BatteryLevel()
+    if err != nil {
+        Warn("Failed to get battery level:", err)
+        return false
+    }
+    return level < threshold
+}
+
+// updatePowerState samples the power state once per monitoring cycle and, when power
+// is back, writes the metrics held back while on battery
+func (sm *SystemMonitor) updatePowerState() bool {
+    lowBattery := OnLowBattery()
+
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+
+    if sm.lowBattery && !lowBattery {
+        Info("Power restored - running deferred maintenance")
+    }
+    sm.lowBattery = lowBattery
+    if !lowBattery && sm.metricsDirty {
+        sm.persistMetrics()
+    }
+    return lowBattery
+}
+
+// persistMetrics saves metrics now, or marks them for saving once power returns.
+// The caller must hold sm.mu.
+func (sm *SystemMonitor) persistMetrics() {
+    if sm.lowBattery {
+        sm.metricsDirty = true
+        return
+    }
+    if err := sm.saveMetrics(); err != nil {
+        Warn("Failed to save metrics:", err)
+        sm.metricsDirty = true // Try again next cycle
+        return
+    }
+    sm.metricsDirty = false
+}
@@ -0,0 +1,28 @@
+package system
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+)
+
+// StartPprofServer serves the Go profiler on addr so CPU and memory profiles can be
+// captured from a running daemon, e.g.
+//
+//	go tool pprof http://127.0.0.1:7374/debug/pprof/profile?seconds=30
+//
+// Only loopback addresses are accepted - profiles expose internals of the process.
+func StartPprofServer(addr string) (*APIServer, error) {
+	if err := loopbackOnly(addr); err != nil {
+		return nil, fmt.Errorf("pprof %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return startServer("pprof", addr, mux)
+}
@@ -0,0 +1,118 @@
+package system
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PurgeData deletes everything RESPAWN stored under dirs: checkpoints, metadata,
+// logs, learning data and marker files. Dirs that don't exist, or that sit inside
+// another of dirs, are skipped. It returns the paths of the files that were deleted.
+func PurgeData(dirs ...string) ([]string, error) {
+	var roots []string
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		roots = append(roots, filepath.Clean(dir))
+	}
+
+	var files []string
+	var subdirs []string
+	for _, root := range roots {
+		if insideAny(root, roots) {
+			continue
+		}
+		Info("Purging all RESPAWN data in", root)
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				subdirs = append(subdirs, path)
+			} else {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+	}
+
+	var deleted []string
+	for _, path := range files {
+		if err := overwriteAndRemove(path); err != nil {
+			Warn("Failed to delete", path, ":", err)
+			continue
+		}
+		deleted = append(deleted, path)
+	}
+
+	// Remove directories deepest first
+	sort.Slice(subdirs, func(i, j int) bool {
+		return len(subdirs[i]) > len(subdirs[j])
+	})
+	for _, dir := range subdirs {
+		if err := os.Remove(dir); err != nil {
+			Warn("Failed to remove directory", dir, ":", err)
+		}
+	}
+
+	return deleted, nil
+}
+
+// insideAny reports whether dir is inside one of the other roots
+func insideAny(dir string, roots []string) bool {
+	for _, root := range roots {
+		if root != dir && strings.HasPrefix(dir, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// overwriteAndRemove overwrites a file with zeros before removing it. This is not
+// secure deletion: APFS is copy-on-write, so the zeros usually land in new blocks
+// and the old contents stay on disk until reused, as do snapshots and backups.
+func overwriteAndRemove(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	// Only overwrite regular files, symlinks are just unlinked
+	if info.Mode().IsRegular() && info.Size() > 0 {
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+
+		zeros := make([]byte, 32*1024)
+		remaining := info.Size()
+		for remaining > 0 {
+			chunk := int64(len(zeros))
+			if remaining < chunk {
+				chunk = remaining
+			}
+			if _, err := file.Write(zeros[:chunk]); err != nil {
+				file.Close()
+				return err
+			}
+			remaining -= chunk
+		}
+
+		file.Sync()
+		file.Close()
+	}
+
+	return os.Remove(path)
+}
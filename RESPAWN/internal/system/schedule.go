@@ -0,0 +1,113 @@
+package system
+
+import (
+    "time"
+
+    "RESPAWN/pkg/config"
+)
+
+// scheduleCheckInterval is how often scheduled launches are checked; under a
+// minute so no scheduled minute is skipped
+const scheduleCheckInterval = 20 * time.Second
+
+// launchCatchUp is how late a launch missed while the Mac slept still runs on wake
+const launchCatchUp = 2 * time.Hour
+
+// SetScheduledLaunchHandler sets the function the monitor calls when a scheduled launch is due
+func (sm *SystemMonitor) SetScheduledLaunchHandler(handler func(launch config.ScheduledLaunch) error) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.scheduledLaunchHandler = handler
+}
+
+// SetQuietHoursEndHandler sets the function called when notification quiet hours end
+func (sm *SystemMonitor) SetQuietHoursEndHandler(handler func()) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.quietHoursEndHandler = handler
+}
+
+// checkQuietHours calls the quiet hours handler when quiet hours end, and on the
+// first check outside them, so a digest queued while the daemon was stopped goes out
+func (sm *SystemMonitor) checkQuietHours(now time.Time) {
+    quiet := config.Current().QuietHours.Contains(now)
+
+    sm.mu.Lock()
+    ended := !quiet && (sm.quietHours || !sm.quietHoursChecked)
+    sm.quietHours = quiet
+    sm.quietHoursChecked = true
+    handler := sm.quietHoursEndHandler
+    sm.mu.Unlock()
+
+    if ended && handler != nil {
+        handler()
+    }
+}
+
+// scheduleLoop fires scheduled launches once in their minute
+func (sm *SystemMonitor) scheduleLoop() {
+    defer RecordPanics()
+    ticker := time.NewTicker(scheduleCheckInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case now := <-ticker.C:
+            sm.runDueLaunches(now)
+            sm.checkQuietHours(now)
+        case <-sm.stopChan:
+            return
+        }
+    }
+}
+
+// runDueLaunches runs every launch scheduled since the last check that hasn't run
+// yet. After a sleep that covers launches missed within launchCatchUp; on the first
+// check only the current minute counts.
+func (sm *SystemMonitor) runDueLaunches(now time.Time) {
+    sm.mu.Lock()
+    since := sm.lastScheduleCheck
+    sm.lastScheduleCheck = now
+    sm.mu.Unlock()
+
+    if earliest := now.Add(-launchCatchUp); since.IsZero() {
+        since = now.Truncate(time.Minute).Add(-time.Nanosecond)
+    } else if since.Before(earliest) {
+        since = earliest
+    }
+
+    for _, launch := range config.Current().ScheduledLaunches {
+        // Yesterday too, for a launch just before midnight missed during a sleep
+        var due time.Time
+        for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+            if at, ok := launch.Occurrence(day); ok && at.After(since) && !at.After(now) {
+                due = at
+            }
+        }
+        if due.IsZero() {
+            continue
+        }
+        occurrence := due.Format("2006-01-02 15:04")
+
+        sm.mu.Lock()
+        handler := sm.scheduledLaunchHandler
+        alreadyRan := sm.launchesRun[launch.Name+"@"+launch.At] == occurrence
+        if !alreadyRan {
+            sm.launchesRun[launch.Name+"@"+launch.At] = occurrence
+        }
+        sm.mu.Unlock()
+
+        if alreadyRan || handler == nil || !sm.beginWork() {
+            continue
+        }
+
+        if late := now.Sub(due); late > time.Minute {
+            Info("Catching up on scheduled launch", launch.Name, "missed by", late.Round(time.Minute))
+        }
+        Info("Running scheduled launch", launch.Name, "(", launch.Checkpoint+launch.Template, ")")
+        if err := handler(launch); err != nil {
+            Error("Scheduled launch", launch.Name, "failed:", err)
+        }
+        sm.inFlight.Done()
+    }
+}
@@ -0,0 +1,58 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// SelfUsage is RESPAWN's own resource consumption
+type SelfUsage struct {
+	RSSMB      int64     `json:"rss_mb"`
+	CPUPercent float64   `json:"cpu_percent"` // Average since the previous sample
+	SampledAt  time.Time `json:"sampled_at"`
+}
+
+// selfUsageSampler computes CPU usage from consecutive rusage samples
+type selfUsageSampler struct {
+	lastCPUTime time.Duration
+	lastSample  time.Time
+}
+
+// Sample measures current RSS and the CPU used since the previous sample
+func (s *selfUsageSampler) Sample() (SelfUsage, error) {
+	now := time.Now()
+	usage := SelfUsage{SampledAt: now}
+
+	var rusage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &rusage); err != nil {
+		return usage, fmt.Errorf("failed to get rusage: %w", err)
+	}
+	cpuTime := time.Duration(rusage.Utime.Nano() + rusage.Stime.Nano())
+
+	if !s.lastSample.IsZero() {
+		wall := now.Sub(s.lastSample)
+		if wall > 0 {
+			usage.CPUPercent = float64(cpuTime-s.lastCPUTime) / float64(wall) * 100
+		}
+	}
+	s.lastCPUTime = cpuTime
+	s.lastSample = now
+
+	// rusage only reports peak RSS, ask ps for the current value (in KB)
+	output, err := exec.Command("ps", "-o", "rss=", "-p", strconv.Itoa(os.Getpid())).Output()
+	if err != nil {
+		return usage, fmt.Errorf("failed to execute ps command: %w", err)
+	}
+	rssKB, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return usage, fmt.Errorf("failed to parse RSS: %w", err)
+	}
+	usage.RSSMB = rssKB / 1024
+
+	return usage, nil
+}
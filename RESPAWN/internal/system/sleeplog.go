@@ -0,0 +1,115 @@
+package system
+
+import (
+    "fmt"
+    "os/exec"
+    "regexp"
+    "strings"
+    "time"
+)
+
+// pmsetLogPattern matches the sleep and wake entries of `pmset -g log`, e.g.
+// "2024-04-02 02:10:33 +0200 Sleep               	Entering Sleep state due to 'Idle Sleep'..."
+// DarkWake entries (Power Nap, maintenance) are left out: the display stays off and
+// the Mac goes back to sleep, so to the user the sleep continues.
+var pmsetLogPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2} [+-]\d{4}) (Sleep|Wake)\s`)
+
+// pmsetTimeLayout is the timestamp format of `pmset -g log`
+const pmsetTimeLayout = "2006-01-02 15:04:05 -0700"
+
+// SleepPeriod is one sleep from the power management log. A sleep the Mac has not
+// woken from yet, or whose wake is missing from the log, has a zero End.
+type SleepPeriod struct {
+    Start time.Time `json:"start"`
+    End   time.Time `json:"end,omitempty"`
+}
+
+// Duration is how long the Mac slept, zero while End is unknown
+func (p SleepPeriod) Duration() time.Duration {
+    if p.End.IsZero() {
+        return 0
+    }
+    return p.End.Sub(p.Start)
+}
+
+// String describes the sleep, e.g. "slept 02:10–07:45"
+func (p SleepPeriod) String() string {
+    if p.End.IsZero() {
+        return fmt.Sprintf("slept since %s", p.Start.Format("15:04"))
+    }
+    return fmt.Sprintf("slept %s–%s", p.Start.Format("15:04"), p.End.Format("15:04"))
+}
+
+// parseSleepLog pairs each Sleep entry of `pmset -g log` output with the Wake that follows it
+func parseSleepLog(output string) []SleepPeriod {
+    var periods []SleepPeriod
+    var asleep *SleepPeriod
+
+    for _, line := range strings.Split(output, "\n") {
+        match := pmsetLogPattern.FindStringSubmatch(line)
+        if match == nil {
+            continue
+        }
+        at, err := time.Parse(pmsetTimeLayout, match[1])
+        if err != nil {
+            continue
+        }
+
+        switch match[2] {
+        case "Sleep":
+            if asleep == nil {
+                asleep = &SleepPeriod{Start: at}
+            }
+        case "Wake":
+            if asleep != nil {
+                asleep.End = at
+                periods = append(periods, *asleep)
+                asleep = nil
+            }
+        }
+    }
+
+    if asleep != nil {
+        periods = append(periods, *asleep)
+    }
+    return periods
+}
+
+// SleepsSince returns the sleeps recorded by power management that ended after since,
+// oldest first
+func SleepsSince(since time.Time) ([]SleepPeriod, error) {
+    output, err := exec.Command("pmset", "-g", "log").Output()
+    if err != nil {
+        return nil, fmt.Errorf("pmset failed: %w", err)
+    }
+
+    var periods []SleepPeriod
+    for _, period := range parseSleepLog(string(output)) {
+        if period.End.IsZero() || period.End.After(since) {
+            periods = append(periods, period)
+        }
+    }
+    return periods, nil
+}
+
+// sleepAfter returns the sleep that started after since, spanning the whole time the
+// Mac was away if it slept several times, or nil if it did not sleep. ok is false when
+// the log could not be read and the caller has to guess.
+func sleepAfter(since time.Time) (period *SleepPeriod, ok bool) {
+    periods, err := SleepsSince(since)
+    if err != nil {
+        Debug("Sleep log unavailable:", err)
+        return nil, false
+    }
+
+    for _, p := range periods {
+        if p.Start.Before(since) {
+            continue
+        }
+        if period == nil {
+            period = &SleepPeriod{Start: p.Start}
+        }
+        period.End = p.End
+    }
+    return period, true
+}
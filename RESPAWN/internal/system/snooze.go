@@ -0,0 +1,135 @@
+package system
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"RESPAWN/pkg/config"
+)
+
+// snoozeFileName holds snoozed notifications, so a daemon restart doesn't lose them
+const snoozeFileName = "snoozed.json"
+
+// Notifications that can be snoozed with "Remind Me Later"
+const (
+	SnoozeRestorePrompt = "restore-prompt"
+	SnoozeDiskAlert     = "disk-alert"
+)
+
+// snooze is one held-back notification
+type snooze struct {
+	Until   time.Time `json:"until"`
+	Subject string    `json:"subject,omitempty"` // What the notification was about, e.g. a checkpoint ID
+}
+
+// DueSnooze is a notification whose snooze ran out
+type DueSnooze struct {
+	Kind    string
+	Subject string
+}
+
+// UnmarshalJSON also reads entries from files written before snoozes had a subject
+func (s *snooze) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &s.Until); err == nil {
+		return nil
+	}
+	type plain snooze
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// snoozeMu serializes read-modify-write of the snooze file within the daemon
+var snoozeMu sync.Mutex
+
+// SnoozeFile returns the path of the snoozed notifications file
+func SnoozeFile() string {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return filepath.Join(cfg.DataDir, snoozeFileName)
+}
+
+// Snooze holds a notification back and re-delivers it after the configured snooze
+// duration. subject is handed back on re-delivery, so the notification is about the
+// same thing it was the first time.
+func Snooze(kind, subject string) error {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+
+	snoozeMu.Lock()
+	defer snoozeMu.Unlock()
+
+	snoozes := loadSnoozes()
+	snoozes[kind] = snooze{Until: time.Now().Add(cfg.SnoozeDuration), Subject: subject}
+	Info("Snoozed", kind, "until", snoozes[kind].Until.Format("15:04"))
+	return saveSnoozes(snoozes)
+}
+
+// Snoozed reports whether a notification is snoozed and not yet due
+func Snoozed(kind string) bool {
+	snoozeMu.Lock()
+	defer snoozeMu.Unlock()
+
+	s, ok := loadSnoozes()[kind]
+	return ok && time.Now().Before(s.Until)
+}
+
+// dueSnoozes removes the snoozes that have run out and returns them
+func dueSnoozes(now time.Time) []DueSnooze {
+	snoozeMu.Lock()
+	defer snoozeMu.Unlock()
+
+	snoozes := loadSnoozes()
+	var due []DueSnooze
+	for kind, s := range snoozes {
+		if !now.Before(s.Until) {
+			due = append(due, DueSnooze{Kind: kind, Subject: s.Subject})
+			delete(snoozes, kind)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Kind < due[j].Kind })
+
+	if err := saveSnoozes(snoozes); err != nil {
+		Warn("Failed to save snoozed notifications:", err)
+	}
+	return due
+}
+
+// loadSnoozes reads the snooze file, empty if it is missing or unreadable
+func loadSnoozes() map[string]snooze {
+	snoozes := make(map[string]snooze)
+	data, err := os.ReadFile(SnoozeFile())
+	if err != nil {
+		return snoozes
+	}
+	if err := json.Unmarshal(data, &snoozes); err != nil {
+		Warn("Ignoring unreadable snooze file:", err)
+		return make(map[string]snooze)
+	}
+	return snoozes
+}
+
+// saveSnoozes writes the snooze file, removing it once nothing is snoozed
+func saveSnoozes(snoozes map[string]snooze) error {
+	if len(snoozes) == 0 {
+		if err := os.Remove(SnoozeFile()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(snoozes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(SnoozeFile(), data, 0644)
+}
@@ -6,9 +6,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
+	"RESPAWN/internal/i18n"
 	"RESPAWN/internal/types"
 	"RESPAWN/pkg/config"
 )
@@ -36,6 +39,24 @@ type CrashTracker struct {
     windowPeriod time.Duration
     isDisabled   bool
     stateFile    string
+    history      []CrashRecord // Newest last, kept beyond the window for `respawn crashes`
+}
+
+// CrashRecord is one recorded crash
+type CrashRecord struct {
+    Time   time.Time `json:"time"`
+    Reason string    `json:"reason"`
+    Report string    `json:"report,omitempty"` // Path of the crash report file, if one was written
+}
+
+// maxCrashHistory is how many crash records are kept
+const maxCrashHistory = 50
+
+// crashState is the part of the crash tracker saved between runs
+type crashState struct {
+    Crashes  []time.Time   `json:"crashes"`
+    History  []CrashRecord `json:"history,omitempty"`
+    Disabled bool          `json:"disabled"`
 }
 
 // RestartPolicy defines restart behavior
@@ -46,6 +67,48 @@ type RestartPolicy struct {
     LastCrashTime   time.Time
 }
 
+// Runtime state files, kept in the data directory
+const (
+    lockFileName       = "respawn.lock"
+    pidFileName        = "respawn.pid"
+    crashStateFileName = "crash_state.json"
+    crashReportsDir    = "crash_reports"
+)
+
+// PIDFile returns the path of the daemon's PID file
+func PIDFile() string {
+    cfg := config.Current()
+    if cfg == nil {
+        cfg = config.DefaultConfig()
+    }
+    return filepath.Join(cfg.DataDir, pidFileName)
+}
+
+// migrateRuntimeState moves state files left next to the executable by older
+// versions into the data directory. Files already in the data directory win.
+func migrateRuntimeState(oldDir, newDir string) {
+    if oldDir == newDir {
+        return
+    }
+
+    for _, name := range []string{lockFileName, pidFileName, crashStateFileName, crashReportsDir} {
+        oldPath := filepath.Join(oldDir, name)
+        newPath := filepath.Join(newDir, name)
+        if _, err := os.Stat(oldPath); err != nil {
+            continue
+        }
+        if _, err := os.Stat(newPath); err == nil {
+            continue
+        }
+
+        if err := os.Rename(oldPath, newPath); err != nil {
+            Warn("Failed to migrate", oldPath, "to", newDir, ":", err)
+            continue
+        }
+        Info("Migrated", name, "to", newDir)
+    }
+}
+
 //NewStartupManager creates a new startup manager 
 func NewStartupManager() (*StartupManager, error) {
     // Get the executable path
@@ -54,25 +117,34 @@ func NewStartupManager() (*StartupManager, error) {
         return nil, fmt.Errorf("failed to get executable path: %w", err)
     }
     
-    // Get the base directory (where the executable lives)
-    baseDir := filepath.Dir(execPath)
+    // Runtime state lives in the data directory; the executable's directory is
+    // often read-only and shared, e.g. /usr/local/bin
+    cfg := config.Current()
+    if cfg == nil {
+        cfg = config.DefaultConfig()
+    }
+    baseDir := cfg.DataDir
+    if err := os.MkdirAll(baseDir, 0755); err != nil {
+        return nil, fmt.Errorf("failed to create data directory: %w", err)
+    }
+    migrateRuntimeState(filepath.Dir(execPath), baseDir)
 
     // create macOS auto-start manager
     autoStart := NewMacOSAutoStart(execPath)
 
     // Initialize instance lock
     instanceLock := &InstanceLock{
-        lockFile: filepath.Join(baseDir, "respawn.lock"),
-        pidFile:  filepath.Join(baseDir, "respawn.pid"),
+        lockFile: filepath.Join(baseDir, lockFileName),
+        pidFile:  filepath.Join(baseDir, pidFileName),
         pid:      os.Getpid(),
     }
 
     // Initialize crash tracker
     crashTracker := &CrashTracker{
         crashes:      make([]time.Time, 0),
-        maxCrashes:   3, // Disable after 3 crashes
-        windowPeriod: 1 * time.Hour,
-        stateFile:    filepath.Join(baseDir, "crash_state.json"),	
+        maxCrashes:   cfg.CrashThreshold,
+        windowPeriod: cfg.CrashWindow,
+        stateFile:    filepath.Join(baseDir, crashStateFileName),
     }
 
     if err := crashTracker.Load(); err != nil {
@@ -94,6 +166,11 @@ func NewStartupManager() (*StartupManager, error) {
 func (sm *StartupManager) EnsureSingleInstance() error {
 	Debug("Checking for existing RESPAWN instance")
 
+	// Another user's state can't be ours to lock, refuse instead of fighting over it
+	if err := CheckDataDirOwner(sm.baseDir); err != nil {
+		return err
+	}
+
 	// Check if lock file exists
 	if _, err := os.Stat(sm.instanceLock.lockFile); err == nil {
 	// Lock file exists, check if process is still running
@@ -125,14 +202,24 @@ func (sm *StartupManager) EnsureSingleInstance() error {
 	return nil 
 }
 
+// SetExecutablePath makes Install point the LaunchAgent at path instead of the running binary
+func (sm *StartupManager) SetExecutablePath(path string) {
+	sm.executablePath = path
+	sm.autoStart.executablePath = path
+}
+
 // Install sets up auto-start for RESPAWN
 func (sm *StartupManager) Install() error {
 	Info("Installing RESPAWN auto-start for macOS")
 
-	// Check if already installed
+	// Check if already installed, rewriting the plist if it runs another binary
 	if sm.autoStart.IsInstalled() {
-		Info("RESPAWN auto-start already installed")
-		return nil 
+		if sm.autoStart.PointsAtExecutable() {
+			Info("RESPAWN auto-start already installed")
+			return nil
+		}
+		Info("Pointing RESPAWN auto-start at", sm.autoStart.executablePath)
+		sm.autoStart.Disable()
 	}
 
 	// Install auto-start
@@ -146,9 +233,9 @@ func (sm *StartupManager) Install() error {
 	}
 
 	Info("RESPAWN auto-start installed successfully")
-	fmt.Println("✅ RESPAWN auto-start configured")
-    fmt.Println("✅ Will start automatically on system login")
-    fmt.Println("✅ Target startup time: 7-8 seconds")
+	fmt.Fprintln(Stdout, "✅ RESPAWN auto-start configured")
+    fmt.Fprintln(Stdout, "✅ Will start automatically on system login")
+    fmt.Fprintln(Stdout, "✅ Target startup time: 7-8 seconds")
     
     return nil
 }
@@ -163,11 +250,11 @@ func (sm *StartupManager) Uninstall() error {
 	}
 
 	if err := sm.autoStart.Uninstall(); err != nil {
-		return fmt.Errorf("Failed to uninstall auto-start: %w", &err)
+		return fmt.Errorf("Failed to uninstall auto-start: %w", err)
 	}
 
 	Info("RESPAWN auto-start uninstalled successfully")
-	fmt.Println("✅ RESPAWN auto-start removed")
+	fmt.Fprintln(Stdout, "✅ RESPAWN auto-start removed")
 
 	return nil 
 }
@@ -189,7 +276,7 @@ func (sm *StartupManager) EnableAutoStart() error {
 	sm.crashTracker.Save()
 
 	Info("RESPAWN auto-start enabled")
-	fmt.Println("✅ Auto-start enabled ")
+	fmt.Fprintln(Stdout, "✅ Auto-start enabled ")
 
 	return nil 
 }
@@ -203,11 +290,11 @@ func (sm *StartupManager) DisableAutoStart() error {
 	}
 
 	if err := sm.autoStart.Disable(); err != nil {
-		return fmt.Errorf("Failed to disable auto-start: %w", &err)
+		return fmt.Errorf("Failed to disable auto-start: %w", err)
 	}
 
 	Info("RESPAWN auto-start disabled")
-	fmt.Println("✅ Auto-start disabled ")
+	fmt.Fprintln(Stdout, "✅ Auto-start disabled ")
 
 	return nil
 }
@@ -249,7 +336,7 @@ func (sm *StartupManager) StartWithPolicy() error {
 	case err := <-initChan:
 		if err != nil {
 			Error("Initialization failed:", err)
-			sm.recordCrash()
+			sm.recordCrash("initialization failed", err.Error())
 			sm.showErrorDialog("RESPAWN Initialization Failed", err.Error())
 			return err 
 		}
@@ -296,20 +383,26 @@ func (sm *StartupManager) initialize() error {
 func (sm *StartupManager) checkMacOSPermissions() error {
 	Debug("Checking macOS permissions")
 
-	// Check Accessibility permission (CRITICAL)
+	// Check Accessibility permission - without it we run in degraded mode
 	hasAccessibility := sm.hasAccessibilityPermission()
 	if !hasAccessibility {
-		Warn("Accessibility permission not granted")
-		sm.showPermissionDialog(
-			"Accessibility Access Required",
-			"RESPAWN needs Accessibility access to detect window states. \n\n"+
-				"Please grant permission in:\nSystem Preferences -> Security & Privacy -> Privacy -> Accessibility",
+		Warn("Accessibility permission not granted - running in degraded mode (basic checkpoints, no window state)")
+		SetDegradedMode(true)
+		if err := OpenPermissionPane(PermissionAccessibility); err != nil {
+			Debug("Could not open Accessibility pane:", err)
+		}
+
+		// Don't block startup on the dialog
+		go sm.showPermissionDialog(
+			"Accessibility Access Recommended",
+			"RESPAWN is running in basic mode: it will remember your apps but not their window states.\n\n"+
+				"For full restoration grant permission in:\nSystem Settings -> Privacy & Security -> Accessibility",
 		)
-		return fmt.Errorf("Accessibility permission required")
+	} else {
+		SetDegradedMode(false)
+		Info("Accessibility permission granted")
 	}
 
-	Info("Accessibility permission granted")
-
 	// Check full Disk Access (OPTIONAL)
 	hasFullDisk := sm.hasFullDiskAccess()
 	if !hasFullDisk {
@@ -353,12 +446,15 @@ func (sm *StartupManager) hasFullDiskAccess() bool {
 	return err == nil 
 }
 
-//recordCrash records a crash event
-func (sm *StartupManager) recordCrash() {
-	sm.crashTracker.RecordCrash()
+//recordCrash records a crash event and writes a report with the details
+func (sm *StartupManager) recordCrash(reason, details string) {
+	sm.crashTracker.RecordCrashReport(reason, details)
+	Emit(EventDaemonCrashed, map[string]interface{}{
+		"reason": reason,
+	})
 
 	if sm.crashTracker.ShouldDisableAutoStart() {
-		Error("Crash threshold exceeded (3 crashes in 1 hour) - disabling auto-start")
+		Error("Crash threshold exceeded (", sm.crashTracker.maxCrashes, "crashes in", sm.crashTracker.windowPeriod, ") - disabling auto-start")
 		sm.DisableAutoStart()
 	}
 }
@@ -385,7 +481,7 @@ func (sm *StartupManager) RestartWithBackoff(policy *types.RestartPolicy) error
 	policy.LastCrashTime = time.Now()
 
 	// Attempt restart
-	cmd := exec.Command(sm.executablePath, "--start")
+	cmd := exec.Command(sm.executablePath, "start")
 	if err := cmd.Start(); err != nil {
 		Error ("Failed to restart RESPAWN:", err)
 		return sm.RestartWithBackoff(policy)
@@ -412,15 +508,49 @@ func GetDefaultRestartPolicy() *types.RestartPolicy {
 
 // RecordCrash records a new crash
 func (ct *CrashTracker) RecordCrash() {
+	ct.RecordCrashReport("", "")
+}
+
+// RecordCrashReport records a new crash, writing details to a crash report file when given
+func (ct *CrashTracker) RecordCrashReport(reason, details string) {
 	now := time.Now()
 	ct.crashes = append(ct.crashes, now)
 
+	record := CrashRecord{Time: now, Reason: reason}
+	if details != "" {
+		if path, err := ct.writeReport(record, details); err != nil {
+			Warn("Failed to write crash report:", err)
+		} else {
+			record.Report = path
+		}
+	}
+	ct.history = append(ct.history, record)
+	if len(ct.history) > maxCrashHistory {
+		ct.history = ct.history[len(ct.history)-maxCrashHistory:]
+	}
+
 	// Remove crashes outside the window period
 	ct.cleanOldCrashes()
 
 	ct.Save()
 
-	Warn("Crash recorded. Total crashes in last hour:", len(ct.crashes))
+	Warn("Crash recorded. Total crashes in last", ct.windowPeriod, ":", len(ct.crashes))
+}
+
+// writeReport saves a crash's details next to the crash state and returns the file path
+func (ct *CrashTracker) writeReport(record CrashRecord, details string) (string, error) {
+	reportDir := filepath.Join(filepath.Dir(ct.stateFile), crashReportsDir)
+	if err := os.MkdirAll(reportDir, 0755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(reportDir, fmt.Sprintf("crash_%s.txt", record.Time.Format("20060102_150405")))
+	report := fmt.Sprintf("RESPAWN crash report\nTime: %s\nPID: %d\nReason: %s\n\n%s\n",
+		record.Time.Format(time.RFC3339), os.Getpid(), record.Reason, details)
+	if err := os.WriteFile(path, []byte(report), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
 }
 
 // ShouldDisableAutoStart checks if auto-start should be disabled 
@@ -434,7 +564,7 @@ func (ct *CrashTracker) ShouldDisableAutoStart() bool {
 	if len(ct.crashes) >= ct.maxCrashes {
 		ct.isDisabled = true
 		ct.Save()
-		Error("Crash threshold reached:", len(ct.crashes), "crashes in last hour")
+		Error("Crash threshold reached:", len(ct.crashes), "crashes in last", ct.windowPeriod)
 		return true
 	}
 
@@ -455,9 +585,16 @@ func (ct *CrashTracker) cleanOldCrashes() {
 	ct.crashes = validCrashes
 }
 
+// Reset forgets recorded crashes and clears the disabled state
+func (ct *CrashTracker) Reset() {
+	ct.crashes = make([]time.Time, 0)
+	ct.history = nil
+	ct.isDisabled = false
+}
+
 // Save saves crash tracker state
 func (ct *CrashTracker) Save() error {
-	data, err := json.MarshalIndent(ct, "", " ")
+	data, err := json.MarshalIndent(crashState{Crashes: ct.crashes, History: ct.history, Disabled: ct.isDisabled}, "", " ")
 	if err != nil {
 		return err
 	}
@@ -470,7 +607,28 @@ func (ct *CrashTracker) Load() error {
 	if err != nil {
 		return err 
 	}
-	return json.Unmarshal(data, ct)
+
+	var state crashState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	ct.crashes = state.Crashes
+	ct.history = state.History
+	ct.isDisabled = state.Disabled
+
+	// Reports migrated from the executable's directory keep their file name
+	for i, record := range ct.history {
+		if record.Report == "" {
+			continue
+		}
+		if _, err := os.Stat(record.Report); err != nil {
+			moved := filepath.Join(filepath.Dir(ct.stateFile), crashReportsDir, filepath.Base(record.Report))
+			if _, err := os.Stat(moved); err == nil {
+				ct.history[i].Report = moved
+			}
+		}
+	}
+	return nil
 }
 
 // Helper methods
@@ -490,8 +648,8 @@ func (sm *StartupManager) isProcessRunning(pid int) bool {
 //showPermissionDialog shows a permission 
 func (sm *StartupManager) showPermissionDialog(title, message string) {
 	script := fmt.Sprintf(`
-        display dialog "%s" with title "%s" buttons {"OK"} default button "OK" with icon caution
-    `, strings.ReplaceAll(message, `"`, `\"`), title)
+        display dialog "%s" with title "%s" buttons {"%s"} default button 1 with icon caution
+    `, strings.ReplaceAll(message, `"`, `\"`), title, i18n.T("dialog.ok"))
 
 	cmd:= exec.Command("osascript", "-e", script)
 	if err := cmd.Run(); err != nil {
@@ -504,8 +662,8 @@ func (sm *StartupManager) showErrorDialog(title, message string) {
 	Error(title, ":", message)
 
 	script := fmt.Sprintf(`
-        display dialog "%s" with title "%s" buttons {"OK"} default button "OK" with icon stop
-    `, strings.ReplaceAll(message, `"`, `\"`), title)
+        display dialog "%s" with title "%s" buttons {"%s"} default button 1 with icon stop
+    `, strings.ReplaceAll(message, `"`, `\"`), title, i18n.T("dialog.ok"))
 
 	cmd := exec.Command("osascript", "-e", script)
 	cmd.Run()
@@ -513,14 +671,102 @@ func (sm *StartupManager) showErrorDialog(title, message string) {
 
 // showCrashNotification shows crash notification to user
 func (sm *StartupManager) showCrashNotification() {
-	message := fmt.Sprintf(
-        "RESPAWN has crashed %d times in the last hour.\n\n"+
-            "Auto-start has been disabled for safety.\n\n"+
-            "To re-enable:\nOpen Terminal and run: respawn --enable-autostart",
-        sm.crashTracker.maxCrashes,
-    )
-
-	sm.showPermissionDialog("RESPAWN Auto-start disabled", message)
+	message := i18n.T("dialog.crash", sm.crashTracker.maxCrashes, sm.crashTracker.windowPeriod)
+
+	sm.showPermissionDialog(i18n.T("dialog.crash_title"), message)
+}
+
+// RecordPanic records a daemon panic as a crash, with its stack in the crash report
+func (sm *StartupManager) RecordPanic(value interface{}, stack []byte) {
+	sm.recordCrash(fmt.Sprintf("panic: %v", value), string(stack))
+}
+
+// panicHandler is what RecordPanics reports to, see SetPanicHandler
+var panicHandler atomic.Pointer[func(value interface{}, stack []byte)]
+
+// SetPanicHandler sets the function RecordPanics reports a panic to, e.g. RecordPanic
+func SetPanicHandler(handler func(value interface{}, stack []byte)) {
+	panicHandler.Store(&handler)
+}
+
+// RecordPanics reports a panic to the panic handler, then lets it crash the process.
+// recover only sees panics in its own goroutine, so every long-lived goroutine of
+// the daemon defers it first.
+func RecordPanics() {
+	if r := recover(); r != nil {
+		if handler := panicHandler.Load(); handler != nil {
+			(*handler)(r, debug.Stack())
+		}
+		panic(r)
+	}
+}
+
+// CrashStatus returns the crashes within the crash window, the window itself, and
+// whether auto-start was disabled because of them
+func (sm *StartupManager) CrashStatus() (recent int, window time.Duration, disabled bool) {
+	sm.crashTracker.cleanOldCrashes()
+	return len(sm.crashTracker.crashes), sm.crashTracker.windowPeriod, sm.crashTracker.isDisabled
+}
+
+// recentCrashes counts the crashes recorded within the last period, reading the
+// crash state directly so the monitor doesn't need a StartupManager
+func recentCrashes(period time.Duration) int {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	ct := &CrashTracker{stateFile: filepath.Join(cfg.DataDir, crashStateFileName)}
+	if err := ct.Load(); err != nil {
+		return 0
+	}
+
+	cutoff := time.Now().Add(-period)
+	count := 0
+	for _, record := range ct.history {
+		if record.Time.After(cutoff) {
+			count++
+		}
+	}
+	return count
+}
+
+// CrashHistory returns recorded crashes, newest first
+func (sm *StartupManager) CrashHistory() []CrashRecord {
+	history := make([]CrashRecord, len(sm.crashTracker.history))
+	for i, record := range sm.crashTracker.history {
+		history[len(history)-1-i] = record
+	}
+	return history
+}
+
+// ResetCrashes clears the crash history and, if crashes had turned auto-start off,
+// turns it back on
+func (sm *StartupManager) ResetCrashes() (bool, error) {
+	wasDisabled := sm.crashTracker.isDisabled
+	sm.crashTracker.Reset()
+	if err := sm.crashTracker.Save(); err != nil {
+		return false, fmt.Errorf("failed to save crash state: %w", err)
+	}
+
+	if wasDisabled && sm.autoStart.IsInstalled() && !sm.autoStart.IsEnabled() {
+		if err := sm.autoStart.Enable(); err != nil {
+			return false, fmt.Errorf("crash history cleared but auto-start could not be re-enabled: %w", err)
+		}
+		Info("Auto-start re-enabled after crash reset")
+		return true, nil
+	}
+	return false, nil
+}
+
+// PurgeStateFiles removes the lock, PID and crash state files and returns the paths deleted
+func (sm *StartupManager) PurgeStateFiles() []string {
+	var deleted []string
+	for _, path := range []string{sm.instanceLock.lockFile, sm.instanceLock.pidFile, sm.crashTracker.stateFile} {
+		if err := os.Remove(path); err == nil {
+			deleted = append(deleted, path)
+		}
+	}
+	return deleted
 }
 
 // ReleaseLock releases the instance lock
@@ -3,7 +3,9 @@
 package system
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,6 +14,7 @@ import (
 
 type MacOSAutoStart struct {
     executablePath string
+    label          string
     plistPath      string
 }
 
@@ -20,11 +23,13 @@ const launchAgentPlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 <plist version="1.0">
 <dict>
     <key>Label</key>
-    <string>com.respawn.agent</string>
+    <string>{{.Label}}</string>
     <key>ProgramArguments</key>
     <array>
         <string>{{.ExecutablePath}}</string>
-        <string>--start</string>
+        {{- range .Arguments}}
+        <string>{{.}}</string>
+        {{- end}}
     </array>
     <key>RunAtLoad</key>
     <true/>
@@ -37,23 +42,29 @@ const launchAgentPlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
     </dict>
     <key>ThrottleInterval</key>
     <integer>10</integer>
+    {{- if .LogPath}}
     <key>StandardOutPath</key>
     <string>{{.LogPath}}/respawn_stdout.log</string>
     <key>StandardErrorPath</key>
     <string>{{.LogPath}}/respawn_stderr.log</string>
+    {{- end}}
 </dict>
 </plist>`
 
 func NewMacOSAutoStart(execPath string) *MacOSAutoStart {
-	homeDir, _ := os.UserHomeDir()
-	plistPath := filepath.Join(homeDir, "Library/LaunchAgents/com.respawn.agent.plist")
-
 	return &MacOSAutoStart{
 		executablePath: execPath,
-		plistPath:  	plistPath,
+		label:          LaunchAgentLabel(),
+		plistPath:  	LaunchAgentPath(),
 	}
 }
 
+// LaunchAgentPath returns where `respawn install` writes the LaunchAgent plist
+func LaunchAgentPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, "Library/LaunchAgents", LaunchAgentLabel()+".plist")
+}
+
 func (m *MacOSAutoStart) Install() error {
 	Debug("Installing macOS LaunchAgent")
 
@@ -62,35 +73,62 @@ func (m *MacOSAutoStart) Install() error {
 	if err := os.MkdirAll(launchAgentsDir, 0755); err != nil {
 		return fmt.Errorf("Failed to create LaunchAgents directory: %w", err)
 	}
+	m.removeLegacyAgent()
 
-	// Create plist file from template
-	tmpl, err := template.New("plist").Parse(launchAgentPlistTemplate)
+	// launchd won't create the directory the plist sends stdout and stderr to
+	logDir, err := LogDir()
 	if err != nil {
-		return fmt.Errorf("Failed to parse plist template: %w", err)
+		return err
+	}
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return fmt.Errorf("Failed to create log directory: %w", err)
 	}
 
+	// Create plist file from template
 	file, err := os.Create(m.plistPath)
 	if err != nil {
 		return fmt.Errorf("Failed to create plist file: %w", err)
 	}
 	defer file.Close()
 
-	homeDir, _ := os.UserHomeDir()
-	logPath := filepath.Join(homeDir, ".respawn/logs")
+	if err := WriteLaunchAgentPlist(file, m.label, m.executablePath, "start"); err != nil {
+		return err
+	}
+
+	Debug("LaunchAgent plist created at:", m.plistPath)
+	return nil
+}
+
+// WriteLaunchAgentPlist writes a LaunchAgent plist running executablePath with args
+// under label, logging to the configured log_dir
+func WriteLaunchAgentPlist(w io.Writer, label, executablePath string, args ...string) error {
+	logPath, _ := LogDir()
+	return writePlist(w, label, executablePath, logPath, args)
+}
+
+// writePlist renders the LaunchAgent template; an empty logPath leaves stdout and
+// stderr uncaptured
+func writePlist(w io.Writer, label, executablePath, logPath string, args []string) error {
+	tmpl, err := template.New("plist").Parse(launchAgentPlistTemplate)
+	if err != nil {
+		return fmt.Errorf("Failed to parse plist template: %w", err)
+	}
 
 	data := struct {
+		Label           string
 		ExecutablePath  string
+		Arguments       []string
 		LogPath         string
 	}{
-		ExecutablePath: m.executablePath,
+		Label:          label,
+		ExecutablePath: executablePath,
+		Arguments:      args,
 		LogPath: 		logPath,
 	}
 
-	if err := tmpl.Execute(file, data); err != nil {
+	if err := tmpl.Execute(w, data); err != nil {
 		return fmt.Errorf("Failed to write plist file: %w", err)
 	}
-
-	Debug("LaunchAgent plist created at:", m.plistPath)
 	return nil
 }
 
@@ -99,6 +137,7 @@ func (m *MacOSAutoStart) Uninstall() error {
 
 	// Unload first if loaded
 	m.Disable()
+	m.removeLegacyAgent()
 
 	// Remove plist file
 	if err := os.Remove(m.plistPath); err != nil && !os.IsNotExist(err) {
@@ -139,13 +178,42 @@ func (m *MacOSAutoStart) IsInstalled() bool {
 	return err == nil
 }
 
+// PointsAtExecutable reports whether the installed plist runs m's executable, false
+// once the binary was moved or another one was asked for
+func (m *MacOSAutoStart) PointsAtExecutable() bool {
+	data, err := os.ReadFile(m.plistPath)
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(data, []byte("<string>"+m.executablePath+"</string>"))
+}
+
 func (m *MacOSAutoStart) IsEnabled() bool {
 	// Check if LaunchAgent is loaded
-	cmd := exec.Command("launchctl", "list", "com.respawn.agent")
+	cmd := exec.Command("launchctl", "list", m.label)
 	err := cmd.Run()
 	return err == nil
 }
 
+// removeLegacyAgent unloads and deletes the agent installed under the label used
+// before labels were per-user
+func (m *MacOSAutoStart) removeLegacyAgent() {
+	legacyPath := filepath.Join(filepath.Dir(m.plistPath), legacyLaunchAgentLabel+".plist")
+	if legacyPath == m.plistPath {
+		return
+	}
+	if _, err := os.Stat(legacyPath); err != nil {
+		return
+	}
+
+	exec.Command("launchctl", "unload", legacyPath).Run() // Ignore errors - might not be loaded
+	if err := os.Remove(legacyPath); err != nil {
+		Warn("Failed to remove old LaunchAgent", legacyPath, ":", err)
+		return
+	}
+	Info("Replaced LaunchAgent", legacyLaunchAgentLabel, "with", m.label)
+}
+
 
 
 
@@ -0,0 +1,81 @@
+package system
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"RESPAWN/pkg/config"
+)
+
+// stdLogMaxBytes is how large a launchd-captured log may grow before maintenance
+// truncates it, keeping the previous contents as a single .1 backup
+const stdLogMaxBytes = 10 << 20
+
+// stdLogNames are the files the LaunchAgent and Daemonize send stdout and stderr to
+var stdLogNames = []string{"respawn_stdout.log", "respawn_stderr.log"}
+
+// LogDir returns the configured log_dir, where the log and the captured output live
+func LogDir() (string, error) {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	if cfg.LogDir == "" {
+		return "", fmt.Errorf("no log directory configured")
+	}
+	return cfg.LogDir, nil
+}
+
+// RotateStdLogs truncates captured stdout and stderr logs that outgrew stdLogMaxBytes.
+// launchd keeps them open in append mode, so they are copied aside and truncated in
+// place; renaming would leave launchd writing to the backup.
+func RotateStdLogs() error {
+	logDir, err := LogDir()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range stdLogNames {
+		path := filepath.Join(logDir, name)
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if info.Size() <= stdLogMaxBytes {
+			continue
+		}
+
+		if err := copyFile(path, path+".1"); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", name, err)
+		}
+		if err := os.Truncate(path, 0); err != nil {
+			return fmt.Errorf("failed to truncate %s: %w", name, err)
+		}
+		Info("Rotated", name, "at", info.Size()>>20, "MB")
+	}
+	return nil
+}
+
+// copyFile copies src over dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,106 @@
+//go:build darwin
+
+package system
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"RESPAWN/pkg/config"
+)
+
+// A system-wide install serves fleets deployed through MDM: one LaunchAgent in
+// /Library/LaunchAgents starts a daemon in every user's session, and a config
+// template in config.SystemConfigDir seeds each user's settings. It is a LaunchAgent
+// rather than a LaunchDaemon because restoring apps needs the user's GUI session;
+// data directories, PID files and logs stay per-user.
+
+// SystemLaunchAgentLabel is the launchd label of the system-wide agent
+const SystemLaunchAgentLabel = "com.respawn.agent.managed"
+
+// SystemLaunchAgentPath is where the system-wide agent plist lives
+var SystemLaunchAgentPath = filepath.Join("/Library/LaunchAgents", SystemLaunchAgentLabel+".plist")
+
+// ErrNeedsRoot means a system-wide change was attempted without root
+var ErrNeedsRoot = errors.New("system-wide install needs root, run it with sudo")
+
+// WriteSystemLaunchAgentPlist writes the system-wide agent plist running executablePath.
+// It has no log paths, launchd doesn't expand ~ and the daemon keeps its own log.
+func WriteSystemLaunchAgentPlist(w io.Writer, executablePath string) error {
+	return writePlist(w, SystemLaunchAgentLabel, executablePath, "", []string{"start"})
+}
+
+// InstallSystemWide writes the system-wide agent and, unless one is deployed
+// already, a config template with the defaults. launchd starts the agent for each
+// user at their next login.
+func InstallSystemWide(executablePath string) error {
+	if os.Geteuid() != 0 {
+		return ErrNeedsRoot
+	}
+
+	file, err := os.OpenFile(SystemLaunchAgentPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to create plist file: %w", err)
+	}
+	if err := WriteSystemLaunchAgentPlist(file, executablePath); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("Failed to write plist file: %w", err)
+	}
+	Info("System-wide LaunchAgent written to", SystemLaunchAgentPath)
+
+	if err := os.MkdirAll(config.SystemConfigDir, 0755); err != nil {
+		return fmt.Errorf("Failed to create %s: %w", config.SystemConfigDir, err)
+	}
+	if _, err := os.Stat(config.SystemConfigTemplate); err == nil {
+		Info("Keeping the deployed config template", config.SystemConfigTemplate)
+		return nil
+	}
+	return writeConfigTemplate(config.SystemConfigTemplate)
+}
+
+// UninstallSystemWide removes the system-wide agent. The config template is left
+// for the administrator, it may be managed by MDM.
+func UninstallSystemWide() error {
+	if os.Geteuid() != 0 {
+		return ErrNeedsRoot
+	}
+	if err := os.Remove(SystemLaunchAgentPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to remove plist file: %w", err)
+	}
+	Info("System-wide LaunchAgent removed")
+	return nil
+}
+
+// SystemWideInstalled reports whether the system-wide agent is installed
+func SystemWideInstalled() bool {
+	_, err := os.Stat(SystemLaunchAgentPath)
+	return err == nil
+}
+
+// writeConfigTemplate writes the default config without the per-user paths, as a
+// starting point for the administrator to edit. It runs under sudo, so the defaults
+// would otherwise point into root's home.
+func writeConfigTemplate(path string) error {
+	cfg := config.DefaultConfig()
+	cfg.DataDir = ""
+	cfg.LogDir = ""
+	cfg.APIAddress = ""
+	cfg.ConfigPath = ""
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config template: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config template: %w", err)
+	}
+	Info("Config template written to", path)
+	return nil
+}
@@ -0,0 +1,146 @@
+package system
+
+import (
+    "sort"
+    "strings"
+    "time"
+)
+
+// StartupBudget is how long daemon initialization may take before it counts as slow
+const StartupBudget = 8 * time.Second
+
+// maxStartupRecords is how many past startups are kept in metrics
+const maxStartupRecords = 10
+
+// StartupPhase is how long one initialization step took
+type StartupPhase struct {
+    Name     string        `json:"name"`
+    Duration time.Duration `json:"duration"`
+}
+
+// StartupTiming records one daemon startup
+type StartupTiming struct {
+    StartedAt time.Time      `json:"started_at"`
+    Total     time.Duration  `json:"total"`
+    Phases    []StartupPhase `json:"phases"`
+}
+
+// OverBudget reports whether the startup took longer than StartupBudget
+func (t StartupTiming) OverBudget() bool {
+    return t.Total > StartupBudget
+}
+
+// PhaseTimer measures consecutive initialization phases
+type PhaseTimer struct {
+    timing StartupTiming
+    last   time.Time
+}
+
+// NewPhaseTimer starts timing a startup
+func NewPhaseTimer() *PhaseTimer {
+    now := time.Now()
+    return &PhaseTimer{timing: StartupTiming{StartedAt: now}, last: now}
+}
+
+// Mark ends the current phase under name and starts the next one
+func (pt *PhaseTimer) Mark(name string) {
+    now := time.Now()
+    pt.timing.Phases = append(pt.timing.Phases, StartupPhase{Name: name, Duration: now.Sub(pt.last)})
+    pt.last = now
+}
+
+// Finish returns the timing of every phase marked so far
+func (pt *PhaseTimer) Finish() StartupTiming {
+    pt.timing.Total = pt.last.Sub(pt.timing.StartedAt)
+    return pt.timing
+}
+
+// RecordStartup adds a startup timing to the metrics, keeping the most recent ones
+func (sm *SystemMonitor) RecordStartup(timing StartupTiming) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+
+    sm.metrics.StartupTimings = append(sm.metrics.StartupTimings, timing)
+    if len(sm.metrics.StartupTimings) > maxStartupRecords {
+        sm.metrics.StartupTimings = sm.metrics.StartupTimings[len(sm.metrics.StartupTimings)-maxStartupRecords:]
+    }
+    sm.persistMetrics()
+}
+
+// maxCheckpointSamples is how many past checkpoint sizes are kept in metrics
+const maxCheckpointSamples = 100
+
+// RecordCheckpointSize adds a checkpoint's size and whether its apps changed to the
+// metrics, and updates the disk growth rate from the recent samples
+func (sm *SystemMonitor) RecordCheckpointSize(size int64, appNames []string) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+
+    sorted := append([]string(nil), appNames...)
+    sort.Strings(sorted)
+    appSet := strings.Join(sorted, "\n")
+
+    samples := append(sm.metrics.CheckpointSamples, CheckpointSample{
+        At:      time.Now(),
+        Size:    size,
+        Changed: appSet != sm.metrics.LastAppSet,
+    })
+    if len(samples) > maxCheckpointSamples {
+        samples = samples[len(samples)-maxCheckpointSamples:]
+    }
+    sm.metrics.CheckpointSamples = samples
+    sm.metrics.LastAppSet = appSet
+    sm.metrics.DiskGrowthRate = diskGrowthRate(samples)
+    sm.persistMetrics()
+}
+
+// RecordCheckpointsRemoved adds the space freed by deleting checkpoints to the
+// metrics, so the disk growth rate is net of cleanup and pruning
+func (sm *SystemMonitor) RecordCheckpointsRemoved(bytes int64) {
+    if bytes <= 0 {
+        return
+    }
+
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+
+    samples := append(sm.metrics.CheckpointSamples, CheckpointSample{At: time.Now(), Size: bytes, Removed: true})
+    if len(samples) > maxCheckpointSamples {
+        samples = samples[len(samples)-maxCheckpointSamples:]
+    }
+    sm.metrics.CheckpointSamples = samples
+    sm.metrics.DiskGrowthRate = diskGrowthRate(samples)
+    sm.persistMetrics()
+}
+
+// diskGrowthRate is how many MB per week the samples add up to, net of removals and
+// negative when more was freed than written, 0 until they span some time
+func diskGrowthRate(samples []CheckpointSample) float64 {
+    if len(samples) < 2 {
+        return 0
+    }
+    span := samples[len(samples)-1].At.Sub(samples[0].At)
+    if span < time.Hour {
+        return 0
+    }
+
+    var total int64
+    for _, sample := range samples[1:] {
+        if sample.Removed {
+            total -= sample.Size
+        } else {
+            total += sample.Size
+        }
+    }
+    week := 7 * 24 * time.Hour
+    return float64(total) / (1 << 20) * float64(week) / float64(span)
+}
+
+// LoadMetrics reads the metrics written by the daemon
+func LoadMetrics() (*OptimizationMetrics, error) {
+    sm := &SystemMonitor{baseDir: dataDir()}
+    if err := sm.loadMetrics(); err != nil {
+        return nil, err
+    }
+    return sm.metrics, nil
+}
@@ -0,0 +1,100 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// URLScheme is the scheme Shortcuts and Focus automations use to drive RESPAWN,
+// e.g. respawn://checkpoint or respawn://pause?for=2h
+const URLScheme = "respawn"
+
+const (
+	urlHandlerName     = "RESPAWN URL Handler"
+	urlHandlerBundleID = "com.ninsco.respawn.urlhandler"
+	lsregisterPath     = "/System/Library/Frameworks/CoreServices.framework/Frameworks/LaunchServices.framework/Support/lsregister"
+)
+
+// urlHandlerScript forwards every opened respawn:// URL to `respawn url <url>`
+const urlHandlerScript = `on open location theURL
+	do shell script quoted form of %q & " url " & quoted form of theURL
+end open location
+
+on run
+	display dialog "RESPAWN URL Handler runs respawn:// links from Shortcuts and automations." buttons {"OK"} default button 1
+end run
+`
+
+// URLHandlerPath returns where the URL handler applet is installed
+func URLHandlerPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, "Applications", urlHandlerName+".app")
+}
+
+// InstallURLHandler builds a small AppleScript applet that claims the respawn://
+// scheme and registers it with Launch Services. A plain binary can't own a URL
+// scheme - macOS only routes URLs to app bundles.
+func InstallURLHandler(executablePath string) error {
+	appPath := URLHandlerPath()
+	if err := os.MkdirAll(filepath.Dir(appPath), 0755); err != nil {
+		return fmt.Errorf("failed to create Applications directory: %w", err)
+	}
+
+	scriptFile, err := os.CreateTemp("", "respawn-url-*.applescript")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(scriptFile.Name())
+
+	if _, err := fmt.Fprintf(scriptFile, urlHandlerScript, executablePath); err != nil {
+		scriptFile.Close()
+		return err
+	}
+	scriptFile.Close()
+
+	os.RemoveAll(appPath)
+	if output, err := exec.Command("osacompile", "-o", appPath, scriptFile.Name()).CombinedOutput(); err != nil {
+		return fmt.Errorf("osacompile failed: %w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+
+	plist := filepath.Join(appPath, "Contents", "Info.plist")
+	for _, command := range []string{
+		"Set :CFBundleIdentifier " + urlHandlerBundleID,
+		"Add :LSUIElement bool true",
+		"Add :CFBundleURLTypes array",
+		"Add :CFBundleURLTypes:0 dict",
+		"Add :CFBundleURLTypes:0:CFBundleURLName string " + urlHandlerBundleID,
+		"Add :CFBundleURLTypes:0:CFBundleURLSchemes array",
+		"Add :CFBundleURLTypes:0:CFBundleURLSchemes:0 string " + URLScheme,
+	} {
+		if output, err := exec.Command("/usr/libexec/PlistBuddy", "-c", command, plist).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to update Info.plist (%s): %w (output: %s)", command, err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	if output, err := exec.Command(lsregisterPath, "-f", appPath).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to register URL scheme: %w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+
+	Info("Installed", URLScheme+":// handler at", appPath)
+	return nil
+}
+
+// UninstallURLHandler unregisters and removes the URL handler applet
+func UninstallURLHandler() error {
+	appPath := URLHandlerPath()
+	if _, err := os.Stat(appPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	exec.Command(lsregisterPath, "-u", appPath).Run() // Ignore errors - removing the bundle is what matters
+	if err := os.RemoveAll(appPath); err != nil {
+		return fmt.Errorf("failed to remove URL handler: %w", err)
+	}
+
+	Info("Removed", URLScheme+":// handler")
+	return nil
+}
@@ -0,0 +1,548 @@
+package team
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"RESPAWN/internal/system"
+	"RESPAWN/pkg/config"
+)
+
+// teamKeySize is the length of the shared key used to encrypt checkpoints shared with a team
+const teamKeySize = 32
+
+// Identity is this machine's member key pair
+type Identity struct {
+	Name       string `json:"name"`
+	PublicKey  string `json:"public_key"`  // base64 ed25519
+	PrivateKey string `json:"private_key"` // base64 ed25519
+}
+
+// Member is one person in a team
+type Member struct {
+	Name      string    `json:"name"`
+	PublicKey string    `json:"public_key,omitempty"` // Empty until an invited member joins
+	JoinedAt  time.Time `json:"joined_at,omitempty"`
+	Invited   bool      `json:"invited,omitempty"`
+}
+
+// Team is a group whose members share checkpoints
+type Team struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Key       string    `json:"key"` // base64 shared key, empty until the owner accepts this member
+	Owner     string    `json:"owner"`
+	Members   []Member  `json:"members"`
+	CreatedAt time.Time `json:"created_at"`
+	Pending   bool      `json:"pending,omitempty"` // Joined here, but the owner hasn't accepted this member yet
+
+	ExchangeKey string `json:"exchange_key,omitempty"` // base64 X25519 private key the team key is sent to while pending
+}
+
+// Store holds the local identity and team memberships
+type Store struct {
+	Identity Identity `json:"identity"`
+	Teams    []Team   `json:"teams"`
+
+	path string
+}
+
+// invite is the payload encoded in an invite code. The team in it has no key:
+// the key is only sent, encrypted, once the owner accepts the member.
+type invite struct {
+	Team      Team   `json:"team"`
+	Invitee   string `json:"invitee"`
+	Inviter   string `json:"inviter"`    // Inviter's public key
+	Signature string `json:"signature"`  // Inviter's signature over the team and invitee
+}
+
+// acceptance is the payload of the code a joining member sends back to the owner
+type acceptance struct {
+	TeamID      string `json:"team_id"`
+	Invitee     string `json:"invitee"`
+	PublicKey   string `json:"public_key"`   // The joining member's key
+	ExchangeKey string `json:"exchange_key"` // X25519 public key to encrypt the team key to
+	Signature   string `json:"signature"`    // By the member's key, proving the member holds it
+}
+
+// keyGrant is the payload of the code the owner sends back after accepting a member
+type keyGrant struct {
+	TeamID      string `json:"team_id"`
+	ExchangeKey string `json:"exchange_key"` // Owner's one-off X25519 public key
+	Sealed      string `json:"sealed"`       // Team key, AES-GCM sealed with the shared secret
+	Signature   string `json:"signature"`    // Owner's signature over the above
+}
+
+// Load reads the team store, creating an identity on first use
+func Load() (*Store, error) {
+	store := &Store{path: storePath()}
+
+	data, err := os.ReadFile(store.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read team store: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, store); err != nil {
+			return nil, fmt.Errorf("failed to parse team store: %w", err)
+		}
+	}
+
+	if store.Identity.PublicKey == "" {
+		if err := store.generateIdentity(); err != nil {
+			return nil, err
+		}
+		if err := store.Save(); err != nil {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
+
+// Save writes the team store; it holds private keys, so only the owner may read it
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0600)
+}
+
+// Create makes a new team with this machine as its owner and only member
+func (s *Store) Create(name string) (*Team, error) {
+	if s.Find(name) != nil {
+		return nil, fmt.Errorf("team %q already exists", name)
+	}
+
+	key := make([]byte, teamKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate team key: %w", err)
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate team ID: %w", err)
+	}
+
+	now := time.Now()
+	s.Teams = append(s.Teams, Team{
+		ID:        hex.EncodeToString(id),
+		Name:      name,
+		Key:       base64.StdEncoding.EncodeToString(key),
+		Owner:     s.Identity.PublicKey,
+		Members:   []Member{{Name: s.Identity.Name, PublicKey: s.Identity.PublicKey, JoinedAt: now}},
+		CreatedAt: now,
+	})
+
+	system.Info("Created team", name)
+	return &s.Teams[len(s.Teams)-1], nil
+}
+
+// Invite records an invited member and returns a signed invite code for them
+func (s *Store) Invite(teamName, invitee string) (string, error) {
+	team := s.Find(teamName)
+	if team == nil {
+		return "", fmt.Errorf("no team named %q", teamName)
+	}
+	if team.member(invitee) == nil {
+		team.Members = append(team.Members, Member{Name: invitee, Invited: true})
+	}
+
+	inv := invite{Team: *team, Invitee: invitee, Inviter: s.Identity.PublicKey}
+	inv.Team.Key = ""
+	signature, err := s.sign(inv.signedBytes())
+	if err != nil {
+		return "", err
+	}
+	inv.Signature = signature
+
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return "", err
+	}
+
+	system.Info("Invited", invitee, "to team", teamName)
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Join adds the team from an invite code. The invite must be signed by ownerKey,
+// the owner's public key obtained out of band: the key inside the invite proves
+// nothing, anyone can sign their own invite. Returns the acceptance code the owner
+// needs to record this member; until then the team is pending here and has no key.
+func (s *Store) Join(code, ownerKey string) (*Team, string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(code))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid invite code: %w", err)
+	}
+
+	var inv invite
+	if err := json.Unmarshal(data, &inv); err != nil {
+		return nil, "", fmt.Errorf("invalid invite code: %w", err)
+	}
+	ownerKey = strings.TrimSpace(ownerKey)
+	if inv.Team.Owner != ownerKey || inv.Inviter != ownerKey {
+		return nil, "", fmt.Errorf("invite was not issued by the owner key given")
+	}
+	if !verify(ownerKey, inv.signedBytes(), inv.Signature) {
+		return nil, "", fmt.Errorf("invite signature is invalid")
+	}
+	if s.Find(inv.Team.ID) != nil {
+		return nil, "", fmt.Errorf("already a member of %s", inv.Team.Name)
+	}
+
+	team := inv.Team
+	member := team.member(inv.Invitee)
+	if member == nil {
+		team.Members = append(team.Members, Member{Name: inv.Invitee})
+		member = &team.Members[len(team.Members)-1]
+	}
+	member.PublicKey = s.Identity.PublicKey
+	member.JoinedAt = time.Now()
+	member.Invited = false
+	team.Key = ""
+	team.Pending = true
+
+	exchange, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate exchange key: %w", err)
+	}
+	team.ExchangeKey = base64.StdEncoding.EncodeToString(exchange.Bytes())
+
+	acc := acceptance{
+		TeamID:      team.ID,
+		Invitee:     inv.Invitee,
+		PublicKey:   s.Identity.PublicKey,
+		ExchangeKey: base64.StdEncoding.EncodeToString(exchange.PublicKey().Bytes()),
+	}
+	signature, err := s.sign(acc.signedBytes())
+	if err != nil {
+		return nil, "", err
+	}
+	acc.Signature = signature
+	accData, err := json.Marshal(acc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.Teams = append(s.Teams, team)
+
+	system.Info("Joined team", team.Name, "- waiting for the owner to accept")
+	return &s.Teams[len(s.Teams)-1], base64.RawURLEncoding.EncodeToString(accData), nil
+}
+
+// Accept records a joining member from the acceptance code Join printed for them.
+// Only the owner can accept, and only members that were invited. Returns the key
+// code the member needs to finish joining: the team key, encrypted to them.
+func (s *Store) Accept(code string) (*Team, *Member, string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(code))
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("invalid acceptance code: %w", err)
+	}
+
+	var acc acceptance
+	if err := json.Unmarshal(data, &acc); err != nil {
+		return nil, nil, "", fmt.Errorf("invalid acceptance code: %w", err)
+	}
+	team := s.Find(acc.TeamID)
+	if team == nil {
+		return nil, nil, "", fmt.Errorf("acceptance is for a team this machine isn't in")
+	}
+	if team.Owner != s.Identity.PublicKey {
+		return nil, nil, "", fmt.Errorf("only the owner of %s can accept members", team.Name)
+	}
+	if !verify(acc.PublicKey, acc.signedBytes(), acc.Signature) {
+		return nil, nil, "", fmt.Errorf("acceptance signature is invalid")
+	}
+
+	member := team.member(acc.Invitee)
+	if member == nil || !member.Invited {
+		return nil, nil, "", fmt.Errorf("%s has no pending invite to %s", acc.Invitee, team.Name)
+	}
+
+	grant, err := s.grantKey(team, acc.ExchangeKey)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	member.PublicKey = acc.PublicKey
+	member.JoinedAt = time.Now()
+	member.Invited = false
+
+	system.Info("Accepted", member.Name, "into team", team.Name)
+	return team, member, grant, nil
+}
+
+// Activate finishes joining a pending team with the key code the owner sent after
+// accepting this member. The code must be signed by the team's owner.
+func (s *Store) Activate(code string) (*Team, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(code))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key code: %w", err)
+	}
+
+	var grant keyGrant
+	if err := json.Unmarshal(data, &grant); err != nil {
+		return nil, fmt.Errorf("invalid key code: %w", err)
+	}
+	team := s.Find(grant.TeamID)
+	if team == nil || !team.Pending {
+		return nil, fmt.Errorf("key code is for a team this machine isn't waiting to join")
+	}
+	if !verify(team.Owner, grant.signedBytes(), grant.Signature) {
+		return nil, fmt.Errorf("key code signature is invalid")
+	}
+
+	key, err := openKey(team.ExchangeKey, grant)
+	if err != nil {
+		return nil, err
+	}
+
+	team.Key = base64.StdEncoding.EncodeToString(key)
+	team.Pending = false
+	team.ExchangeKey = ""
+
+	system.Info("Received the key for team", team.Name)
+	return team, nil
+}
+
+// Leave forgets a team and its key
+func (s *Store) Leave(teamName string) error {
+	for i := range s.Teams {
+		if s.Teams[i].Name == teamName || s.Teams[i].ID == teamName {
+			system.Info("Left team", s.Teams[i].Name)
+			s.Teams = append(s.Teams[:i], s.Teams[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no team named %q", teamName)
+}
+
+// Find returns a team by name or ID
+func (s *Store) Find(nameOrID string) *Team {
+	for i := range s.Teams {
+		if s.Teams[i].Name == nameOrID || s.Teams[i].ID == nameOrID {
+			return &s.Teams[i]
+		}
+	}
+	return nil
+}
+
+// ActiveMembers returns members who have joined, excluding pending invites
+func (t *Team) ActiveMembers() []Member {
+	var members []Member
+	for _, m := range t.Members {
+		if !m.Invited {
+			members = append(members, m)
+		}
+	}
+	return members
+}
+
+// member finds a member by name or public key
+func (t *Team) member(nameOrKey string) *Member {
+	for i := range t.Members {
+		if t.Members[i].Name == nameOrKey || (t.Members[i].PublicKey != "" && t.Members[i].PublicKey == nameOrKey) {
+			return &t.Members[i]
+		}
+	}
+	return nil
+}
+
+// signedBytes is the part of an invite covered by the signature
+func (inv invite) signedBytes() []byte {
+	data, _ := json.Marshal(struct {
+		Team    Team   `json:"team"`
+		Invitee string `json:"invitee"`
+	}{inv.Team, inv.Invitee})
+	return data
+}
+
+// signedBytes is the part of an acceptance covered by the signature
+func (acc acceptance) signedBytes() []byte {
+	data, _ := json.Marshal(struct {
+		TeamID      string `json:"team_id"`
+		Invitee     string `json:"invitee"`
+		PublicKey   string `json:"public_key"`
+		ExchangeKey string `json:"exchange_key"`
+	}{acc.TeamID, acc.Invitee, acc.PublicKey, acc.ExchangeKey})
+	return data
+}
+
+// signedBytes is the part of a key grant covered by the signature
+func (g keyGrant) signedBytes() []byte {
+	data, _ := json.Marshal(struct {
+		TeamID      string `json:"team_id"`
+		ExchangeKey string `json:"exchange_key"`
+		Sealed      string `json:"sealed"`
+	}{g.TeamID, g.ExchangeKey, g.Sealed})
+	return data
+}
+
+// grantKey seals the team key to a member's exchange key and returns the signed key code
+func (s *Store) grantKey(team *Team, memberExchangeKey string) (string, error) {
+	memberPublic, err := exchangePublicKey(memberExchangeKey)
+	if err != nil {
+		return "", err
+	}
+	key, err := base64.StdEncoding.DecodeString(team.Key)
+	if err != nil {
+		return "", fmt.Errorf("team key is invalid")
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate exchange key: %w", err)
+	}
+	aead, err := exchangeCipher(ephemeral, memberPublic)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	grant := keyGrant{
+		TeamID:      team.ID,
+		ExchangeKey: base64.StdEncoding.EncodeToString(ephemeral.PublicKey().Bytes()),
+		Sealed:      base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, key, []byte(team.ID))),
+	}
+	signature, err := s.sign(grant.signedBytes())
+	if err != nil {
+		return "", err
+	}
+	grant.Signature = signature
+
+	data, err := json.Marshal(grant)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// openKey decrypts the team key in a key grant with the member's exchange key
+func openKey(privateKey string, grant keyGrant) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("exchange key is invalid")
+	}
+	private, err := ecdh.X25519().NewPrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("exchange key is invalid: %w", err)
+	}
+	ownerPublic, err := exchangePublicKey(grant.ExchangeKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := exchangeCipher(private, ownerPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(grant.Sealed)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("key code is invalid")
+	}
+	key, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(grant.TeamID))
+	if err != nil {
+		return nil, fmt.Errorf("key code wasn't sealed for this machine")
+	}
+	return key, nil
+}
+
+// exchangePublicKey parses a base64 X25519 public key
+func exchangePublicKey(encoded string) (*ecdh.PublicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("exchange key is invalid")
+	}
+	public, err := ecdh.X25519().NewPublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("exchange key is invalid: %w", err)
+	}
+	return public, nil
+}
+
+// exchangeCipher derives the AES-GCM cipher two X25519 keys share
+func exchangeCipher(private *ecdh.PrivateKey, public *ecdh.PublicKey) (cipher.AEAD, error) {
+	secret, err := private.ECDH(public)
+	if err != nil {
+		return nil, fmt.Errorf("key exchange failed: %w", err)
+	}
+	sum := sha256.Sum256(secret)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// generateIdentity creates this machine's member key pair
+func (s *Store) generateIdentity() error {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate identity key: %w", err)
+	}
+
+	name := "respawn"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		name += "@" + strings.TrimSuffix(host, ".local")
+	}
+
+	s.Identity = Identity{
+		Name:       name,
+		PublicKey:  base64.StdEncoding.EncodeToString(public),
+		PrivateKey: base64.StdEncoding.EncodeToString(private),
+	}
+	system.Info("Generated team identity for", name)
+	return nil
+}
+
+// sign signs data with this machine's private key
+func (s *Store) sign(data []byte) (string, error) {
+	private, err := base64.StdEncoding.DecodeString(s.Identity.PrivateKey)
+	if err != nil || len(private) != ed25519.PrivateKeySize {
+		return "", fmt.Errorf("identity private key is invalid")
+	}
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(private, data)), nil
+}
+
+// verify checks a signature made by the holder of publicKey
+func verify(publicKey string, data []byte, signature string) bool {
+	public, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(public) != ed25519.PublicKeySize {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(public, data, sig)
+}
+
+// storePath returns the location of the team store in the data directory
+func storePath() string {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return filepath.Join(cfg.DataDir, "teams.json")
+}
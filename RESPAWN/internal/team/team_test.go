@@ -0,0 +1,236 @@
+package team
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+// newTestStore returns a store with a fresh identity that is never saved
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	store := &Store{path: t.TempDir() + "/teams.json"}
+	if err := store.generateIdentity(); err != nil {
+		t.Fatalf("Failed to generate identity: %v", err)
+	}
+	return store
+}
+
+// TestInviteJoinAcceptActivate verifies the team key reaches a member only after acceptance
+func TestInviteJoinAcceptActivate(t *testing.T) {
+	owner := newTestStore(t)
+	member := newTestStore(t)
+
+	created, err := owner.Create("core")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	teamKey := created.Key
+
+	invite, err := owner.Invite("core", "sam")
+	if err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+
+	joined, acceptCode, err := member.Join(invite, owner.Identity.PublicKey)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	if joined.Key != "" {
+		t.Error("Expected no team key before the owner accepts")
+	}
+	if !joined.Pending {
+		t.Error("Expected the team to be pending after joining")
+	}
+
+	_, accepted, keyCode, err := owner.Accept(acceptCode)
+	if err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	if accepted.PublicKey != member.Identity.PublicKey || accepted.Invited {
+		t.Errorf("Expected sam recorded with the member's key, got %+v", accepted)
+	}
+
+	activated, err := member.Activate(keyCode)
+	if err != nil {
+		t.Fatalf("Activate failed: %v", err)
+	}
+	if activated.Key != teamKey {
+		t.Error("Expected the owner's team key after activating")
+	}
+	if activated.Pending || activated.ExchangeKey != "" {
+		t.Error("Expected the team active and the exchange key dropped")
+	}
+}
+
+// TestInviteHasNoKey verifies an intercepted invite doesn't reveal the team key
+func TestInviteHasNoKey(t *testing.T) {
+	owner := newTestStore(t)
+	if _, err := owner.Create("core"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	code, err := owner.Invite("core", "sam")
+	if err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+
+	inv := decodeInvite(t, code)
+	if inv.Team.Key != "" {
+		t.Error("Expected the invite not to carry the team key")
+	}
+	if owner.Find("core").Key == "" {
+		t.Error("Expected the owner to keep the team key")
+	}
+}
+
+// TestJoinRejectsForgedInvite verifies invites are checked against the owner key given
+func TestJoinRejectsForgedInvite(t *testing.T) {
+	owner := newTestStore(t)
+	forger := newTestStore(t)
+	member := newTestStore(t)
+
+	if _, err := forger.Create("core"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	forged, err := forger.Invite("core", "sam")
+	if err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+	if _, _, err := member.Join(forged, owner.Identity.PublicKey); err == nil {
+		t.Error("Expected an invite signed by another key to be refused")
+	}
+
+	// Claiming the real owner without their signature
+	inv := decodeInvite(t, forged)
+	inv.Team.Owner = owner.Identity.PublicKey
+	inv.Inviter = owner.Identity.PublicKey
+	if _, _, err := member.Join(encode(t, inv), owner.Identity.PublicKey); err == nil {
+		t.Error("Expected an invite with a bad signature to be refused")
+	}
+}
+
+// TestAcceptChecks verifies only the owner accepts, and only signed codes for invited members
+func TestAcceptChecks(t *testing.T) {
+	owner := newTestStore(t)
+	member := newTestStore(t)
+
+	if _, err := owner.Create("core"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	invite, err := owner.Invite("core", "sam")
+	if err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+	_, acceptCode, err := member.Join(invite, owner.Identity.PublicKey)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+
+	// The joining member isn't the owner
+	if _, _, _, err := member.Accept(acceptCode); err == nil {
+		t.Error("Expected a non-owner to be refused")
+	}
+
+	// Tampered key in the acceptance
+	var acc acceptance
+	decode(t, acceptCode, &acc)
+	acc.PublicKey = newTestStore(t).Identity.PublicKey
+	if _, _, _, err := owner.Accept(encode(t, acc)); err == nil {
+		t.Error("Expected a tampered acceptance to be refused")
+	}
+
+	if _, _, _, err := owner.Accept(acceptCode); err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	if _, _, _, err := owner.Accept(acceptCode); err == nil {
+		t.Error("Expected a second acceptance for the same invite to be refused")
+	}
+}
+
+// TestActivateChecks verifies key codes must be signed by the owner and sealed for this machine
+func TestActivateChecks(t *testing.T) {
+	owner := newTestStore(t)
+	member := newTestStore(t)
+	other := newTestStore(t)
+
+	if _, err := owner.Create("core"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	samInvite, err := owner.Invite("core", "sam")
+	if err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+	alexInvite, err := owner.Invite("core", "alex")
+	if err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+
+	_, samCode, err := member.Join(samInvite, owner.Identity.PublicKey)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	_, alexCode, err := other.Join(alexInvite, owner.Identity.PublicKey)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	_, _, samKey, err := owner.Accept(samCode)
+	if err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	if _, _, _, err := owner.Accept(alexCode); err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+
+	// Another pending member can't open sam's key code
+	if _, err := other.Activate(samKey); err == nil {
+		t.Error("Expected a key code sealed for another member to be refused")
+	}
+
+	// A key code not signed by the owner
+	var grant keyGrant
+	decode(t, samKey, &grant)
+	grant.Signature, err = other.sign(grant.signedBytes())
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if _, err := member.Activate(encode(t, grant)); err == nil {
+		t.Error("Expected a key code signed by someone else to be refused")
+	}
+
+	if _, err := member.Activate(samKey); err != nil {
+		t.Fatalf("Activate failed: %v", err)
+	}
+	if _, err := member.Activate(samKey); err == nil {
+		t.Error("Expected activating a team that isn't pending to be refused")
+	}
+}
+
+// decodeInvite parses an invite code
+func decodeInvite(t *testing.T, code string) invite {
+	t.Helper()
+	var inv invite
+	decode(t, code, &inv)
+	return inv
+}
+
+// decode parses any of the team codes into v
+func decode(t *testing.T, code string, v any) {
+	t.Helper()
+	data, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil {
+		t.Fatalf("Failed to decode code: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("Failed to parse code: %v", err)
+	}
+}
+
+// encode builds a team code from v
+func encode(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Failed to encode code: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -37,9 +37,24 @@ type ProcessInfo struct {
 	PID         int    `json:"pid"`
 	Name        string `json:"name"`
 	ProcessName string `json:"process_name"`
+	BundleID    string `json:"bundle_id,omitempty"`
+	BundlePath  string `json:"bundle_path,omitempty"` // Resolved .app location, may be outside /Applications
+	BundleVersion string `json:"bundle_version,omitempty"` // CFBundleShortVersionString at checkpoint time
 	MemoryMB    int64  `json:"memory_mb"`
 	WindowState string `json:"window_state"` // "normal", "minimized", "maximized"
 	IsRunning   bool   `json:"is_running"`
+	ListeningPorts []int `json:"listening_ports,omitempty"` // TCP ports the process was listening on
+	Profiles    []string `json:"profiles,omitempty"` // Browser profiles with open windows
+	Chat        *ChatContext `json:"chat,omitempty"`  // Focused workspace/channel of chat apps
+	LoginItem   bool     `json:"login_item,omitempty"` // macOS opens it at login by itself
+}
+
+// ChatContext records where a chat app (Slack, Discord, Teams) was focused
+type ChatContext struct {
+	Workspace   string `json:"workspace,omitempty"`
+	Channel     string `json:"channel,omitempty"`
+	WindowTitle string `json:"window_title,omitempty"`
+	DeepLink    string `json:"deep_link,omitempty"` // e.g. slack://open?team=T0123
 }
 
 // New embedding: Extend ProcessInfo with WindowInfo slice
@@ -56,19 +71,136 @@ type LaunchResult struct {
 	LaunchTime time.Time `json:"launch_time"`
 	RetryCount int       `json:"retry_count"`
 	ErrorMsg   string    `json:"error_msg,omitempty"`
+	ListeningPorts []int `json:"listening_ports,omitempty"` // Ports recorded at checkpoint time
+	NotInstalled bool    `json:"not_installed,omitempty"`  // Skipped because the app is no longer installed
+	InstallHint  string  `json:"install_hint,omitempty"`   // Where to get the app again, when known
+	AlreadyRunning bool  `json:"already_running,omitempty"` // Skipped because the app was already running
+	LoginItem    bool    `json:"login_item,omitempty"`     // Already running because macOS opened it as a login item
+	ReopenedByMacOS bool `json:"reopened_by_macos,omitempty"` // Already running because macOS reopened it after the reboot
+	ManualOnly   bool    `json:"manual_only,omitempty"`    // Skipped because repeated failures made it manual restore only
+	MarkedManualOnly bool `json:"marked_manual_only,omitempty"` // This failure made the app manual restore only
+	TimedOut     bool    `json:"timed_out,omitempty"`      // The launch hung past its deadline
+	Blacklisted  bool    `json:"blacklisted,omitempty"`    // Skipped because it is on the temporary blacklist
+	MarkedBlacklisted bool `json:"marked_blacklisted,omitempty"` // This timeout put the app on the blacklist
+	Duration     time.Duration `json:"duration"`          // Time spent launching, including retries
+	Err          error   `json:"-"`                        // Why the launch failed, for errors.Is checks
+}
+
+// MountedVolume represents a mounted network share
+type MountedVolume struct {
+	Source     string `json:"source"`      // e.g. "//user@server/share"
+	MountPoint string `json:"mount_point"` // e.g. "/Volumes/share"
+	FSType     string `json:"fs_type"`     // "smbfs", "afpfs", "nfs", "webdav"
+}
+
+// SessionSnapshot records browser session files copied into a checkpoint
+type SessionSnapshot struct {
+	AppName   string   `json:"app_name"`
+	Profile   string   `json:"profile"`
+	SourceDir string   `json:"source_dir"` // Where the browser keeps the files
+	StoredDir string   `json:"stored_dir"` // Where the checkpoint copy lives
+	Files     []string `json:"files"`      // Relative to both directories
+}
+
+// StateFileSnapshot records an app's own window-state file copied into a checkpoint
+type StateFileSnapshot struct {
+	AppName    string    `json:"app_name"`
+	SourcePath string    `json:"source_path"`
+	StoredPath string    `json:"stored_path"`
+	ModTime    time.Time `json:"mod_time"` // Of the source when copied; a file changed since is left alone on restore
+}
+
+// AudioState represents the sound output settings
+type AudioState struct {
+	OutputDevice string `json:"output_device,omitempty"`
+	Volume       int    `json:"volume"` // 0-100
+	Muted        bool   `json:"muted"`
+}
+
+// PlaybackState represents what a music player was playing
+type PlaybackState struct {
+	Player   string  `json:"player"`             // "Music" or "Spotify"
+	Track    string  `json:"track"`
+	Artist   string  `json:"artist"`
+	TrackID  string  `json:"track_id,omitempty"` // Spotify URI
+	Position float64 `json:"position"`           // Seconds into the track
+	Playing  bool    `json:"playing"`
+}
+
+// DeveloperState records developer tooling that was open at checkpoint time
+type DeveloperState struct {
+	XcodeDocuments []string          `json:"xcode_documents,omitempty"` // .xcodeproj / .xcworkspace paths
+	Simulators     []SimulatorDevice `json:"simulators,omitempty"`      // Booted simulators
+	ComposeProjects []ComposeProject `json:"compose_projects,omitempty"` // Running docker compose projects
+	Containers     []string          `json:"containers,omitempty"`      // Running containers outside compose
+}
+
+// ComposeProject identifies a docker compose project from its container labels
+type ComposeProject struct {
+	Name        string   `json:"name"`
+	WorkingDir  string   `json:"working_dir"`
+	ConfigFiles []string `json:"config_files,omitempty"`
+}
+
+// SimulatorDevice identifies a booted simulator
+type SimulatorDevice struct {
+	UDID    string `json:"udid"`
+	Name    string `json:"name"`
+	Runtime string `json:"runtime"`
+}
+
+// VirtualMachine identifies a running VM and the app that hosts it
+type VirtualMachine struct {
+	Provider string `json:"provider"` // "UTM", "Parallels" or "VMware Fusion"
+	ID       string `json:"id"`       // UUID, or .vmx path for Fusion
+	Name     string `json:"name"`
+}
+
+// DisplayInfo represents a connected display and its place in the arrangement
+type DisplayInfo struct {
+	Name     string   `json:"name"`
+	Position Position `json:"position"` // Origin in global screen coordinates
+	Size     Size     `json:"size"`     // Resolution in points
+	IsMain   bool     `json:"is_main,omitempty"`
 }
 
 // Checkpoint represents a system checkpoint
 type Checkpoint struct {
+	FormatVersion int         `json:"format_version"`
 	ID          string        `json:"id"`
 	Timestamp   time.Time     `json:"timestamp"`
 	Processes   []ProcessInfo `json:"processes"`
 	AppNames    []string      `json:"app_names"`
+	Volumes     []MountedVolume `json:"volumes,omitempty"`
+	Audio       *AudioState   `json:"audio,omitempty"`
+	Playback    []PlaybackState `json:"playback,omitempty"`
+	Developer   *DeveloperState `json:"developer,omitempty"`
+	VirtualMachines []VirtualMachine `json:"virtual_machines,omitempty"`
+	Sessions    []SessionSnapshot `json:"sessions,omitempty"`
+	WindowStateFiles []StateFileSnapshot `json:"window_state_files,omitempty"`
+	Displays    []DisplayInfo `json:"displays,omitempty"`
+	Providers   []CaptureProvider `json:"providers,omitempty"` // Which capture providers contributed, and why others did not
+	CaptureMode string        `json:"capture_mode,omitempty"` // "full" or "basic" (no window state)
+	Partial     bool          `json:"partial,omitempty"`      // Only some apps were captured, on request
+	Trigger     string        `json:"trigger,omitempty"`      // What caused it, e.g. "scheduled" or "manual"; decides retention
+	Note        string        `json:"note,omitempty"`         // Free-text annotation, e.g. "state before demo"
+	Hostname    string        `json:"hostname,omitempty"`     // Mac it was taken on, for display
+	MachineID   string        `json:"machine_id,omitempty"`   // Hardware UUID of that Mac; empty before machines were recorded
 	IsCompressed bool         `json:"is_compressed"`
 	FilePath    string        `json:"file_path"`
 	FileSize    int64         `json:"file_size"`
 }
 
+// CaptureProvider records whether one source of checkpoint data ran, e.g. the
+// Accessibility window scan or browser session files
+type CaptureProvider struct {
+	Name       string   `json:"name"`
+	Categories []string `json:"categories"`       // Data it provides: "windows", "tabs", "docs", "sessions"...
+	Captured   bool     `json:"captured"`
+	Items      int      `json:"items,omitempty"`  // How many things it recorded
+	Reason     string   `json:"reason,omitempty"` // Why it was skipped, e.g. "window_privacy is titles"
+}
+
 // CheckpointList contains a list of checkpoints with metadata
 type CheckpointList struct {
 	Checkpoints     []Checkpoint `json:"checkpoints"`
@@ -84,6 +216,7 @@ type CheckpointStatus struct {
 	Timestamp    time.Time `json:"timestamp"`
 	ErrorMessage string `json:"error_message,omitempty"`
 	AppsCount    int    `json:"apps_count"`
+	Digest       string `json:"digest,omitempty"` // One-line summary, e.g. "8 apps · Google Chrome · 3 MB"
 }
 
 // RestartPolicy defines restart behavior after crashes
@@ -96,20 +229,57 @@ type RestartPolicy struct {
 
 // RestoreSummary contains restoration completion details
 type RestoreSummary struct {
-	TotalApps      int
-	SuccessfulApps int
-	FailedApps     int
-	SkippedApps    int
-	TotalDuration  time.Duration
-	FailedAppNames []string
-	StartTime      time.Time
-	EndTime        time.Time
+	TotalApps      int           `json:"total_apps"`
+	SuccessfulApps int           `json:"successful_apps"`
+	FailedApps     int           `json:"failed_apps"`
+	SkippedApps    int           `json:"skipped_apps"` // Already running or reopened by macOS, not installed, manual restore only and blacklisted
+	TotalDuration  time.Duration `json:"total_duration"`
+	FailedAppNames []string      `json:"failed_app_names,omitempty"`
+	AlreadyRunningApps []string  `json:"already_running_apps,omitempty"` // Apps skipped because they were already running
+	ReopenedByMacOSApps []string `json:"reopened_by_macos_apps,omitempty"` // Apps macOS had already reopened after the reboot
+	NotInstalledApps []string          `json:"not_installed_apps,omitempty"` // Apps skipped because they are no longer installed
+	ManualOnlyApps []string            `json:"manual_only_apps,omitempty"` // Apps skipped because they are manual restore only
+	NewlyManualOnlyApps []string       `json:"newly_manual_only_apps,omitempty"` // Apps this restore made manual restore only
+	BlacklistedApps []string           `json:"blacklisted_apps,omitempty"` // Apps skipped because they are blacklisted
+	NewlyBlacklistedApps []string      `json:"newly_blacklisted_apps,omitempty"` // Apps this restore blacklisted for timing out
+	InstallHints   map[string]string `json:"install_hints,omitempty"` // App Name -> App Store / brew cask suggestion
+	ListeningPorts map[string][]int `json:"listening_ports,omitempty"` // App Name -> ports that were listening at checkpoint time
+	LaunchDurations map[string]time.Duration `json:"launch_durations,omitempty"` // App Name -> time spent launching it
+	StartTime      time.Time     `json:"start_time"`
+	EndTime        time.Time     `json:"end_time"`
 }
 
-// StatusSummary contains RESPAWN status information
+// StatusSummary contains RESPAWN status information, printed by `respawn status --json`
 type StatusSummary struct {
-	LastCheckpoint time.Time `json:"last_checkpoint"`
-	TotalCheckpoints int    `json:"total_checkpoints"`
-	AutoStartEnabled bool   `json:"auto_start_enabled"`
-	HealthStatus   string   `json:"health_status"`
+	Version          string     `json:"version"`
+	Running          bool       `json:"running"`
+	PID              int        `json:"pid,omitempty"`
+	Paused           bool       `json:"paused"`
+	PausedUntil      *time.Time `json:"paused_until,omitempty"` // Unset for a pause without an end
+	AutoStartEnabled bool       `json:"auto_start_enabled"`
+	Profile          string     `json:"profile"`
+	LastCheckpoint   time.Time  `json:"last_checkpoint"`
+	LastCheckpointID string     `json:"last_checkpoint_id,omitempty"`
+	NextCheckpoint   *time.Time `json:"next_checkpoint,omitempty"` // Only while the daemon runs and isn't paused
+	CheckpointGate   string     `json:"checkpoint_gate,omitempty"` // Why the daemon is holding checkpoints back
+	TotalCheckpoints int        `json:"total_checkpoints"`
+	Crashes          CrashSummary   `json:"crashes"`
+	Storage          StorageSummary `json:"storage"`
+	Permissions      map[string]bool `json:"permissions,omitempty"` // From the last pre-flight check
+	PermissionsCheckedAt *time.Time `json:"permissions_checked_at,omitempty"`
+	HealthStatus     string     `json:"health_status"` // "healthy", "degraded", "paused" or "stopped"
+}
+
+// CrashSummary is the daemon's recent crash record
+type CrashSummary struct {
+	Recent            int           `json:"recent"`
+	Window            time.Duration `json:"window"`
+	AutoStartDisabled bool          `json:"auto_start_disabled"` // Too many crashes turned auto-start off
+}
+
+// StorageSummary is the space checkpoints take and what is left on their volume
+type StorageSummary struct {
+	CheckpointBytes int64   `json:"checkpoint_bytes"`
+	DiskFreeBytes   uint64  `json:"disk_free_bytes"`
+	DiskUsedPercent float64 `json:"disk_used_percent"`
 }
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"RESPAWN/internal/system"
+)
+
+// Dialog describes a dialog for a Backend to show
+type Dialog struct {
+	Title         string
+	Message       string
+	Buttons       []string
+	DefaultButton string        // Must be one of Buttons
+	Icon          string        // "caution" or "stop"
+	GiveUpAfter   time.Duration // Close unanswered after this long; 0 waits
+}
+
+// Backend delivers notifications and dialogs for the NotificationManager. The
+// default drives osascript; terminal-notifier, a webhook or a test fake can be
+// swapped in with SetBackend.
+type Backend interface {
+	// Banner shows a passing notification
+	Banner(message string, notifType NotificationType) error
+	// Dialog waits for a button and returns it, or "" when the dialog gave up.
+	// Cancelling or failing to show the dialog is an error.
+	Dialog(d Dialog) (string, error)
+	// Ask is a Dialog with a text field, returning the text entered
+	Ask(d Dialog, defaultAnswer string) (string, error)
+	// DoNotDisturb reports whether macOS Do Not Disturb is on
+	DoNotDisturb() (bool, error)
+	// Open opens a URL, e.g. a respawn:// link
+	Open(url string) error
+}
+
+// osascriptBackend shows notifications and dialogs through AppleScript
+type osascriptBackend struct{}
+
+// Banner shows a macOS notification
+func (osascriptBackend) Banner(message string, notifType NotificationType) error {
+	script := fmt.Sprintf(`
+        display notification %s with title "RESPAWN" sound name "Glass"
+    `, system.AppleScriptString(message))
+
+	output, err := exec.Command("osascript", "-e", script).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to show notification: %w (output: %s)", err, string(output))
+	}
+	return nil
+}
+
+// Dialog shows a `display dialog` and returns the button pressed
+func (osascriptBackend) Dialog(d Dialog) (string, error) {
+	output, err := exec.Command("osascript", "-e", "display dialog "+dialogClauses(d)).Output()
+	if err != nil {
+		return "", err
+	}
+	return parseButtonReturned(string(output)), nil
+}
+
+// Ask shows a `display dialog` with a default answer and returns the text entered
+func (osascriptBackend) Ask(d Dialog, defaultAnswer string) (string, error) {
+	script := fmt.Sprintf(`
+        set response to text returned of (display dialog %s default answer %s)
+        return response
+    `, dialogClauses(d), system.AppleScriptString(defaultAnswer))
+
+	output, err := exec.Command("osascript", "-e", script).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DoNotDisturb reads the Focus preferences
+func (osascriptBackend) DoNotDisturb() (bool, error) {
+	output, err := exec.Command("defaults", "read", "com.apple.ncprefs", "dnd_prefs").Output()
+	if err != nil {
+		return false, err
+	}
+	return dndEnabled(string(output)), nil
+}
+
+// Open hands a URL to LaunchServices
+func (osascriptBackend) Open(url string) error {
+	return exec.Command("open", url).Run()
+}
+
+// dialogClauses renders everything after `display dialog` for d
+func dialogClauses(d Dialog) string {
+	buttons := make([]string, len(d.Buttons))
+	for i, button := range d.Buttons {
+		buttons[i] = system.AppleScriptString(button)
+	}
+
+	clauses := fmt.Sprintf("%s with title %s buttons {%s}",
+		system.AppleScriptString(d.Message), system.AppleScriptString(d.Title), strings.Join(buttons, ", "))
+	if d.DefaultButton != "" {
+		clauses += " default button " + system.AppleScriptString(d.DefaultButton)
+	}
+	if d.Icon != "" {
+		clauses += " with icon " + d.Icon
+	}
+	if d.GiveUpAfter > 0 {
+		clauses += fmt.Sprintf(" giving up after %d", int(d.GiveUpAfter.Seconds()))
+	}
+	return clauses
+}
+
+// parseButtonReturned picks the button out of `display dialog` output such as
+// "button returned:Prune, gave up:false". A dialog that gave up returns "".
+func parseButtonReturned(output string) string {
+	_, rest, ok := strings.Cut(output, "button returned:")
+	if !ok {
+		return ""
+	}
+	button, _, _ := strings.Cut(rest, ", gave up:")
+	return strings.TrimSpace(button)
+}
+
+// dndEnabled reports whether `defaults read com.apple.ncprefs dnd_prefs` output
+// shows Do Not Disturb switched on by the user
+func dndEnabled(prefs string) bool {
+	return strings.Contains(prefs, "userPref") && strings.Contains(prefs, "enabled = 1")
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"RESPAWN/internal/i18n"
+	"RESPAWN/internal/system"
+	"RESPAWN/pkg/config"
+)
+
+// digestFileName holds notifications held back by quiet hours. It lives on disk so
+// notifications from CLI commands end up in the daemon's digest too.
+const digestFileName = "notification_digest.json"
+
+// digestMaxLines is how many held-back notifications the digest lists in full
+const digestMaxLines = 5
+
+// digestMu serializes read-modify-write of the digest file within a process
+var digestMu sync.Mutex
+
+// digestEntry is one notification held back by quiet hours
+type digestEntry struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// digestPath is where held-back notifications live in the data directory
+func digestPath() string {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return filepath.Join(cfg.DataDir, digestFileName)
+}
+
+// queueDigest adds a notification to the digest shown when quiet hours end
+func queueDigest(message string) error {
+	digestMu.Lock()
+	defer digestMu.Unlock()
+
+	entries := loadDigest()
+	entries = append(entries, digestEntry{Time: time.Now(), Message: message})
+	return saveDigest(entries)
+}
+
+// FlushDigest shows the notifications held back during quiet hours as one summary
+// and clears them. It does nothing while quiet hours last or nothing was held back.
+func (nm *NotificationManager) FlushDigest() error {
+	if nm.quietHours.Contains(time.Now()) {
+		return nil
+	}
+
+	digestMu.Lock()
+	entries := loadDigest()
+	if len(entries) > 0 {
+		if err := saveDigest(nil); err != nil {
+			system.Warn("Failed to clear notification digest:", err)
+		}
+	}
+	digestMu.Unlock()
+
+	if len(entries) == 0 {
+		return nil
+	}
+	system.Info("Showing digest of", len(entries), "notifications held back by quiet hours")
+
+	lines := []string{i18n.T("notify.digest")}
+	for i, entry := range entries {
+		if i == digestMaxLines {
+			lines = append(lines, i18n.T("notify.digest_more", len(entries)-digestMaxLines))
+			break
+		}
+		// First line only, the digest is a reminder rather than a replay
+		first, _, _ := strings.Cut(entry.Message, "\n")
+		lines = append(lines, fmt.Sprintf("%s %s", entry.Time.Format("15:04"), first))
+	}
+	return nm.showBannerNotification(strings.Join(lines, "\n"), NotificationInfo, 10*time.Second)
+}
+
+// loadDigest reads the held-back notifications, empty if there are none
+func loadDigest() []digestEntry {
+	data, err := os.ReadFile(digestPath())
+	if err != nil {
+		return nil
+	}
+	var entries []digestEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		system.Warn("Ignoring unreadable notification digest:", err)
+		return nil
+	}
+	return entries
+}
+
+// saveDigest writes the held-back notifications, removing the file when there are none
+func saveDigest(entries []digestEntry) error {
+	path := digestPath()
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
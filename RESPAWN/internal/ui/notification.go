@@ -2,19 +2,28 @@ package ui
 
 import (
 	"fmt"
-	"os/exec"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+	"RESPAWN/internal/i18n"
 	"RESPAWN/internal/types"
 	"RESPAWN/internal/system"
+	"RESPAWN/pkg/config"
 )
 
 // NotificationManager handles user notifications
 type NotificationManager struct {
+	// mu guards level, quietHours and lastNotification; a config reload changes
+	// them while notifications go out from other goroutines
+	mu               sync.Mutex
 	position         NotificationPosition
 	respectDND       bool
 	lastNotification time.Time
 	isInteractive    bool
+	level            string
+	quietHours       config.ClockWindow
+	backend          Backend
 }
 
 // NotificationPosition defines where notifications appear
@@ -36,15 +45,47 @@ const (
 	NotificationError
 )
 
+// PromptChoice is the answer to a dialog that can be snoozed
+type PromptChoice int
+
+const (
+	ChoiceDismiss PromptChoice = iota // Not now, or the dialog timed out
+	ChoiceAccept
+	ChoiceSnooze // Remind me later
+)
+
 // NewNotificationManager creates a new notification manager
 func NewNotificationManager() *NotificationManager {
+	level := "all"
+	var quietHours config.ClockWindow
+	if cfg := config.Current(); cfg != nil {
+		level = cfg.NotificationLevel
+		quietHours = cfg.QuietHours
+	}
+
 	return &NotificationManager{
 		position:      PositionBottomRight,
 		respectDND:    true,
 		isInteractive: true,
+		level:         level,
+		quietHours:    quietHours,
+		backend:       osascriptBackend{},
 	}
 }
 
+// ApplyConfig picks up the notification level and quiet hours from a reloaded config
+func (nm *NotificationManager) ApplyConfig() {
+	cfg := config.Current()
+	if cfg == nil {
+		return
+	}
+
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	nm.level = cfg.NotificationLevel
+	nm.quietHours = cfg.QuietHours
+}
+
 // ShowRestoreStart shows restoration started notification (silent in Modified Option C)
 func (nm *NotificationManager) ShowRestoreStart() error {
 	system.Info("Restoration started - silent notification")
@@ -66,7 +107,7 @@ func (nm *NotificationManager) ShowAppRestored(appName string, timestamp time.Ti
 	}
 
 	// Show minimalist notification: "App ✅"
-	message := fmt.Sprintf("%s ✅", appName)
+	message := i18n.T("notify.app_restored", appName)
 
 	if err := nm.showBannerNotification(message, NotificationSuccess, 2*time.Second); err != nil {
 		system.Warn("Failed to show app restored notification:", err)
@@ -88,21 +129,48 @@ func (nm *NotificationManager) ShowRestoreComplete(summary types.RestoreSummary)
 
 	if summary.FailedApps == 0 {
 		// All successful
-		message = fmt.Sprintf(
-			"✅ Restored %d applications in %s",
+		message = i18n.T("notify.restore_complete",
 			summary.SuccessfulApps,
 			nm.formatDuration(summary.TotalDuration),
 		)
 	} else {
 		// Some failures
-		message = fmt.Sprintf(
-			"⚠️ Restored %d/%d applications\n%d failed\n\nCheck: respawn --status",
+		message = i18n.T("notify.restore_partial",
 			summary.SuccessfulApps,
 			summary.TotalApps,
 			summary.FailedApps,
 		)
 	}
 
+	if len(summary.NotInstalledApps) > 0 {
+		message += "\n" + i18n.T("notify.not_installed", strings.Join(summary.NotInstalledApps, ", "))
+	}
+	if len(summary.AlreadyRunningApps) > 0 {
+		message += "\n" + i18n.T("notify.already_running", strings.Join(summary.AlreadyRunningApps, ", "))
+	}
+	if len(summary.ReopenedByMacOSApps) > 0 {
+		message += "\n" + i18n.T("notify.reopened", strings.Join(summary.ReopenedByMacOSApps, ", "))
+	}
+	if len(summary.ManualOnlyApps) > 0 {
+		message += "\n" + i18n.T("notify.manual_only", strings.Join(summary.ManualOnlyApps, ", "))
+	}
+	if len(summary.NewlyManualOnlyApps) > 0 {
+		message += "\n" + i18n.T("notify.manual_only_marked", strings.Join(summary.NewlyManualOnlyApps, ", "))
+	}
+	if len(summary.NewlyBlacklistedApps) > 0 {
+		message += "\n" + i18n.T("notify.blacklisted", strings.Join(summary.NewlyBlacklistedApps, ", "))
+	}
+
+	// Remind about dev servers that were listening at checkpoint time
+	if len(summary.ListeningPorts) > 0 {
+		var apps []string
+		for appName := range summary.ListeningPorts {
+			apps = append(apps, appName)
+		}
+		sort.Strings(apps)
+		message += "\n" + i18n.T("notify.restart_dev_servers", strings.Join(apps, ", "))
+	}
+
 	notificationType := NotificationSuccess
 	if summary.FailedApps > 0 {
 		notificationType = NotificationWarning
@@ -124,8 +192,7 @@ func (nm *NotificationManager) ShowCheckpointFailed(status types.CheckpointStatu
 	// Always show checkpoint failures (Modified Option C requirement)
 	// Even if DND is active
 
-	message := fmt.Sprintf(
-		"❌ Checkpoint Failed\n\n%s\n\nTime: %s",
+	message := i18n.T("notify.checkpoint_failed",
 		status.ErrorMessage,
 		status.Timestamp.Format("15:04:05"),
 	)
@@ -138,17 +205,31 @@ func (nm *NotificationManager) ShowCheckpointFailed(status types.CheckpointStatu
 	return nil
 }
 
-// ShowCheckpointSuccess shows checkpoint creation confirmation (silent per Modified Option C)
+// ShowCheckpointSuccess confirms a checkpoint the user asked for, with its digest.
+// Periodic checkpoints don't call this and stay silent per Modified Option C.
 func (nm *NotificationManager) ShowCheckpointSuccess(status types.CheckpointStatus) error {
 	system.Debug("Checkpoint created successfully:", status.CheckpointID)
 
-	// Silent per Modified Option C
-	// User can check status via: respawn --status
+	if nm.respectDND && nm.isDoNotDisturbActive() {
+		system.Debug("Do Not Disturb active - notification suppressed")
+		return nil
+	}
+
+	message := i18n.T("notify.checkpoint_saved")
+	if status.Digest != "" {
+		message += "\n" + status.Digest
+	}
+
+	if err := nm.showBannerNotification(message, NotificationSuccess, 3*time.Second); err != nil {
+		system.Warn("Failed to show checkpoint notification:", err)
+		return err
+	}
 
 	return nil
 }
 
-// ShowError shows error notification
+// ShowError shows error notification. Anything that isn't a failure belongs in
+// ShowWarning, ShowStatus or ShowInfo, so it isn't logged and styled as an error.
 func (nm *NotificationManager) ShowError(title, message string) error {
 	system.Error(title, ":", message)
 
@@ -163,6 +244,109 @@ func (nm *NotificationManager) ShowError(title, message string) error {
 	return nil
 }
 
+// ShowWarning shows something that went partly wrong, such as apps skipped by a restore.
+// Like errors it bypasses DND, but it is logged and styled as a warning.
+func (nm *NotificationManager) ShowWarning(title, message string) error {
+	system.Warn(title, ":", message)
+
+	fullMessage := fmt.Sprintf("%s\n\n%s", title, message)
+
+	if err := nm.showBannerNotification(fullMessage, NotificationWarning, 10*time.Second); err != nil {
+		system.Warn("Failed to show warning notification:", err)
+		return err
+	}
+
+	return nil
+}
+
+// ShowStatus announces a change in what RESPAWN itself is doing: started, paused or
+// resumed. It bypasses DND so monitoring never stops or starts unnoticed.
+func (nm *NotificationManager) ShowStatus(title, message string) error {
+	system.Info(title, ":", message)
+
+	fullMessage := fmt.Sprintf("%s\n\n%s", title, message)
+
+	if err := nm.showBannerNotification(fullMessage, NotificationInfo, 5*time.Second); err != nil {
+		system.Warn("Failed to show status notification:", err)
+		return err
+	}
+
+	return nil
+}
+
+// ShowInfo shows routine information, such as how long the Mac slept. It respects DND.
+func (nm *NotificationManager) ShowInfo(title, message string) error {
+	system.Info(title, ":", message)
+
+	if nm.respectDND && nm.isDoNotDisturbActive() {
+		system.Debug("Do Not Disturb active - notification suppressed")
+		return nil
+	}
+
+	fullMessage := fmt.Sprintf("%s\n\n%s", title, message)
+
+	if err := nm.showBannerNotification(fullMessage, NotificationInfo, 5*time.Second); err != nil {
+		system.Warn("Failed to show info notification:", err)
+		return err
+	}
+
+	return nil
+}
+
+// ShowDiskSpaceAlert warns that the disk is filling up and suggests pruning. When
+// critical, a dialog offers to prune right away, returning ChoiceAccept for the
+// caller to prune, or to be reminded later. The dialog blocks until answered.
+func (nm *NotificationManager) ShowDiskSpaceAlert(usedPercent float64, critical bool, pruneCount int, pruneSize string, pruneArg string) (PromptChoice, error) {
+	system.Warn(fmt.Sprintf("Disk %.0f%% full - suggesting prune of %d checkpoints (%s)", usedPercent, pruneCount, pruneSize))
+
+	suggestion := i18n.T("notify.disk_elsewhere")
+	if pruneCount > 0 {
+		suggestion = i18n.T("notify.disk_prune", pruneCount, pruneSize)
+	}
+	message := i18n.T("notify.disk_full", usedPercent, suggestion)
+
+	if !critical || pruneCount == 0 {
+		if pruneCount > 0 {
+			message += "\n" + i18n.T("notify.disk_run_prune", pruneArg)
+		}
+		return ChoiceDismiss, nm.showBannerNotification(message, NotificationWarning, 10*time.Second)
+	}
+
+	return nm.showSnoozeDialog(message, i18n.T("dialog.disk_title"), i18n.T("dialog.prune")), nil
+}
+
+// ShowRestorePrompt asks whether to restore the workspace after a restart, showing
+// the checkpoint digest so the user knows what comes back
+func (nm *NotificationManager) ShowRestorePrompt(checkpointName, digest string) PromptChoice {
+	system.Info("Asking whether to restore", checkpointName)
+	return nm.showSnoozeDialog(i18n.T("dialog.restore_prompt", checkpointName, digest),
+		i18n.T("dialog.restore_title"), i18n.T("dialog.restore"))
+}
+
+// showSnoozeDialog shows a dialog with Not Now, Remind Me Later and an accept button.
+// A dialog left unanswered gives up after two minutes and counts as Not Now.
+func (nm *NotificationManager) showSnoozeDialog(message, title, accept string) PromptChoice {
+	button, err := nm.backend.Dialog(Dialog{
+		Title:         title,
+		Message:       message,
+		Buttons:       []string{i18n.T("dialog.not_now"), i18n.T("dialog.later"), accept},
+		DefaultButton: accept,
+		Icon:          "caution",
+		GiveUpAfter:   2 * time.Minute,
+	})
+	if err != nil {
+		return ChoiceDismiss
+	}
+	switch button {
+	case accept:
+		return ChoiceAccept
+	case i18n.T("dialog.later"):
+		return ChoiceSnooze
+	default:
+		return ChoiceDismiss
+	}
+}
+
 // ShowTeamCheckpointShared shows team checkpoint sharing notification
 func (nm *NotificationManager) ShowTeamCheckpointShared(teamSize int, checkpointID string) error {
 	system.Info("Team checkpoint shared with", teamSize, "members")
@@ -173,8 +357,7 @@ func (nm *NotificationManager) ShowTeamCheckpointShared(teamSize int, checkpoint
 		return nil
 	}
 
-	message := fmt.Sprintf(
-		"📤 Checkpoint shared with team (%d members)\n%s",
+	message := i18n.T("notify.team_shared",
 		teamSize,
 		checkpointID,
 	)
@@ -197,8 +380,7 @@ func (nm *NotificationManager) ShowTeamCheckpointAvailable(checkpointID string,
 		return nil
 	}
 
-	message := fmt.Sprintf(
-		"📥 New team checkpoint available\nFrom: %s\n%s",
+	message := i18n.T("notify.team_available",
 		memberName,
 		checkpointID,
 	)
@@ -211,46 +393,57 @@ func (nm *NotificationManager) ShowTeamCheckpointAvailable(checkpointID string,
 	return nil
 }
 
-// showBannerNotification displays a banner notification using macOS native notifications
+// showBannerNotification displays a banner notification through the backend
 func (nm *NotificationManager) showBannerNotification(message string, notifType NotificationType, duration time.Duration) error {
-	// Escape quotes in message for AppleScript
-	escapedMessage := strings.ReplaceAll(message, `"`, `\"`)
-	escapedMessage = strings.ReplaceAll(escapedMessage, "\n", "\\n")
-
-	// Build AppleScript notification
-	script := fmt.Sprintf(`
-        display notification "%s" with title "RESPAWN" sound name "Glass"
-    `, escapedMessage)
-
-	// Execute AppleScript
-	cmd := exec.Command("osascript", "-e", script)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to show notification: %w (output: %s)", err, string(output))
+	nm.mu.Lock()
+	level, quietHours := nm.level, nm.quietHours
+	nm.mu.Unlock()
+
+	// Respect the configured notification level
+	if !levelAllows(level, notifType) {
+		system.Debug("Notification suppressed by notification level", level, ":", message)
+		return nil
+	}
+
+	// Quiet hours hold back everything but errors for the digest
+	if notifType != NotificationError && quietHours.Contains(time.Now()) {
+		system.Debug("Quiet hours - notification queued for digest:", message)
+		return queueDigest(message)
+	}
+
+	if err := nm.backend.Banner(message, notifType); err != nil {
+		return err
 	}
 
 	system.Debug("Notification shown:", message)
+	nm.mu.Lock()
 	nm.lastNotification = time.Now()
+	nm.mu.Unlock()
 
 	return nil
 }
 
+// levelAllows reports whether a notification type passes a notification level
+func levelAllows(level string, notifType NotificationType) bool {
+	switch level {
+	case "none":
+		return false
+	case "errors":
+		return notifType == NotificationError || notifType == NotificationWarning
+	default:
+		return true
+	}
+}
+
 // isDoNotDisturbActive checks if macOS Do Not Disturb is enabled
 func (nm *NotificationManager) isDoNotDisturbActive() bool {
-	// Check macOS Focus mode status
-	// Using plutil to read Focus preferences
-	cmd := exec.Command("defaults", "read", "com.apple.ncprefs", "dnd_prefs")
-	output, err := cmd.Output()
+	dndActive, err := nm.backend.DoNotDisturb()
 	if err != nil {
 		// If we can't read DND status, assume it's not active
 		system.Debug("Could not read DND status, assuming inactive")
 		return false
 	}
 
-	// Simple check - if DND plist exists and contains "enabled"
-	dndActive := strings.Contains(string(output), "userPref") &&
-		strings.Contains(string(output), "enabled = 1")
-
 	if dndActive {
 		system.Debug("Do Not Disturb is active")
 	}
@@ -260,24 +453,13 @@ func (nm *NotificationManager) isDoNotDisturbActive() bool {
 
 // formatDuration formats duration for user display
 func (nm *NotificationManager) formatDuration(d time.Duration) string {
-	seconds := int(d.Seconds())
-
-	if seconds < 60 {
-		return fmt.Sprintf("%d seconds", seconds)
-	}
-
-	minutes := seconds / 60
-	remainingSeconds := seconds % 60
-
-	if remainingSeconds == 0 {
-		return fmt.Sprintf("%d minutes", minutes)
-	}
-
-	return fmt.Sprintf("%d minutes %d seconds", minutes, remainingSeconds)
+	return i18n.Duration(d)
 }
 
 // GetLastNotificationTime returns when the last notification was shown
 func (nm *NotificationManager) GetLastNotificationTime() time.Time {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
 	return nm.lastNotification
 }
 
@@ -287,6 +469,11 @@ func (nm *NotificationManager) SetRespectDND(respect bool) {
 	system.Debug("Do Not Disturb respect set to:", respect)
 }
 
+// SetBackend replaces how notifications and dialogs are delivered
+func (nm *NotificationManager) SetBackend(backend Backend) {
+	nm.backend = backend
+}
+
 // SetInteractive enables or disables interactive notifications
 func (nm *NotificationManager) SetInteractive(interactive bool) {
 	nm.isInteractive = interactive
@@ -299,8 +486,7 @@ func (nm *NotificationManager) ShowRestorationProgress(current, total int, curre
 		return nil // Non-interactive mode
 	}
 
-	message := fmt.Sprintf(
-		"Restoring: %s\n%d of %d applications",
+	message := i18n.T("notify.restore_progress",
 		currentApp,
 		current,
 		total,
@@ -318,12 +504,7 @@ func (nm *NotificationManager) ShowRestorationProgress(current, total int, curre
 func (nm *NotificationManager) ShowStatusSummary(summary types.StatusSummary) error {
 	system.Info("Showing status summary")
 
-	message := fmt.Sprintf(
-		"RESPAWN Status\n\n"+
-			"Last Checkpoint: %s\n"+
-			"Total Checkpoints: %d\n"+
-			"Auto-start: %s\n"+
-			"Health: %s",
+	message := i18n.T("notify.status_summary",
 		summary.LastCheckpoint.Format("15:04 PM"),
 		summary.TotalCheckpoints,
 		nm.boolToStatus(summary.AutoStartEnabled),
@@ -340,9 +521,9 @@ func (nm *NotificationManager) ShowStatusSummary(summary types.StatusSummary) er
 // boolToStatus converts boolean to status string
 func (nm *NotificationManager) boolToStatus(enabled bool) string {
 	if enabled {
-		return "✅ Enabled"
+		return i18n.T("enabled")
 	}
-	return "❌ Disabled"
+	return i18n.T("disabled")
 }
 
 // ShowCriticalAlert shows critical system alert (crashes, major failures)
@@ -350,13 +531,15 @@ func (nm *NotificationManager) ShowCriticalAlert(title, message string) error {
 	system.Error("Critical alert:", title, "-", message)
 
 	// Critical alerts always bypass DND
-	// Use macOS dialog for critical alerts (more prominent than notifications)
-	script := fmt.Sprintf(`
-        display dialog "%s" with title "%s" buttons {"OK"} default button "OK" with icon stop
-    `, strings.ReplaceAll(message, `"`, `\"`), title)
-
-	cmd := exec.Command("osascript", "-e", script)
-	if err := cmd.Run(); err != nil {
+	// Use a dialog for critical alerts (more prominent than notifications)
+	_, err := nm.backend.Dialog(Dialog{
+		Title:         title,
+		Message:       message,
+		Buttons:       []string{i18n.T("dialog.ok")},
+		DefaultButton: i18n.T("dialog.ok"),
+		Icon:          "stop",
+	})
+	if err != nil {
 		// Fallback to notification if dialog fails
 		return nm.showBannerNotification(
 			fmt.Sprintf("%s\n\n%s", title, message),
@@ -372,18 +555,18 @@ func (nm *NotificationManager) ShowCriticalAlert(title, message string) error {
 func (nm *NotificationManager) ShowPermissionRequest(permissionType, instructions string) (string, error) {
 	system.Info("Requesting permission:", permissionType)
 
-	message := fmt.Sprintf(
-		"RESPAWN needs %s permission.\n\n%s",
+	message := i18n.T("dialog.permission",
 		permissionType,
 		instructions,
 	)
 
-	script := fmt.Sprintf(`
-        display dialog "%s" with title "Permission Required" buttons {"Grant Permission", "Quit"} default button "Grant Permission" with icon caution
-    `, strings.ReplaceAll(message, `"`, `\"`))
-
-	cmd := exec.Command("osascript", "-e", script)
-	output, err := cmd.Output()
+	button, err := nm.backend.Dialog(Dialog{
+		Title:         i18n.T("dialog.permission_title"),
+		Message:       message,
+		Buttons:       []string{i18n.T("dialog.grant"), i18n.T("dialog.quit")},
+		DefaultButton: i18n.T("dialog.grant"),
+		Icon:          "caution",
+	})
 
 	if err != nil {
 		system.Warn("User declined permission or dialog failed") 
@@ -391,7 +574,8 @@ func (nm *NotificationManager) ShowPermissionRequest(permissionType, instruction
 	}
 
 	// Check which button was clicked
-	if strings.Contains(string(output), "Grant Permission") {
+	// Callers get the English button name whatever the locale
+	if button == i18n.T("dialog.grant") {
 		return "Grant Permission",nil
 	}
 
@@ -407,27 +591,21 @@ func (nm *NotificationManager) ShowRestoreOptionsMenu(checkpoints []string) (int
 	system.Info("Showing restore options menu")
 
 	// Build checkpoint list for dialog
-	checkpointList := strings.Join(checkpoints, "\\n")
-
-	message := fmt.Sprintf(
-		"Available Checkpoints:\\n\\n%s\\n\\nEnter checkpoint number to restore:",
-		checkpointList,
-	)
-
-	script := fmt.Sprintf(`
-        set response to text returned of (display dialog "%s" with title "Select Checkpoint" default answer "1" buttons {"Restore", "Cancel"} default button "Restore")
-        return response
-    `, message)
-
-	cmd := exec.Command("osascript", "-e", script)
-	output, err := cmd.Output()
+	checkpointList := strings.Join(checkpoints, "\n")
+
+	answer, err := nm.backend.Ask(Dialog{
+		Title:         i18n.T("dialog.select_title"),
+		Message:       i18n.T("dialog.select", checkpointList),
+		Buttons:       []string{i18n.T("dialog.restore"), i18n.T("dialog.cancel")},
+		DefaultButton: i18n.T("dialog.restore"),
+	}, "1")
 	if err != nil {
 		system.Debug("User cancelled checkpoint selection")
 		return -1, fmt.Errorf("user cancelled")
 	}
 
 	// Parse selected checkpoint number
-	selectedStr := strings.TrimSpace(string(output))
+	selectedStr := strings.TrimSpace(answer)
 	var selected int
 	if _, err := fmt.Sscanf(selectedStr, "%d", &selected); err != nil {
 		return -1, fmt.Errorf("invalid selection: %s", selectedStr)
@@ -0,0 +1,290 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"RESPAWN/internal/i18n"
+	"RESPAWN/pkg/config"
+)
+
+// fakeBackend records what the manager delivers instead of running osascript
+type fakeBackend struct {
+	banners []string
+	dialogs []Dialog
+	opened  []string
+	button  string // Returned by Dialog
+	dnd     bool
+	failing bool // Dialog and Banner fail
+}
+
+func (f *fakeBackend) Banner(message string, notifType NotificationType) error {
+	if f.failing {
+		return errors.New("no notification center")
+	}
+	f.banners = append(f.banners, message)
+	return nil
+}
+
+func (f *fakeBackend) Dialog(d Dialog) (string, error) {
+	if f.failing {
+		return "", errors.New("dialog failed")
+	}
+	f.dialogs = append(f.dialogs, d)
+	return f.button, nil
+}
+
+func (f *fakeBackend) Ask(d Dialog, defaultAnswer string) (string, error) {
+	f.dialogs = append(f.dialogs, d)
+	return f.button, nil
+}
+
+func (f *fakeBackend) DoNotDisturb() (bool, error) {
+	return f.dnd, nil
+}
+
+func (f *fakeBackend) Open(url string) error {
+	f.opened = append(f.opened, url)
+	return nil
+}
+
+// newTestManager returns a manager on a fake backend, with English messages and a
+// temporary data directory
+func newTestManager(t *testing.T, configure func(cfg *config.Config)) (*NotificationManager, *fakeBackend) {
+	t.Helper()
+
+	previous := config.Current()
+	t.Cleanup(func() { config.SetCurrent(previous) })
+
+	cfg := config.DefaultConfig()
+	cfg.DataDir = t.TempDir()
+	cfg.Language = "en"
+	if configure != nil {
+		configure(cfg)
+	}
+	config.SetCurrent(cfg)
+
+	backend := &fakeBackend{}
+	nm := NewNotificationManager()
+	nm.SetBackend(backend)
+	return nm, backend
+}
+
+// allDay is a quiet hours window that contains the current time, whenever the test runs
+func allDay() config.ClockWindow {
+	now := time.Now()
+	return config.ClockWindow(now.Add(-time.Hour).Format("15:04") + "-" + now.Add(time.Hour).Format("15:04"))
+}
+
+func TestShowInfoRespectsDoNotDisturb(t *testing.T) {
+	nm, backend := newTestManager(t, nil)
+	backend.dnd = true
+
+	if err := nm.ShowInfo("Welcome Back", "Slept 8h"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.banners) != 0 {
+		t.Fatalf("info shown during Do Not Disturb: %q", backend.banners)
+	}
+
+	nm.SetRespectDND(false)
+	if err := nm.ShowInfo("Welcome Back", "Slept 8h"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.banners) != 1 {
+		t.Fatalf("got %d banners with DND respect off, want 1", len(backend.banners))
+	}
+}
+
+func TestShowErrorBypassesDoNotDisturb(t *testing.T) {
+	nm, backend := newTestManager(t, nil)
+	backend.dnd = true
+
+	if err := nm.ShowError("Restore Failed", "no checkpoints"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.banners) != 1 || backend.banners[0] != "Restore Failed\n\nno checkpoints" {
+		t.Fatalf("banners = %q, want the titled error", backend.banners)
+	}
+}
+
+func TestNotificationLevel(t *testing.T) {
+	tests := []struct {
+		level string
+		want  int // Banners out of an error, a warning and an info
+	}{
+		{"all", 3},
+		{"errors", 2},
+		{"none", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			nm, backend := newTestManager(t, func(cfg *config.Config) { cfg.NotificationLevel = tt.level })
+
+			nm.ShowError("Error", "failed")
+			nm.ShowWarning("Warning", "skipped")
+			nm.ShowInfo("Info", "done")
+
+			if len(backend.banners) != tt.want {
+				t.Errorf("level %s showed %d banners, want %d", tt.level, len(backend.banners), tt.want)
+			}
+		})
+	}
+}
+
+func TestQuietHoursQueueDigest(t *testing.T) {
+	nm, backend := newTestManager(t, func(cfg *config.Config) { cfg.QuietHours = allDay() })
+
+	nm.ShowInfo("Welcome Back", "Slept 8h")
+	nm.ShowStatus("RESPAWN Paused", "Checkpoints paused")
+	nm.ShowError("Checkpoint Failed", "disk full")
+
+	if len(backend.banners) != 1 || !strings.HasPrefix(backend.banners[0], "Checkpoint Failed") {
+		t.Fatalf("banners during quiet hours = %q, want only the error", backend.banners)
+	}
+
+	// Still quiet, so the digest waits
+	if err := nm.FlushDigest(); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.banners) != 1 {
+		t.Fatalf("digest shown during quiet hours")
+	}
+
+	nm.quietHours = ""
+	if err := nm.FlushDigest(); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.banners) != 2 {
+		t.Fatalf("got %d banners after quiet hours, want the digest", len(backend.banners))
+	}
+	digest := backend.banners[1]
+	for _, want := range []string{i18n.T("notify.digest"), "Welcome Back", "RESPAWN Paused"} {
+		if !strings.Contains(digest, want) {
+			t.Errorf("digest %q is missing %q", digest, want)
+		}
+	}
+
+	// The digest is delivered once
+	nm.FlushDigest()
+	if len(backend.banners) != 2 {
+		t.Errorf("digest delivered twice")
+	}
+}
+
+func TestDigestTruncates(t *testing.T) {
+	nm, backend := newTestManager(t, func(cfg *config.Config) { cfg.QuietHours = allDay() })
+
+	for i := 0; i < digestMaxLines+3; i++ {
+		nm.ShowInfo("Info", "message")
+	}
+	nm.quietHours = ""
+	nm.FlushDigest()
+
+	if len(backend.banners) != 1 {
+		t.Fatalf("got %d banners, want the digest", len(backend.banners))
+	}
+	if want := i18n.T("notify.digest_more", 3); !strings.Contains(backend.banners[0], want) {
+		t.Errorf("digest %q doesn't end with %q", backend.banners[0], want)
+	}
+}
+
+func TestSnoozeDialogChoices(t *testing.T) {
+	tests := []struct {
+		button string
+		want   PromptChoice
+	}{
+		{i18n.T("dialog.restore"), ChoiceAccept},
+		{i18n.T("dialog.later"), ChoiceSnooze},
+		{i18n.T("dialog.not_now"), ChoiceDismiss},
+		{"", ChoiceDismiss}, // Gave up
+	}
+
+	for _, tt := range tests {
+		nm, backend := newTestManager(t, nil)
+		backend.button = tt.button
+
+		if got := nm.ShowRestorePrompt("Mon Jan 01 09:00", "3 apps · Safari · Terminal · Xcode"); got != tt.want {
+			t.Errorf("button %q gave choice %d, want %d", tt.button, got, tt.want)
+		}
+		d := backend.dialogs[0]
+		if d.DefaultButton != i18n.T("dialog.restore") || len(d.Buttons) != 3 || d.GiveUpAfter == 0 {
+			t.Errorf("unexpected restore prompt %+v", d)
+		}
+	}
+
+	nm, backend := newTestManager(t, nil)
+	backend.failing = true
+	if got := nm.ShowRestorePrompt("Mon Jan 01 09:00", "3 apps · Safari · Terminal · Xcode"); got != ChoiceDismiss {
+		t.Errorf("failed dialog gave choice %d, want dismiss", got)
+	}
+}
+
+func TestDiskSpaceAlert(t *testing.T) {
+	nm, backend := newTestManager(t, nil)
+
+	// Below critical, a banner suggests the prune command
+	choice, err := nm.ShowDiskSpaceAlert(80, false, 4, "1.2 GB", "1229MB")
+	if err != nil || choice != ChoiceDismiss {
+		t.Fatalf("warning alert = %d, %v", choice, err)
+	}
+	if len(backend.banners) != 1 || !strings.Contains(backend.banners[0], "respawn prune --free 1229MB") {
+		t.Fatalf("banners = %q, want the prune command", backend.banners)
+	}
+
+	// Critical, pruning is left to the caller rather than a URL anything could open
+	backend.button = i18n.T("dialog.prune")
+	choice, err = nm.ShowDiskSpaceAlert(95, true, 4, "1.2 GB", "1229MB")
+	if err != nil || choice != ChoiceAccept {
+		t.Fatalf("critical alert = %d, %v", choice, err)
+	}
+	if len(backend.opened) != 0 {
+		t.Errorf("opened %q, want nothing", backend.opened)
+	}
+}
+
+func TestCriticalAlertFallsBackToBanner(t *testing.T) {
+	nm, backend := newTestManager(t, nil)
+	backend.failing = true
+
+	if err := nm.ShowCriticalAlert("Crashed", "details"); err == nil {
+		t.Fatal("expected the banner fallback to fail too")
+	}
+
+	backend.failing = false
+	if err := nm.ShowCriticalAlert("Crashed", "details"); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.dialogs) != 1 || backend.dialogs[0].Icon != "stop" {
+		t.Errorf("dialogs = %+v, want one stop dialog", backend.dialogs)
+	}
+}
+
+func TestParseButtonReturned(t *testing.T) {
+	tests := map[string]string{
+		"button returned:Prune, gave up:false\n":         "Prune",
+		"button returned:, gave up:true\n":               "",
+		"button returned:OK\n":                           "OK",
+		"button returned:Remind Me Later, gave up:false": "Remind Me Later",
+		"": "",
+	}
+	for output, want := range tests {
+		if got := parseButtonReturned(output); got != want {
+			t.Errorf("parseButtonReturned(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
+
+func TestDNDEnabled(t *testing.T) {
+	on := "{\n    userPref = {\n        enabled = 1;\n    };\n}"
+	off := "{\n    userPref = {\n        enabled = 0;\n    };\n}"
+	if !dndEnabled(on) {
+		t.Error("DND on was read as off")
+	}
+	if dndEnabled(off) || dndEnabled("") {
+		t.Error("DND off was read as on")
+	}
+}
@@ -0,0 +1,39 @@
+package workspace
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Suggest proposes a starter template from how often each app was seen. Apps in
+// top (the learned top three) come first, then the most frequent others, up to limit.
+func Suggest(name string, frequency map[string]int, top []string, limit int) (*Template, error) {
+	if len(frequency) == 0 && len(top) == 0 {
+		return nil, fmt.Errorf("no usage data yet - let the daemon run for a while first")
+	}
+
+	ranked := make([]string, 0, len(frequency))
+	for app := range frequency {
+		ranked = append(ranked, app)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if frequency[ranked[i]] != frequency[ranked[j]] {
+			return frequency[ranked[i]] > frequency[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+
+	t := &Template{
+		Name:        name,
+		Description: "Suggested from observed app usage - edit before applying",
+	}
+	seen := make(map[string]bool)
+	for _, app := range append(append([]string{}, top...), ranked...) {
+		if seen[app] || (limit > 0 && len(t.Apps) >= limit) {
+			continue
+		}
+		seen[app] = true
+		t.Apps = append(t.Apps, App{Name: app})
+	}
+	return t, nil
+}
@@ -0,0 +1,259 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"RESPAWN/internal/process"
+	"RESPAWN/internal/system"
+	"RESPAWN/internal/types"
+	"RESPAWN/pkg/config"
+)
+
+// templateExt is the file extension of saved templates
+const templateExt = ".yaml"
+
+// Template describes a desired workspace, independent of any captured checkpoint
+type Template struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Apps        []App  `yaml:"apps"`
+}
+
+// App is one application in a template and what it should have open
+type App struct {
+	Name      string   `yaml:"name"`
+	BundleID  string   `yaml:"bundle_id,omitempty"`
+	Documents []string `yaml:"documents,omitempty"` // Files or folders opened in the app; ~ is expanded
+	URLs      []string `yaml:"urls,omitempty"`      // Opened in the app, e.g. a browser
+	Window    *Window  `yaml:"window,omitempty"`
+}
+
+// Window is the layout of an app's front window
+type Window struct {
+	State  string `yaml:"state,omitempty"` // "normal", "minimized" or "maximized"
+	X      int    `yaml:"x,omitempty"`
+	Y      int    `yaml:"y,omitempty"`
+	Width  int    `yaml:"width,omitempty"`
+	Height int    `yaml:"height,omitempty"`
+}
+
+// hasBounds reports whether the window has an explicit position and size
+func (w *Window) hasBounds() bool {
+	return w != nil && w.Width > 0 && w.Height > 0
+}
+
+// Dir returns where named templates are kept, under the data directory
+func Dir() string {
+	cfg := config.Current()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return filepath.Join(cfg.DataDir, "templates")
+}
+
+// resolvePath turns a template name into its file path; anything that looks like a path is used as is
+func resolvePath(nameOrPath string) string {
+	if strings.ContainsRune(nameOrPath, os.PathSeparator) || filepath.Ext(nameOrPath) != "" {
+		return expandHome(nameOrPath)
+	}
+	return filepath.Join(Dir(), nameOrPath+templateExt)
+}
+
+// Load reads a template by name (from the templates directory) or by path
+func Load(nameOrPath string) (*Template, error) {
+	path := resolvePath(nameOrPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("template %q not found (looked for %s)", nameOrPath, path)
+		}
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return &t, nil
+}
+
+// Save writes the template to the templates directory, or to path when given, and returns where it went
+func (t *Template) Save(path string) (string, error) {
+	if path == "" {
+		path = resolvePath(t.Name)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create templates directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(t)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode template: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write template: %w", err)
+	}
+	return path, nil
+}
+
+// Validate checks that every app is named and window states are known
+func (t *Template) Validate() error {
+	if len(t.Apps) == 0 {
+		return fmt.Errorf("no apps listed")
+	}
+	for i, app := range t.Apps {
+		if app.Name == "" {
+			return fmt.Errorf("app at index %d needs a name", i)
+		}
+		// Names end up in AppleScript string literals, where these would break out
+		if strings.ContainsAny(app.Name, "\"\\\n\r") {
+			return fmt.Errorf("app name %q can't contain quotes, backslashes or line breaks", app.Name)
+		}
+		if app.Window == nil {
+			continue
+		}
+		switch app.Window.State {
+		case "", "normal", "minimized", "maximized":
+		default:
+			return fmt.Errorf("app %s has unknown window state %q", app.Name, app.Window.State)
+		}
+	}
+	return nil
+}
+
+// List returns the names of saved templates, sorted
+func List() ([]string, error) {
+	entries, err := os.ReadDir(Dir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == templateExt {
+			names = append(names, strings.TrimSuffix(entry.Name(), templateExt))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// FromCheckpoint turns a checkpoint into a template that can be edited and applied later
+func FromCheckpoint(cp *types.Checkpoint, name string) *Template {
+	t := &Template{
+		Name:        name,
+		Description: fmt.Sprintf("Exported from checkpoint %s", cp.ID),
+	}
+	if cp.Note != "" {
+		t.Description += " - " + cp.Note
+	}
+
+	for _, proc := range cp.Processes {
+		app := App{Name: proc.Name, BundleID: proc.BundleID}
+		if proc.WindowState != "" && proc.WindowState != "normal" {
+			app.Window = &Window{State: proc.WindowState}
+		}
+		t.Apps = append(t.Apps, app)
+	}
+	return t
+}
+
+// processes converts the template apps to what the launcher restores
+func (t *Template) processes() []types.ProcessInfo {
+	procs := make([]types.ProcessInfo, 0, len(t.Apps))
+	for _, app := range t.Apps {
+		state := "normal"
+		if app.Window != nil && app.Window.State != "" {
+			state = app.Window.State
+		}
+		procs = append(procs, types.ProcessInfo{
+			Name:        app.Name,
+			ProcessName: app.Name,
+			BundleID:    app.BundleID,
+			WindowState: state,
+		})
+	}
+	return procs
+}
+
+// Apply launches the template's apps, then opens their documents and URLs and places their windows.
+// Apps that are already running are left running; their documents and URLs are still opened.
+// progress, when not nil, is told about each app as it launches.
+func (t *Template) Apply(progress process.ProgressFunc) ([]types.LaunchResult, error) {
+	system.Info("Applying template", t.Name)
+
+	launcher := process.NewApplicationLauncher()
+	launcher.SetProgressHandler(progress)
+	results, err := launcher.RestoreApplications(t.processes())
+	if err != nil {
+		return results, err
+	}
+
+	for _, app := range t.Apps {
+		if targets := app.targets(); len(targets) > 0 {
+			args := append([]string{"-a", app.Name}, targets...)
+			if output, err := exec.Command("open", args...).CombinedOutput(); err != nil {
+				system.Warn("Failed to open items in", app.Name, ":", err, strings.TrimSpace(string(output)))
+			}
+		}
+		if app.Window.hasBounds() {
+			app.placeWindow()
+		}
+	}
+
+	system.Info("Applied template", t.Name)
+	return results, nil
+}
+
+// targets lists the documents and URLs to open in the app
+func (app App) targets() []string {
+	targets := make([]string, 0, len(app.Documents)+len(app.URLs))
+	for _, doc := range app.Documents {
+		targets = append(targets, expandHome(doc))
+	}
+	return append(targets, app.URLs...)
+}
+
+// placeWindow moves and resizes the app's front window
+func (app App) placeWindow() {
+	w := app.Window
+	script := fmt.Sprintf(`
+		tell application "System Events"
+			tell application process %s
+				if exists window 1 then
+					set position of window 1 to {%d, %d}
+					set size of window 1 to {%d, %d}
+				end if
+			end tell
+		end tell
+	`, system.AppleScriptString(app.Name), w.X, w.Y, w.Width, w.Height)
+
+	if err := exec.Command("osascript", "-e", script).Run(); err != nil {
+		system.Warn("Failed to place window for", app.Name, ":", err)
+	}
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 )
@@ -14,6 +17,206 @@ type AppConfig struct {
 	Name        string `json:"name"`
 	ProcessName string `json:"process_name"`
 	Enabled     bool   `json:"enabled"`
+
+	// Files where the app saves its own window bounds (e.g. Electron window-state.json).
+	// Absolute, ~/ or relative to ~/Library/Application Support.
+	WindowStateFiles []string `json:"window_state_files,omitempty"`
+}
+
+// WebhookConfig is an endpoint that receives lifecycle events as JSON POSTs
+type WebhookConfig struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"` // e.g. "checkpoint.created"; empty means all events
+	Secret string   `json:"secret,omitempty"` // Signs each body with HMAC-SHA256 in X-Respawn-Signature
+}
+
+// Wants reports whether the webhook subscribes to an event
+func (w WebhookConfig) Wants(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// DebugConfig holds diagnostics that are off by default
+type DebugConfig struct {
+	PProf        bool   `json:"pprof"`                   // Serve net/http/pprof for profiling the daemon
+	PProfAddress string `json:"pprof_address,omitempty"` // Loopback only, default 127.0.0.1:7374
+}
+
+// SystemConfigDir holds machine-wide settings for fleet deployments; see SystemConfigTemplate
+const SystemConfigDir = "/Library/Application Support/respawn"
+
+// SystemConfigTemplate is the config an administrator deploys for every user. A user
+// without a config of their own starts from it; data_dir stays in their home.
+var SystemConfigTemplate = filepath.Join(SystemConfigDir, "config.json")
+
+// ProfileEnv overrides the active profile, e.g. RESPAWN_PROFILE=home
+const ProfileEnv = "RESPAWN_PROFILE"
+
+// ProfileConfig holds settings for one checkpoint profile, e.g. "work" or "home"
+type ProfileConfig struct {
+	Name          string `json:"name"`
+	RetentionDays int    `json:"retention_days,omitempty"` // 0 uses data_rentention_days
+}
+
+// ScheduledLaunch restores a checkpoint or applies a template at a set time, e.g. work apps at 08:55 on weekdays
+type ScheduledLaunch struct {
+	Name       string   `json:"name"`
+	At         string   `json:"at"`                   // Local time, "15:04"
+	Days       []string `json:"days,omitempty"`       // "mon".."sun", "weekdays" or "weekends"; empty means every day
+	Checkpoint string   `json:"checkpoint,omitempty"` // Checkpoint ID, or "latest"
+	Template   string   `json:"template,omitempty"`   // Workspace template name or path, instead of a checkpoint
+}
+
+// launchDays are the values allowed in ScheduledLaunch.Days
+var launchDays = map[string]bool{
+	"mon": true, "tue": true, "wed": true, "thu": true, "fri": true, "sat": true, "sun": true,
+	"daily": true, "weekdays": true, "weekends": true,
+}
+
+// Occurrence returns when the launch is scheduled on t's day, and false when it
+// isn't scheduled that day
+func (l ScheduledLaunch) Occurrence(t time.Time) (time.Time, bool) {
+	at, err := time.Parse("15:04", l.At)
+	if err != nil || !l.onDay(t) {
+		return time.Time{}, false
+	}
+	year, month, day := t.Date()
+	return time.Date(year, month, day, at.Hour(), at.Minute(), 0, 0, t.Location()), true
+}
+
+// onDay reports whether the launch is scheduled on t's weekday
+func (l ScheduledLaunch) onDay(t time.Time) bool {
+	if len(l.Days) == 0 {
+		return true
+	}
+
+	day := strings.ToLower(t.Weekday().String()[:3])
+	weekend := t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+	for _, d := range l.Days {
+		switch strings.ToLower(d) {
+		case day, "daily":
+			return true
+		case "weekdays":
+			if !weekend {
+				return true
+			}
+		case "weekends":
+			if weekend {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Window privacy levels for window_privacy, from least to most data stored
+const (
+	PrivacyNone     = "none"     // Only which apps were running
+	PrivacyGeometry = "geometry" // Plus window positions and sizes
+	PrivacyTitles   = "titles"   // Plus window titles, e.g. the focused chat channel
+	PrivacyFull     = "full"     // Plus open documents, browser URLs and apps' window-state files
+)
+
+// privacyRank orders the privacy levels by how much they store
+var privacyRank = map[string]int{
+	PrivacyNone:     0,
+	PrivacyGeometry: 1,
+	PrivacyTitles:   2,
+	PrivacyFull:     3,
+}
+
+// Ways to resolve sync_conflicts, when a sync client left two versions of a checkpoint
+const (
+	ConflictsKeepBoth = "keep-both" // The conflicted copy becomes a checkpoint of its own, ID-<checksum prefix>
+	ConflictsNewest   = "newest"    // The most recently taken checkpoint replaces the other
+)
+
+// Checkpoint triggers, recorded with each checkpoint and used as retention_by_trigger keys
+const (
+	TriggerScheduled     = "scheduled"      // The daemon's regular interval
+	TriggerManual        = "manual"         // respawn checkpoint, the URL scheme, or confirmed on quit
+	TriggerPreSleep      = "pre-sleep"      // SIGUSR1, sent by sleep hooks
+	TriggerPreRestore    = "pre-restore"    // The workspace just before restoring an older checkpoint
+	TriggerCrashRecovery = "crash-recovery" // Taken when the daemon recovers from its own crash
+)
+
+// checkpointTriggers lists the valid triggers
+var checkpointTriggers = map[string]bool{
+	TriggerScheduled:     true,
+	TriggerManual:        true,
+	TriggerPreSleep:      true,
+	TriggerPreRestore:    true,
+	TriggerCrashRecovery: true,
+}
+
+// ClockWindow is a daily local time range such as "02:00-04:00". The end may be
+// earlier than the start for windows that span midnight, e.g. "23:00-01:00".
+type ClockWindow string
+
+// bounds returns the window's start and end as offsets from midnight
+func (w ClockWindow) bounds() (start, end time.Duration, err error) {
+	from, to, ok := strings.Cut(string(w), "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid time window %q, use HH:MM-HH:MM", string(w))
+	}
+	startTime, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time window %q, use HH:MM-HH:MM", string(w))
+	}
+	endTime, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time window %q, use HH:MM-HH:MM", string(w))
+	}
+
+	start = time.Duration(startTime.Hour())*time.Hour + time.Duration(startTime.Minute())*time.Minute
+	end = time.Duration(endTime.Hour())*time.Hour + time.Duration(endTime.Minute())*time.Minute
+	if start == end {
+		return 0, 0, fmt.Errorf("time window %q is empty", string(w))
+	}
+	return start, end, nil
+}
+
+// Validate checks the window is well formed; an empty window is valid
+func (w ClockWindow) Validate() error {
+	if w == "" {
+		return nil
+	}
+	_, _, err := w.bounds()
+	return err
+}
+
+// Contains reports whether t's time of day falls inside the window. An empty or
+// invalid window contains nothing.
+func (w ClockWindow) Contains(t time.Time) bool {
+	start, end, err := w.bounds()
+	if err != nil {
+		return false
+	}
+
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if start < end {
+		return offset >= start && offset < end
+	}
+	return offset >= start || offset < end
+}
+
+// Length is how long the window lasts each day
+func (w ClockWindow) Length() time.Duration {
+	start, end, err := w.bounds()
+	if err != nil {
+		return 0
+	}
+	if end < start {
+		end += 24 * time.Hour
+	}
+	return end - start
 }
 
 type Config struct {
@@ -22,12 +225,81 @@ type Config struct {
 
 	// checkpoint settings
 	CheckpointInterval time.Duration	`json:"checkpoint_interval"`
+	TriggerDebounce    time.Duration `json:"trigger_debounce"` // Triggers this close to the last checkpoint are coalesced; 0 disables
 	DataRetentionDays  int 		`json:"data_rentention_days"`
+	RetentionByTrigger map[string]int `json:"retention_by_trigger,omitempty"` // Days to keep checkpoints by trigger, when longer than the profile's retention
+
+	// Monitor loop intervals
+	MonitorInterval   time.Duration `json:"monitor_interval"`
+	HeartbeatInterval time.Duration `json:"heartbeat_interval"`
+	LearningInterval  time.Duration `json:"learning_interval"`
 
 	// System settings
 	AutoRestore bool `json:"auto_restore"`
+	NotificationLevel string `json:"notification_level"` // "all", "errors" or "none"
+	SnoozeDuration time.Duration `json:"snooze_duration"` // How long "Remind Me Later" holds back a prompt
+	QuietHours ClockWindow `json:"quiet_hours,omitempty"` // e.g. "22:00-08:00"; only errors are shown, the rest waits for a digest
+	Language string `json:"language,omitempty"` // "en" or "es"; empty follows LANG
 	MaxRetryAttempts int `json:"max_retry_attempts"`
 	LaunchDelayMs int `json:"launch_delay_ms"`
+	ManualRestoreAfter int `json:"manual_restore_after"` // Restores an app may fail in a row before it is left to the user; 0 never
+	BlacklistDuration time.Duration `json:"blacklist_duration"` // How long apps that keep timing out are skipped; 0 never
+
+	// Self-monitoring limits
+	MaxMemoryMB            int     `json:"max_memory_mb"`
+	MaxCPUPercent          float64 `json:"max_cpu_percent"`
+	RestartOnResourceLimit bool    `json:"restart_on_resource_limit"`
+
+	// Auto-start is disabled after crash_threshold crashes within crash_window
+	CrashThreshold int           `json:"crash_threshold"`
+	CrashWindow    time.Duration `json:"crash_window"`
+
+	// Capture settings
+	WindowPrivacy string `json:"window_privacy"` // Most window data stored: "none", "geometry", "titles" or "full"; caps the capture_* settings below
+	CaptureListeningPorts bool `json:"capture_listening_ports"`
+	CaptureNetworkVolumes bool `json:"capture_network_volumes"`
+	RemountNetworkVolumes bool `json:"remount_network_volumes"`
+	CaptureAudioState     bool `json:"capture_audio_state"`
+	CaptureBrowserProfiles bool `json:"capture_browser_profiles"`
+	CaptureBrowserSessions bool `json:"capture_browser_sessions"`
+	CaptureWindowStateFiles bool `json:"capture_window_state_files"` // Snapshot apps' own settings files holding window bounds; restoring replaces the whole file
+	CaptureChatContext    bool `json:"capture_chat_context"`
+	CaptureDeveloperState bool `json:"capture_developer_state"` // Xcode, simulators and Docker
+	RestoreDockerProjects bool `json:"restore_docker_projects"` // Run docker compose up -d on restore
+	CaptureVirtualMachines bool     `json:"capture_virtual_machines"`
+	VMRestoreExclude       []string `json:"vm_restore_exclude,omitempty"` // VM names never resumed on restore
+	RestoreAudioState     bool `json:"restore_audio_state"`
+	RestorePlaybackState  bool `json:"restore_playback_state"` // Record and resume Music/Spotify
+
+	// Storage settings
+	CompressionLevel int `json:"compression_level"` // zstd level 1-22 for compressed checkpoints
+	WriteDebugJSON bool `json:"write_debug_json"` // Side-car pretty JSON of the latest checkpoint
+	DiskWarnPercent     int `json:"disk_warn_percent"`     // Alert with prune suggestions above this disk usage
+	DiskCriticalPercent int `json:"disk_critical_percent"` // Alert every maintenance run above this
+	MaintenanceMinBattery int `json:"maintenance_min_battery"` // On battery below this percent, compression and metrics writes wait for power; 0 disables
+	MaintenanceWindow ClockWindow `json:"maintenance_window"` // Local time range for compression, pruning and recompression; empty runs every 6 hours
+	AutoOptimize bool `json:"auto_optimize"` // Apply the suggested interval and compression changes to this file; off only logs them
+	MirrorDir      string `json:"mirror_dir,omitempty"` // Also write every checkpoint here, e.g. an external drive
+	MirrorMaxKBps     int  `json:"mirror_max_kbps,omitempty"`     // Cap on mirror writes in KB/s, for mirrors a sync client uploads; 0 is unlimited
+	MirrorOnlyOnPower bool `json:"mirror_only_on_power,omitempty"` // Hold mirroring back while on battery
+	MirrorOnlyOnWiFi  bool `json:"mirror_only_on_wifi,omitempty"`  // Hold mirroring back unless on Wi-Fi or a wired network, e.g. while tethered
+	SyncConflicts string `json:"sync_conflicts"` // Conflicted copies in a synced checkpoint directory: "keep-both" renames them, "newest" keeps the latest write
+	ExcludeFromTimeMachine bool `json:"exclude_from_time_machine"` // tmutil addexclusion on the checkpoint directory
+	ExcludeFromSpotlight   bool `json:"exclude_from_spotlight"`    // .metadata_never_index in the checkpoint directory
+
+	// Profiles keep separate checkpoint streams; empty profile is the default stream
+	Profile  string          `json:"profile,omitempty"`
+	Profiles []ProfileConfig `json:"profiles,omitempty"`
+
+	// Scheduled restores that bootstrap a workspace, independent of crash recovery
+	ScheduledLaunches []ScheduledLaunch `json:"scheduled_launches,omitempty"`
+
+	// Integrations
+	Webhooks   []WebhookConfig `json:"webhooks,omitempty"`
+	APIAddress string          `json:"api_address"` // Local API for GUIs: "unix:<socket path>" or a loopback host:port; empty disables it
+
+	// Diagnostics
+	Debug DebugConfig `json:"debug"`
 
 	// Paths
 	DataDir string `json:"data_dir"`
@@ -35,7 +307,27 @@ type Config struct {
 	ConfigPath string `json:"config_path"`
 }
 
-var GlobalConfig *Config 
+// current is the loaded config. Reload swaps in a new one rather than changing it,
+// so the daemon's goroutines can read it while the config file is reloaded.
+var current atomic.Pointer[Config]
+
+// Current returns the loaded config, nil until LoadConfig. It must not be modified;
+// read it once for settings that have to agree with each other.
+func Current() *Config {
+    return current.Load()
+}
+
+// SetCurrent replaces the loaded config, for tests that build one in memory
+func SetCurrent(c *Config) {
+    current.Store(c)
+}
+
+// Minimum loop intervals, anything faster would make RESPAWN far from invisible
+const (
+	MinMonitorInterval   = 10 * time.Second
+	MinHeartbeatInterval = 5 * time.Second
+	MinLearningInterval  = 1 * time.Minute
+)
 
 // DefaultConfig returns a config with sensible defaults
 func DefaultConfig() *Config {
@@ -56,10 +348,53 @@ func DefaultConfig() *Config {
 		},
 
 		CheckpointInterval: 15 * time.Minute, // 15 minutes 
+		TriggerDebounce: 2 * time.Minute,
 		DataRetentionDays: 7, // 7 days
+		RetentionByTrigger: map[string]int{
+			TriggerManual:   30, // Taken on purpose, worth keeping longer than routine ones
+			TriggerPreSleep: 14,
+		},
+		MonitorInterval: 10 * time.Minute,
+		HeartbeatInterval: 1 * time.Minute,
+		LearningInterval: 1 * time.Hour,
 		AutoRestore: true,
+		NotificationLevel: "all",
+		SnoozeDuration: 1 * time.Hour,
+		WindowPrivacy: PrivacyFull,
+		MaxMemoryMB: 150,
+		MaxCPUPercent: 5.0, // RESPAWN promises to be invisible
+		RestartOnResourceLimit: false,
+		CrashThreshold: 3,
+		CrashWindow: 1 * time.Hour,
 		MaxRetryAttempts: 3,
 		LaunchDelayMs: 7000, // 7 seconds
+		ManualRestoreAfter: 3,
+		BlacklistDuration: 24 * time.Hour,
+		CaptureListeningPorts: false,
+		CaptureNetworkVolumes: true,
+		RemountNetworkVolumes: false, // Opt-in, mounting can ask for share credentials mid-restore
+		CaptureAudioState: true,
+		CaptureBrowserProfiles: true,
+		CaptureBrowserSessions: false, // Opt-in, session files can be several MB per profile
+		CaptureWindowStateFiles: false, // Opt-in, the files hold more settings than window bounds
+		CaptureChatContext: true,
+		CaptureDeveloperState: true,
+		RestoreDockerProjects: false, // Opt-in, starting containers can be heavy
+		CaptureVirtualMachines: true,
+		RestoreAudioState: false, // Opt-in, changing volume unexpectedly is intrusive
+		RestorePlaybackState: false,
+		WriteDebugJSON: false,
+		CompressionLevel: 3, // zstd default
+		MaintenanceMinBattery: 30,
+		MaintenanceWindow: "", // Every 6 hours; a window suits Macs that stay awake overnight
+		AutoOptimize: false, // Opt-in, it rewrites the user's settings
+		DiskWarnPercent: 75,
+		DiskCriticalPercent: 90,
+		SyncConflicts: ConflictsKeepBoth,
+		ExcludeFromTimeMachine: true,
+		ExcludeFromSpotlight: true,
+		APIAddress: "unix:" + filepath.Join(dataDir, "respawn.sock"), // Per-user, so every user on the Mac gets their own
+		Debug: DebugConfig{PProfAddress: "127.0.0.1:7374"},
 		DataDir: dataDir,
 		LogDir: filepath.Join(dataDir, "logs"),
 		ConfigPath: filepath.Join(dataDir, "config.json"),
@@ -68,23 +403,81 @@ func DefaultConfig() *Config {
 
 // LoadConfig loads configuration from file or creates default
 func LoadConfig() error {
+    config, err := load()
+    if err != nil {
+        return err
+    }
+
+    current.Store(config)
+    return nil
+}
+
+// RestartSettings are the settings a running daemon only picks up on restart. Storage,
+// the instance lock and the API are set up from them once, so Reload keeps their old
+// values rather than sending lazily read paths somewhere else.
+var RestartSettings = []string{"data_dir", "log_dir", "profile", "mirror_dir", "api_address", "debug"}
+
+// Reload re-reads the config file and replaces the current config, returning the
+// names of the settings that changed, RestartSettings included even though they
+// keep their current values. On error the current config is left untouched.
+func Reload() ([]string, error) {
+    config, err := load()
+    if err != nil {
+        return nil, err
+    }
+
+    old := current.Load()
+    changes := changedSettings(old, config)
+    if old != nil {
+        config.DataDir = old.DataDir
+        config.LogDir = old.LogDir
+        config.ConfigPath = old.ConfigPath
+        config.Profile = old.Profile
+        config.MirrorDir = old.MirrorDir
+        config.APIAddress = old.APIAddress
+        config.Debug = old.Debug
+    }
+    current.Store(config)
+    return changes, nil
+}
+
+// Update re-reads the config file, applies change and saves the result if it still
+// validates, so automatic adjustments never clobber edits made since the last load.
+// The current config is left alone; call Reload to pick the change up.
+func Update(change func(c *Config)) error {
+    config, err := load()
+    if err != nil {
+        return err
+    }
+
+    change(config)
+    if err := config.Validate(); err != nil {
+        return fmt.Errorf("invalid configuration: %w", err)
+    }
+    return config.Save()
+}
+
+// load reads, validates and saves the config file
+func load() (*Config, error) {
     config := DefaultConfig()
     
     // Create data directory if it doesn't exist
     if err := os.MkdirAll(config.DataDir, 0755); err != nil {
-        return fmt.Errorf("failed to create data directory: %w", err)
+        return nil, fmt.Errorf("failed to create data directory: %w", err)
     }
     
     // Try to load existing config
     if _, err := os.Stat(config.ConfigPath); err == nil {
         data, err := os.ReadFile(config.ConfigPath)
         if err != nil {
-            return fmt.Errorf("failed to read config file: %w", err)
+            return nil, fmt.Errorf("failed to read config file: %w", err)
         }
         
         if err := json.Unmarshal(data, config); err != nil {
-            return fmt.Errorf("failed to parse config file: %w", err)
+            return nil, fmt.Errorf("failed to parse config file: %w", err)
         }
+    } else if err := config.applyTemplate(SystemConfigTemplate); err != nil {
+        return nil, err
     }
     
     // Set the config path (not saved to JSON)
@@ -92,17 +485,98 @@ func LoadConfig() error {
     
     // Validate configuration
     if err := config.Validate(); err != nil {
-        return fmt.Errorf("invalid configuration: %w", err)
+        return nil, fmt.Errorf("invalid configuration: %w", err)
     }
     
     // Save config (creates file if it doesn't exist or updates if validation fixed something)
     if err := config.Save(); err != nil {
-        return fmt.Errorf("failed to save config: %w", err)
+        return nil, fmt.Errorf("failed to save config: %w", err)
     }
     
-    GlobalConfig = config
+    return config, nil
+}
+
+// applyTemplate overlays the administrator's config template, if there is one. The
+// per-user paths are kept or derived from the data directory, so every user's
+// checkpoints, logs and API socket stay in their own home.
+func (c *Config) applyTemplate(path string) error {
+    data, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return nil
+    }
+    if err != nil {
+        return fmt.Errorf("failed to read config template: %w", err)
+    }
+
+    dataDir := c.DataDir
+    if err := json.Unmarshal(data, c); err != nil {
+        return fmt.Errorf("failed to parse config template %s: %w", path, err)
+    }
+    c.DataDir = dataDir
+    c.LogDir = filepath.Join(dataDir, "logs")
+    c.APIAddress = "unix:" + filepath.Join(dataDir, "respawn.sock")
     return nil
 }
+
+// changedSettings lists the JSON names of top-level settings that differ, sorted
+func changedSettings(old, updated *Config) []string {
+    if old == nil {
+        return nil
+    }
+
+    var before, after map[string]json.RawMessage
+    oldData, _ := json.Marshal(old)
+    newData, _ := json.Marshal(updated)
+    json.Unmarshal(oldData, &before)
+    json.Unmarshal(newData, &after)
+
+    var changes []string
+    for key, value := range after {
+        if string(before[key]) != string(value) {
+            changes = append(changes, key)
+        }
+    }
+    for key := range before {
+        if _, ok := after[key]; !ok {
+            changes = append(changes, key)
+        }
+    }
+    sort.Strings(changes)
+    return changes
+}
+// ActiveProfile returns the profile checkpoints go to, RESPAWN_PROFILE taking precedence over the config
+func (c *Config) ActiveProfile() string {
+    if profile := os.Getenv(ProfileEnv); profile != "" {
+        return profile
+    }
+    return c.Profile
+}
+
+// RetentionDaysFor returns how long checkpoints of a profile are kept
+func (c *Config) RetentionDaysFor(profile string) int {
+    for _, p := range c.Profiles {
+        if p.Name == profile && p.RetentionDays > 0 {
+            return p.RetentionDays
+        }
+    }
+    return c.DataRetentionDays
+}
+
+// RetentionDaysForTrigger returns how long checkpoints of a profile taken by trigger
+// are kept: retention_by_trigger when it is longer than the profile's retention
+func (c *Config) RetentionDaysForTrigger(profile, trigger string) int {
+    days := c.RetentionDaysFor(profile)
+    if byTrigger := c.RetentionByTrigger[trigger]; byTrigger > days {
+        return byTrigger
+    }
+    return days
+}
+
+// validProfileName rejects names that would escape the checkpoint directory
+func validProfileName(name string) bool {
+    return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
 // Save writes the configuration to file
 func (c *Config) Save() error {
     data, err := json.MarshalIndent(c, "", "  ")
@@ -129,6 +603,23 @@ func (c *Config) Validate() error {
         return fmt.Errorf("checkpoint_interval must be greater than 0")
     }
     
+    // Validate loop intervals
+    if c.MonitorInterval == 0 {
+        c.MonitorInterval = 10 * time.Minute // Fix with default
+    } else if c.MonitorInterval < MinMonitorInterval {
+        c.MonitorInterval = MinMonitorInterval
+    }
+    if c.HeartbeatInterval == 0 {
+        c.HeartbeatInterval = 1 * time.Minute // Fix with default
+    } else if c.HeartbeatInterval < MinHeartbeatInterval {
+        c.HeartbeatInterval = MinHeartbeatInterval
+    }
+    if c.LearningInterval == 0 {
+        c.LearningInterval = 1 * time.Hour // Fix with default
+    } else if c.LearningInterval < MinLearningInterval {
+        c.LearningInterval = MinLearningInterval
+    }
+
     // Validate retry attempts
     if c.MaxRetryAttempts < 1 {
         c.MaxRetryAttempts = 3 // Fix with default
@@ -138,7 +629,149 @@ func (c *Config) Validate() error {
     if c.LaunchDelayMs < 0 {
         c.LaunchDelayMs = 2000 // Fix with default
     }
+
+    // Validate manual restore threshold
+    if c.ManualRestoreAfter < 0 {
+        c.ManualRestoreAfter = 0 // Never mark apps manual restore only
+    }
+    if c.BlacklistDuration < 0 {
+        c.BlacklistDuration = 0 // Never blacklist apps
+    }
     
+    // Validate self-monitoring limits
+    if c.MaxMemoryMB <= 0 {
+        c.MaxMemoryMB = 150 // Fix with default
+    }
+    if c.MaxCPUPercent <= 0 {
+        c.MaxCPUPercent = 5.0 // Fix with default
+    }
+
+    if c.TriggerDebounce < 0 {
+        c.TriggerDebounce = 0 // Negative means no debounce
+    }
+
+    // Validate crash tracking
+    if c.CrashThreshold < 1 {
+        c.CrashThreshold = 3 // Fix with default
+    }
+    if c.CrashWindow <= 0 {
+        c.CrashWindow = 1 * time.Hour // Fix with default
+    }
+
+    // Validate disk thresholds
+    if c.DiskWarnPercent <= 0 || c.DiskWarnPercent > 100 {
+        c.DiskWarnPercent = 75 // Fix with default
+    }
+    if c.DiskCriticalPercent < c.DiskWarnPercent || c.DiskCriticalPercent > 100 {
+        c.DiskCriticalPercent = 90 // Fix with default
+        if c.DiskCriticalPercent < c.DiskWarnPercent {
+            c.DiskCriticalPercent = c.DiskWarnPercent
+        }
+    }
+
+    if c.MirrorMaxKBps < 0 {
+        c.MirrorMaxKBps = 0 // Unlimited
+    }
+    switch c.SyncConflicts {
+    case ConflictsKeepBoth, ConflictsNewest:
+    default:
+        c.SyncConflicts = ConflictsKeepBoth // Fix with default
+    }
+
+    // Validate maintenance battery threshold
+    if c.MaintenanceMinBattery < 0 || c.MaintenanceMinBattery > 100 {
+        c.MaintenanceMinBattery = 30 // Fix with default
+    }
+
+    // Normalize language, e.g. "ES" -> "es"; unsupported ones fall back to LANG
+    c.Language = strings.ToLower(strings.TrimSpace(c.Language))
+
+    // Validate compression level
+    if c.CompressionLevel < 1 || c.CompressionLevel > 22 {
+        c.CompressionLevel = 3 // Fix with default
+    }
+
+    // Validate notification level
+    switch c.NotificationLevel {
+    case "all", "errors", "none":
+    default:
+        c.NotificationLevel = "all" // Fix with default
+    }
+    if c.SnoozeDuration < time.Minute {
+        c.SnoozeDuration = 1 * time.Hour // Fix with default
+    }
+
+    // Validate window privacy
+    if c.WindowPrivacy == "" {
+        c.WindowPrivacy = PrivacyFull // Fix with default
+    } else if _, ok := privacyRank[c.WindowPrivacy]; !ok {
+        return fmt.Errorf("window_privacy must be none, geometry, titles or full, got %q", c.WindowPrivacy)
+    }
+
+    // Validate profiles
+    if c.Profile != "" && !validProfileName(c.Profile) {
+        return fmt.Errorf("invalid profile name %q", c.Profile)
+    }
+    if profile := os.Getenv(ProfileEnv); profile != "" && !validProfileName(profile) {
+        return fmt.Errorf("invalid profile name %q in %s", profile, ProfileEnv)
+    }
+    for i, p := range c.Profiles {
+        if !validProfileName(p.Name) {
+            return fmt.Errorf("profile at index %d has invalid name %q", i, p.Name)
+        }
+        if p.RetentionDays < 0 {
+            return fmt.Errorf("profile %s retention_days must not be negative", p.Name)
+        }
+    }
+
+    for trigger, days := range c.RetentionByTrigger {
+        if !checkpointTriggers[trigger] {
+            return fmt.Errorf("retention_by_trigger has unknown trigger %q", trigger)
+        }
+        if days < 0 {
+            return fmt.Errorf("retention_by_trigger %s must not be negative", trigger)
+        }
+    }
+
+    // Validate maintenance window
+    if err := c.MaintenanceWindow.Validate(); err != nil {
+        return fmt.Errorf("maintenance_window: %w", err)
+    }
+    if err := c.QuietHours.Validate(); err != nil {
+        return fmt.Errorf("quiet_hours: %w", err)
+    }
+
+    // Validate scheduled launches
+    for i, launch := range c.ScheduledLaunches {
+        at, err := time.Parse("15:04", launch.At)
+        if err != nil {
+            return fmt.Errorf("scheduled launch at index %d has invalid time %q, use HH:MM", i, launch.At)
+        }
+        c.ScheduledLaunches[i].At = at.Format("15:04") // e.g. "9:05" becomes "09:05"
+        for j, day := range launch.Days {
+            day = strings.ToLower(strings.TrimSpace(day))
+            if !launchDays[day] {
+                return fmt.Errorf("scheduled launch at index %d has unknown day %q, use mon..sun, daily, weekdays or weekends", i, launch.Days[j])
+            }
+            c.ScheduledLaunches[i].Days[j] = day
+        }
+        if (launch.Checkpoint == "") == (launch.Template == "") {
+            return fmt.Errorf("scheduled launch at index %d needs either a checkpoint (ID or \"latest\") or a template", i)
+        }
+    }
+
+    // Validate debug settings
+    if c.Debug.PProfAddress == "" {
+        c.Debug.PProfAddress = "127.0.0.1:7374" // Fix with default
+    }
+
+    // Validate webhooks
+    for i, hook := range c.Webhooks {
+        if !strings.HasPrefix(hook.URL, "http://") && !strings.HasPrefix(hook.URL, "https://") {
+            return fmt.Errorf("webhook at index %d needs an http(s) url", i)
+        }
+    }
+
     // Validate applications list
     if len(c.Applications) == 0 {
         return fmt.Errorf("applications list cannot be empty")
@@ -165,6 +798,17 @@ func (c *Config) Validate() error {
     
     return nil
 }
+
+// CaptureAllowed reports whether window_privacy permits storing window data of
+// the given privacy level
+func (c *Config) CaptureAllowed(level string) bool {
+    current, ok := privacyRank[c.WindowPrivacy]
+    if !ok {
+        current = privacyRank[PrivacyFull]
+    }
+    return privacyRank[level] <= current
+}
+
 // GetEnabledApplications returns only enabled applications
 func (c *Config) GetEnabledApplications() []AppConfig {
     var enabled []AppConfig